@@ -0,0 +1,97 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/go-fed/httpsig"
+	"github.com/sonatype-nexus-community/bbash/internal/db"
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+	"go.uber.org/zap"
+)
+
+// registerKeySubcommand is the os.Args[1] value that routes main() into registerKeyCmd instead of
+// starting the HTTP server - bbash has no other CLI surface, so a single hardcoded subcommand name
+// is simplest rather than pulling in a flag/subcommand framework for just this one case.
+const registerKeySubcommand = "register-key"
+
+// registerKeyCmd persists a caller's public key so internal/auth.RequireSignature can verify HTTP
+// Signatures from it (see bbash register-key -h for usage), then exits - it never starts the
+// server or the poll loop.
+func registerKeyCmd(args []string) (err error) {
+	fs := flag.NewFlagSet(registerKeySubcommand, flag.ExitOnError)
+	name := fs.String("name", "", "human-readable label for the key (e.g. the CI system it belongs to)")
+	keyId := fs.String("key-id", "", "the keyId a signed request's Signature header will present")
+	algorithm := fs.String("algorithm", string(httpsig.ED25519), "signing algorithm: ed25519 or rsa-sha256")
+	publicKeyFile := fs.String("public-key-file", "", "path to a PEM-encoded PKIX public key")
+	if err = fs.Parse(args); err != nil {
+		return
+	}
+
+	if *name == "" || *keyId == "" || *publicKeyFile == "" {
+		fs.Usage()
+		return fmt.Errorf("register-key: -name, -key-id and -public-key-file are required")
+	}
+	if httpsig.Algorithm(*algorithm) != httpsig.ED25519 && httpsig.Algorithm(*algorithm) != httpsig.RSA_SHA256 {
+		return fmt.Errorf("register-key: unsupported -algorithm %q", *algorithm)
+	}
+
+	pemBytes, err := ioutil.ReadFile(*publicKeyFile)
+	if err != nil {
+		return fmt.Errorf("register-key: reading -public-key-file: %w", err)
+	}
+
+	config := zap.NewProductionConfig()
+	cmdLogger, err := config.Build()
+	if err != nil {
+		return fmt.Errorf("register-key: can not initialize logger: %w", err)
+	}
+	defer func() {
+		_ = cmdLogger.Sync()
+	}()
+
+	pg, host, port, dbname, _, dialect, err := openDB()
+	if err != nil {
+		return fmt.Errorf("register-key: opening db: %w", err)
+	}
+	defer func() {
+		_ = pg.Close()
+	}()
+	if err = pg.Ping(); err != nil {
+		return fmt.Errorf("register-key: pinging db %s:%d/%s: %w", host, port, dbname, err)
+	}
+
+	cmdDB := db.NewWithDialect(pg, cmdLogger, dialect)
+
+	key := types.APIKeyStruct{
+		KeyId:     *keyId,
+		Name:      *name,
+		Algorithm: *algorithm,
+		PublicKey: string(pemBytes),
+		CreatedOn: time.Now(),
+	}
+	if _, err = cmdDB.InsertAPIKey(&key); err != nil {
+		return fmt.Errorf("register-key: %w", err)
+	}
+
+	fmt.Printf("registered api key %q (guid %s)\n", key.KeyId, key.Id)
+	return nil
+}
@@ -0,0 +1,116 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+// Package auditlog records who made which administrative mutation (participant create/delete,
+// score update, campaign update) to which entity, so an operator can answer "who deleted this
+// participant" without digging through the generic per-request audit_entry table auth.Audit
+// already writes. It's deliberately synchronous and best-effort: a handler calls Record after the
+// mutation it's reporting on either succeeds or fails, and a failure to persist the audit_events
+// row is logged but never turned into a response error - an admin mutation that already happened
+// shouldn't fail the caller's request because its own audit trail couldn't be written.
+package auditlog
+
+import (
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sonatype-nexus-community/bbash/internal/auth"
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+	"go.uber.org/zap"
+)
+
+// Action identifies the kind of mutation a Targets/Outcome pair was recorded for.
+const (
+	ActionParticipantCreate = "participant.create"
+	ActionParticipantDelete = "participant.delete"
+	ActionScoreUpdate       = "score.update"
+	ActionCampaignCreate    = "campaign.create"
+	ActionCampaignUpdate    = "campaign.update"
+	ActionScoringRejected   = "scoring.rejected"
+)
+
+// Store is the audit_events persistence Logger needs, a subset of db.IBBashDB.
+type Store interface {
+	InsertAuditEvent(event *types.AuditEventStruct) (guid string, err error)
+	ListAuditEvents(filter types.AuditEventFilter) (events []types.AuditEventStruct, err error)
+}
+
+// Targets names the entities a recorded mutation acted on; leave a field blank when it doesn't
+// apply to the Action being recorded (e.g. a campaign.update leaves ScpName/LoginName/
+// ParticipantUpstreamId blank).
+type Targets struct {
+	CampaignName          string
+	ScpName               string
+	LoginName             string
+	ParticipantUpstreamId string
+}
+
+// Outcome carries a mutation's result: Cause nil means success. UpstreamStatus is the HTTP status
+// an upstream.Backend call returned, when the mutation involved one (e.g. deleteParticipant's
+// upstream delete) - zero when it didn't.
+type Outcome struct {
+	Cause          error
+	UpstreamStatus int
+}
+
+// Logger records audit_events rows for administrative mutations. Construct one with NewLogger and
+// call Record from the handler performing the mutation, after it knows the outcome.
+type Logger struct {
+	store  Store
+	logger *zap.Logger
+}
+
+// NewLogger builds a Logger backed by store.
+func NewLogger(store Store, logger *zap.Logger) *Logger {
+	return &Logger{store: store, logger: logger}
+}
+
+// Record persists one audit_events row for action against targets. Actor and RequestIP come from
+// c: Actor is the auth.Principal auth.Middleware installed on the request context (empty if
+// unauthenticated, which shouldn't happen on an /admin route but is recorded rather than panicked
+// on), and RequestIP is c.RealIP(). A failure to persist is logged, not returned, so the caller
+// doesn't need to handle it.
+func (l *Logger) Record(c echo.Context, action string, targets Targets, outcome Outcome) {
+	event := &types.AuditEventStruct{
+		Action:                action,
+		CampaignName:          targets.CampaignName,
+		ScpName:               targets.ScpName,
+		LoginName:             targets.LoginName,
+		ParticipantUpstreamId: targets.ParticipantUpstreamId,
+		RequestIP:             c.RealIP(),
+		Success:               outcome.Cause == nil,
+		UpstreamStatus:        outcome.UpstreamStatus,
+		OccurredOn:            time.Now(),
+	}
+	if principal, ok := auth.PrincipalFromContext(c.Request().Context()); ok {
+		event.Actor = principal.Subject
+	}
+	if outcome.Cause != nil {
+		event.Error = outcome.Cause.Error()
+	}
+
+	if _, err := l.store.InsertAuditEvent(event); err != nil {
+		l.logger.Error("auditlog: insert audit event", zap.String("action", action), zap.Error(err))
+	}
+}
+
+// List returns every audit_events row matching filter, for the GET /admin/audit route.
+func (l *Logger) List(filter types.AuditEventFilter) (events []types.AuditEventStruct, err error) {
+	return l.store.ListAuditEvents(filter)
+}
@@ -0,0 +1,136 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package auditlog
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sonatype-nexus-community/bbash/internal/auth"
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+)
+
+type fakeStore struct {
+	inserted   []*types.AuditEventStruct
+	insertErr  error
+	listResult []types.AuditEventStruct
+	listErr    error
+}
+
+func (f *fakeStore) InsertAuditEvent(event *types.AuditEventStruct) (guid string, err error) {
+	f.inserted = append(f.inserted, event)
+	return "event-guid", f.insertErr
+}
+
+func (f *fakeStore) ListAuditEvents(filter types.AuditEventFilter) (events []types.AuditEventStruct, err error) {
+	return f.listResult, f.listErr
+}
+
+func newTestContext(t *testing.T) echo.Context {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodDelete, "/admin/campaign/c1/scp/github/login/alice", nil)
+	req.Header.Set(echo.HeaderXRealIP, "203.0.113.9")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetRequest(req.WithContext(auth.WithPrincipal(req.Context(), &auth.Principal{Subject: "alice-admin", Role: auth.RoleAdmin})))
+	return c
+}
+
+func TestLoggerRecordSuccess(t *testing.T) {
+	store := &fakeStore{}
+	l := NewLogger(store, zaptest.NewLogger(t))
+
+	l.Record(newTestContext(t), ActionParticipantDelete, Targets{
+		CampaignName: "c1",
+		ScpName:      "github",
+		LoginName:    "alice",
+	}, Outcome{})
+
+	assert.Len(t, store.inserted, 1)
+	event := store.inserted[0]
+	assert.Equal(t, "alice-admin", event.Actor)
+	assert.Equal(t, ActionParticipantDelete, event.Action)
+	assert.Equal(t, "c1", event.CampaignName)
+	assert.Equal(t, "github", event.ScpName)
+	assert.Equal(t, "alice", event.LoginName)
+	assert.Equal(t, "203.0.113.9", event.RequestIP)
+	assert.True(t, event.Success)
+	assert.Empty(t, event.Error)
+}
+
+func TestLoggerRecordFailureWithUpstreamStatus(t *testing.T) {
+	store := &fakeStore{}
+	l := NewLogger(store, zaptest.NewLogger(t))
+
+	l.Record(newTestContext(t), ActionParticipantDelete, Targets{ParticipantUpstreamId: "up-1"},
+		Outcome{Cause: assert.AnError, UpstreamStatus: http.StatusBadRequest})
+
+	assert.Len(t, store.inserted, 1)
+	event := store.inserted[0]
+	assert.False(t, event.Success)
+	assert.Equal(t, assert.AnError.Error(), event.Error)
+	assert.Equal(t, http.StatusBadRequest, event.UpstreamStatus)
+	assert.Equal(t, "up-1", event.ParticipantUpstreamId)
+}
+
+func TestLoggerRecordWithoutPrincipal(t *testing.T) {
+	store := &fakeStore{}
+	l := NewLogger(store, zaptest.NewLogger(t))
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/admin/campaign/c1", nil)
+	c := e.NewContext(req, httptest.NewRecorder())
+
+	l.Record(c, ActionCampaignUpdate, Targets{CampaignName: "c1"}, Outcome{})
+
+	assert.Len(t, store.inserted, 1)
+	assert.Empty(t, store.inserted[0].Actor)
+}
+
+func TestLoggerRecordInsertErrorIsLoggedNotReturned(t *testing.T) {
+	store := &fakeStore{insertErr: assert.AnError}
+	l := NewLogger(store, zaptest.NewLogger(t))
+
+	assert.NotPanics(t, func() {
+		l.Record(newTestContext(t), ActionScoreUpdate, Targets{}, Outcome{})
+	})
+}
+
+func TestLoggerList(t *testing.T) {
+	want := []types.AuditEventStruct{{Id: "1", Action: ActionCampaignUpdate}}
+	store := &fakeStore{listResult: want}
+	l := NewLogger(store, zaptest.NewLogger(t))
+
+	got, err := l.List(types.AuditEventFilter{Actor: "alice-admin"})
+	assert.NoError(t, err)
+	assert.Equal(t, want, got)
+}
+
+func TestLoggerListError(t *testing.T) {
+	store := &fakeStore{listErr: assert.AnError}
+	l := NewLogger(store, zaptest.NewLogger(t))
+
+	_, err := l.List(types.AuditEventFilter{})
+	assert.EqualError(t, err, assert.AnError.Error())
+}
@@ -0,0 +1,86 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+// Package auth provides a pluggable replacement for server.go's single shared-credential
+// BasicAuth: an Authenticator abstracts over BasicAuth (the historical default), OIDC/JWT bearer
+// tokens, and hashed static API tokens, each resolving the caller to a Principal carrying a Role.
+// Middleware (see middleware.go) then enforces a minimum Role per route.
+package auth
+
+import (
+	"context"
+	"net/http"
+)
+
+// Role is a caller's permission level, ordered by rank() below. A Principal's Role is the ceiling
+// on what it may do; RequireRole rejects anything ranked lower than the route's minimum.
+type Role string
+
+const (
+	RoleReadonly        Role = "readonly"
+	RoleCampaignManager Role = "campaign-manager"
+	RoleAdmin           Role = "admin"
+)
+
+// rank orders Roles from least to most privileged; an unrecognized Role ranks below RoleReadonly
+// so it satisfies nothing.
+func rank(r Role) int {
+	switch r {
+	case RoleReadonly:
+		return 1
+	case RoleCampaignManager:
+		return 2
+	case RoleAdmin:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// Satisfies reports whether r meets or exceeds the privilege of min.
+func (r Role) Satisfies(min Role) bool {
+	return rank(r) >= rank(min)
+}
+
+// Principal is the authenticated caller a successful Authenticate resolves a request to.
+type Principal struct {
+	// Subject identifies the caller for audit purposes: the configured admin username for
+	// BasicAuthenticator, the JWT's "sub" claim for OIDCAuthenticator, or the token's Name for
+	// TokenAuthenticator.
+	Subject string
+	Role    Role
+}
+
+// Authenticator resolves an incoming request to a Principal, or returns an error if it can't.
+type Authenticator interface {
+	Authenticate(r *http.Request) (*Principal, error)
+}
+
+type principalContextKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying principal, retrievable via PrincipalFromContext.
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalContextKey{}, principal)
+}
+
+// PrincipalFromContext returns the Principal Middleware stored on ctx, if any.
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalContextKey{}).(*Principal)
+	return principal, ok
+}
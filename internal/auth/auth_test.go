@@ -0,0 +1,54 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package auth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoleSatisfies(t *testing.T) {
+	assert.True(t, RoleAdmin.Satisfies(RoleReadonly))
+	assert.True(t, RoleAdmin.Satisfies(RoleCampaignManager))
+	assert.True(t, RoleAdmin.Satisfies(RoleAdmin))
+	assert.True(t, RoleCampaignManager.Satisfies(RoleReadonly))
+	assert.False(t, RoleCampaignManager.Satisfies(RoleAdmin))
+	assert.False(t, RoleReadonly.Satisfies(RoleCampaignManager))
+}
+
+func TestRoleSatisfiesUnrecognizedRoleSatisfiesNothing(t *testing.T) {
+	assert.False(t, Role("bogus").Satisfies(RoleReadonly))
+}
+
+func TestPrincipalFromContextRoundTrips(t *testing.T) {
+	principal := &Principal{Subject: "alice", Role: RoleAdmin}
+	ctx := WithPrincipal(context.Background(), principal)
+
+	got, ok := PrincipalFromContext(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, principal, got)
+}
+
+func TestPrincipalFromContextMissing(t *testing.T) {
+	_, ok := PrincipalFromContext(context.Background())
+	assert.False(t, ok)
+}
@@ -0,0 +1,58 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package auth
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+)
+
+// BasicAuthenticator is the historical default: a single shared admin username/password, checked
+// in constant time to avoid timing attacks, resolving to RoleAdmin on success. It supersedes
+// server.go's former infoBasicValidator/middleware.BasicAuth wiring without changing its behavior.
+type BasicAuthenticator struct {
+	Username string
+	Password string
+}
+
+var _ Authenticator = (*BasicAuthenticator)(nil)
+
+// NewBasicAuthenticator builds a BasicAuthenticator checking requests against username/password.
+func NewBasicAuthenticator(username, password string) *BasicAuthenticator {
+	return &BasicAuthenticator{Username: username, Password: password}
+}
+
+// ErrInvalidCredentials is returned by Authenticate when no configured Authenticator accepts the
+// request's credentials.
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+func (a *BasicAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	username, password, ok := r.BasicAuth()
+	if !ok {
+		return nil, ErrInvalidCredentials
+	}
+
+	if subtle.ConstantTimeCompare([]byte(username), []byte(a.Username)) == 1 &&
+		subtle.ConstantTimeCompare([]byte(password), []byte(a.Password)) == 1 {
+		return &Principal{Subject: username, Role: RoleAdmin}, nil
+	}
+	return nil, ErrInvalidCredentials
+}
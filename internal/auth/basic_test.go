@@ -0,0 +1,58 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package auth
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBasicAuthenticatorAcceptsCorrectCredentials(t *testing.T) {
+	a := NewBasicAuthenticator("admin", "s3cr3t")
+
+	r := httptest.NewRequest("GET", "/admin/scp/list", nil)
+	r.SetBasicAuth("admin", "s3cr3t")
+
+	principal, err := a.Authenticate(r)
+	assert.NoError(t, err)
+	assert.Equal(t, "admin", principal.Subject)
+	assert.Equal(t, RoleAdmin, principal.Role)
+}
+
+func TestBasicAuthenticatorRejectsWrongCredentials(t *testing.T) {
+	a := NewBasicAuthenticator("admin", "s3cr3t")
+
+	r := httptest.NewRequest("GET", "/admin/scp/list", nil)
+	r.SetBasicAuth("admin", "wrong")
+
+	_, err := a.Authenticate(r)
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+}
+
+func TestBasicAuthenticatorRejectsMissingCredentials(t *testing.T) {
+	a := NewBasicAuthenticator("admin", "s3cr3t")
+
+	r := httptest.NewRequest("GET", "/admin/scp/list", nil)
+
+	_, err := a.Authenticate(r)
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+}
@@ -0,0 +1,178 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package auth
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/go-fed/httpsig"
+	"github.com/labstack/echo/v4"
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+	"golang.org/x/crypto/ed25519"
+)
+
+// MaxClockSkew is how far apart a signed request's Date header may drift from wall clock time
+// before RequireSignature rejects it as stale, bounding how long a captured request stays replayable.
+const MaxClockSkew = 5 * time.Minute
+
+// ErrStaleSignature is returned when a signed request's Date header is further than MaxClockSkew
+// from now, in either direction.
+var ErrStaleSignature = errors.New("signed request's Date header is stale")
+
+// ErrUnknownKeyId is returned when a request's Signature keyId doesn't match any registered,
+// un-revoked APIKeyStruct.
+var ErrUnknownKeyId = errors.New("unknown signing key")
+
+// ErrDigestMismatch is returned when a request's Digest header doesn't match the SHA-256 digest of
+// its actual body.
+var ErrDigestMismatch = errors.New("digest header does not match request body")
+
+// KeyStore is the slice of db.IBBashDB RequireSignature needs to resolve a Signature header's
+// keyId to a registered public key.
+type KeyStore interface {
+	GetAPIKeyByKeyID(keyId string) (key *types.APIKeyStruct, err error)
+}
+
+// RequireSignature is an optional, additional middleware layered on top of Middleware/RequireRole
+// for routes that external automation (e.g. a GitHub Actions job signing a bug-import call) needs
+// to prove origin for, on top of whatever Role their bearer credential already grants. It verifies
+// an ActivityPub-style HTTP Signature (https://github.com/go-fed/httpsig, the (request-target),
+// host, date and digest headers) against a per-caller public key in store, and rejects anything
+// whose Date header has drifted more than MaxClockSkew from now.
+func RequireSignature(store KeyStore) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			r := c.Request()
+
+			dateHeader := r.Header.Get("Date")
+			if dateHeader == "" {
+				return echo.NewHTTPError(http.StatusUnauthorized, "missing Date header")
+			}
+			signedAt, err := http.ParseTime(dateHeader)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusUnauthorized, "malformed Date header")
+			}
+			if skew := time.Since(signedAt); skew > MaxClockSkew || skew < -MaxClockSkew {
+				return echo.NewHTTPError(http.StatusUnauthorized, ErrStaleSignature.Error())
+			}
+
+			body, err := ioutil.ReadAll(r.Body)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "reading request body")
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			if err = verifyDigest(r.Header.Get("Digest"), body); err != nil {
+				return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+			}
+
+			verifier, err := httpsig.NewVerifier(r)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusUnauthorized, fmt.Sprintf("parsing Signature header: %v", err))
+			}
+
+			key, err := store.GetAPIKeyByKeyID(verifier.KeyId())
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("looking up api key: %v", err))
+			}
+			if key == nil {
+				return echo.NewHTTPError(http.StatusUnauthorized, ErrUnknownKeyId.Error())
+			}
+
+			pubKey, err := parsePublicKey(key.Algorithm, key.PublicKey)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("parsing stored api key: %v", err))
+			}
+
+			if err = verifier.Verify(pubKey, httpsig.Algorithm(key.Algorithm)); err != nil {
+				return echo.NewHTTPError(http.StatusUnauthorized, fmt.Sprintf("signature verification failed: %v", err))
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// verifyDigest reports an error unless digestHeader (a "SHA-256=<base64>" Digest header, per
+// RFC 3230) matches the SHA-256 digest of body - without this check, httpsig.Verifier alone would
+// only prove the signed headers are untampered, not that Digest (and therefore the body it
+// describes) is the one the signer actually saw.
+func verifyDigest(digestHeader string, body []byte) error {
+	const prefix = "SHA-256="
+	if digestHeader == "" {
+		return errors.New("missing Digest header")
+	}
+	if len(digestHeader) <= len(prefix) || digestHeader[:len(prefix)] != prefix {
+		return fmt.Errorf("unsupported Digest header %q", digestHeader)
+	}
+	want, err := base64.StdEncoding.DecodeString(digestHeader[len(prefix):])
+	if err != nil {
+		return fmt.Errorf("malformed Digest header: %w", err)
+	}
+	got := sha256.Sum256(body)
+	if !bytes.Equal(want, got[:]) {
+		return ErrDigestMismatch
+	}
+	return nil
+}
+
+// parsePublicKey decodes a PEM-encoded key stored as APIKeyStruct.PublicKey, according to the
+// algorithm it was registered under (see the register-key CLI subcommand).
+func parsePublicKey(algorithm, pemKey string) (crypto.PublicKey, error) {
+	block, _ := pem.Decode([]byte(pemKey))
+	if block == nil {
+		return nil, errors.New("no PEM block found in stored public key")
+	}
+
+	switch httpsig.Algorithm(algorithm) {
+	case httpsig.ED25519:
+		pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		key, ok := pub.(ed25519.PublicKey)
+		if !ok {
+			return nil, errors.New("stored key is not an Ed25519 public key")
+		}
+		return key, nil
+	default:
+		pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		key, ok := pub.(*rsa.PublicKey)
+		if !ok {
+			return nil, errors.New("stored key is not an RSA public key")
+		}
+		return key, nil
+	}
+}
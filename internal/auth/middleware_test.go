@@ -0,0 +1,129 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeAuditStore struct {
+	entries []*types.AuditEntryStruct
+}
+
+func (f *fakeAuditStore) InsertAuditEntry(entry *types.AuditEntryStruct) (guid string, err error) {
+	f.entries = append(f.entries, entry)
+	return
+}
+
+func okHandler(c echo.Context) error {
+	return c.String(http.StatusOK, "ok")
+}
+
+func TestMiddlewareRejectsFailedAuthentication(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/admin/bug/list", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := Middleware(NewBasicAuthenticator("admin", "s3cr3t"))(okHandler)(c)
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusUnauthorized, httpErr.Code)
+}
+
+func TestMiddlewareStoresPrincipalOnSuccess(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/admin/bug/list", nil)
+	req.SetBasicAuth("admin", "s3cr3t")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	var seen *Principal
+	handler := func(c echo.Context) error {
+		seen, _ = PrincipalFromContext(c.Request().Context())
+		return okHandler(c)
+	}
+
+	err := Middleware(NewBasicAuthenticator("admin", "s3cr3t"))(handler)(c)
+	assert.NoError(t, err)
+	assert.Equal(t, RoleAdmin, seen.Role)
+}
+
+func TestRequireRoleAllowsSufficientRole(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/admin/bug/list", nil)
+	req = req.WithContext(WithPrincipal(req.Context(), &Principal{Subject: "alice", Role: RoleCampaignManager}))
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := RequireRole(RoleReadonly)(okHandler)(c)
+	assert.NoError(t, err)
+}
+
+func TestRequireRoleRejectsInsufficientRole(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/admin/bug/list", nil)
+	req = req.WithContext(WithPrincipal(req.Context(), &Principal{Subject: "alice", Role: RoleReadonly}))
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := RequireRole(RoleAdmin)(okHandler)(c)
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusForbidden, httpErr.Code)
+}
+
+func TestRequireRoleRejectsMissingPrincipal(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/admin/bug/list", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := RequireRole(RoleReadonly)(okHandler)(c)
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusUnauthorized, httpErr.Code)
+}
+
+func TestAuditPersistsOneEntryPerCall(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/admin/bug/update", nil)
+	req = req.WithContext(WithPrincipal(req.Context(), &Principal{Subject: "alice", Role: RoleCampaignManager}))
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath("/admin/bug/update")
+
+	store := &fakeAuditStore{}
+	err := Audit(store)(okHandler)(c)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, len(store.entries))
+	assert.Equal(t, "alice", store.entries[0].Subject)
+	assert.Equal(t, string(RoleCampaignManager), store.entries[0].Role)
+	assert.Equal(t, http.MethodPost, store.entries[0].Method)
+	assert.Equal(t, "/admin/bug/update", store.entries[0].Path)
+	assert.Equal(t, http.StatusOK, store.entries[0].StatusCode)
+}
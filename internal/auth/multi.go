@@ -0,0 +1,56 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package auth
+
+import "net/http"
+
+// MultiAuthenticator lets more than one Authenticator be enabled on the same route at once,
+// instead of newAuthenticator's previous all-or-nothing envAuthMode switch. Each candidate already
+// rejects requests with ErrInvalidCredentials when the request's Authorization scheme isn't its
+// own (BasicAuthenticator wants "Basic", TokenAuthenticator and OIDCAuthenticator want "Bearer"),
+// so trying them in order amounts to dispatching on scheme - two Bearer-style providers (OIDC and
+// a static API token) can coexist because whichever one doesn't recognize the token simply falls
+// through to the next.
+type MultiAuthenticator struct {
+	Providers []Authenticator
+}
+
+var _ Authenticator = (*MultiAuthenticator)(nil)
+
+// NewMultiAuthenticator builds a MultiAuthenticator trying providers in order, returning the first
+// Principal any of them resolves.
+func NewMultiAuthenticator(providers ...Authenticator) *MultiAuthenticator {
+	return &MultiAuthenticator{Providers: providers}
+}
+
+func (a *MultiAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	var err error
+	for _, provider := range a.Providers {
+		var principal *Principal
+		principal, err = provider.Authenticate(r)
+		if err == nil {
+			return principal, nil
+		}
+	}
+	if err == nil {
+		err = ErrInvalidCredentials
+	}
+	return nil, err
+}
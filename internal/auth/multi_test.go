@@ -0,0 +1,76 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubAuthenticator struct {
+	principal *Principal
+	err       error
+}
+
+func (s *stubAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	return s.principal, s.err
+}
+
+func TestMultiAuthenticatorReturnsFirstAccepting(t *testing.T) {
+	basic := NewBasicAuthenticator("admin", "s3cr3t")
+	token := &stubAuthenticator{principal: &Principal{Subject: "ci-bot", Role: RoleCampaignManager}}
+	a := NewMultiAuthenticator(basic, token)
+
+	r := httptest.NewRequest("GET", "/admin/scp/list", nil)
+	r.Header.Set("Authorization", "Bearer whatever-token")
+
+	principal, err := a.Authenticate(r)
+	assert.NoError(t, err)
+	assert.Equal(t, "ci-bot", principal.Subject)
+	assert.Equal(t, RoleCampaignManager, principal.Role)
+}
+
+func TestMultiAuthenticatorPrefersEarlierProvider(t *testing.T) {
+	basic := NewBasicAuthenticator("admin", "s3cr3t")
+	token := &stubAuthenticator{principal: &Principal{Subject: "ci-bot", Role: RoleCampaignManager}}
+	a := NewMultiAuthenticator(basic, token)
+
+	r := httptest.NewRequest("GET", "/admin/scp/list", nil)
+	r.SetBasicAuth("admin", "s3cr3t")
+
+	principal, err := a.Authenticate(r)
+	assert.NoError(t, err)
+	assert.Equal(t, "admin", principal.Subject)
+	assert.Equal(t, RoleAdmin, principal.Role)
+}
+
+func TestMultiAuthenticatorRejectsWhenNoProviderAccepts(t *testing.T) {
+	basic := NewBasicAuthenticator("admin", "s3cr3t")
+	token := &stubAuthenticator{err: ErrInvalidCredentials}
+	a := NewMultiAuthenticator(basic, token)
+
+	r := httptest.NewRequest("GET", "/admin/scp/list", nil)
+
+	_, err := a.Authenticate(r)
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+}
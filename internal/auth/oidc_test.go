@@ -0,0 +1,171 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package auth
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// signTestJWT builds a minimal RS256 JWT for claims, signed by key and advertised under kid - just
+// enough of the JOSE spec for OIDCAuthenticator.Authenticate to validate, mirroring the hand-rolled
+// RESP test helpers in internal/poll/source_redis_stream_test.go.
+func signTestJWT(t *testing.T, key *rsa.PrivateKey, kid string, claims map[string]interface{}) string {
+	header := map[string]interface{}{"alg": "RS256", "kid": kid, "typ": "JWT"}
+	headerJSON, err := json.Marshal(header)
+	assert.NoError(t, err)
+	claimsJSON, err := json.Marshal(claims)
+	assert.NoError(t, err)
+
+	signingInput := base64.RawURLEncoding.EncodeToString(headerJSON) + "." +
+		base64.RawURLEncoding.EncodeToString(claimsJSON)
+
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	assert.NoError(t, err)
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func newTestJWKSServer(t *testing.T, key *rsa.PrivateKey, kid string) *httptest.Server {
+	n := base64.RawURLEncoding.EncodeToString(key.PublicKey.N.Bytes())
+	e := base64.RawURLEncoding.EncodeToString(big64(key.PublicKey.E))
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(jwkSet{Keys: []jwk{{Kid: kid, Kty: "RSA", N: n, E: e}}})
+	}))
+}
+
+// big64 big-endian encodes a small int (an RSA exponent, e.g. 65537) the same way a JWKS "e" field
+// does, trimming leading zero bytes the way math/big.Int.Bytes() leaves none.
+func big64(v int) []byte {
+	b := []byte{byte(v >> 16), byte(v >> 8), byte(v)}
+	for len(b) > 1 && b[0] == 0 {
+		b = b[1:]
+	}
+	return b
+}
+
+func TestOIDCAuthenticatorAcceptsValidToken(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	server := newTestJWKSServer(t, key, "kid-1")
+	defer server.Close()
+
+	a := NewOIDCAuthenticator("https://issuer.example", "bbash", server.URL)
+
+	token := signTestJWT(t, key, "kid-1", map[string]interface{}{
+		"iss":        "https://issuer.example",
+		"aud":        "bbash",
+		"sub":        "alice",
+		"bbash_role": string(RoleCampaignManager),
+		"exp":        float64(time.Now().Add(time.Hour).Unix()),
+	})
+
+	r := httptest.NewRequest("GET", "/admin/bug/list", nil)
+	r.Header.Set("Authorization", "Bearer "+token)
+
+	principal, err := a.Authenticate(r)
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", principal.Subject)
+	assert.Equal(t, RoleCampaignManager, principal.Role)
+}
+
+func TestOIDCAuthenticatorRejectsWrongIssuer(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	server := newTestJWKSServer(t, key, "kid-1")
+	defer server.Close()
+
+	a := NewOIDCAuthenticator("https://issuer.example", "bbash", server.URL)
+
+	token := signTestJWT(t, key, "kid-1", map[string]interface{}{
+		"iss": "https://someone-else.example",
+		"aud": "bbash",
+		"sub": "alice",
+		"exp": float64(time.Now().Add(time.Hour).Unix()),
+	})
+
+	r := httptest.NewRequest("GET", "/admin/bug/list", nil)
+	r.Header.Set("Authorization", "Bearer "+token)
+
+	_, err = a.Authenticate(r)
+	assert.Error(t, err)
+}
+
+func TestOIDCAuthenticatorRejectsExpiredToken(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	server := newTestJWKSServer(t, key, "kid-1")
+	defer server.Close()
+
+	a := NewOIDCAuthenticator("https://issuer.example", "bbash", server.URL)
+
+	token := signTestJWT(t, key, "kid-1", map[string]interface{}{
+		"iss": "https://issuer.example",
+		"aud": "bbash",
+		"sub": "alice",
+		"exp": float64(time.Now().Add(-time.Hour).Unix()),
+	})
+
+	r := httptest.NewRequest("GET", "/admin/bug/list", nil)
+	r.Header.Set("Authorization", "Bearer "+token)
+
+	_, err = a.Authenticate(r)
+	assert.Error(t, err)
+}
+
+func TestOIDCAuthenticatorRejectsTamperedSignature(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	server := newTestJWKSServer(t, key, "kid-1")
+	defer server.Close()
+
+	a := NewOIDCAuthenticator("https://issuer.example", "bbash", server.URL)
+
+	token := signTestJWT(t, otherKey, "kid-1", map[string]interface{}{
+		"iss": "https://issuer.example",
+		"aud": "bbash",
+		"sub": "alice",
+		"exp": float64(time.Now().Add(time.Hour).Unix()),
+	})
+
+	r := httptest.NewRequest("GET", "/admin/bug/list", nil)
+	r.Header.Set("Authorization", "Bearer "+token)
+
+	_, err = a.Authenticate(r)
+	assert.Error(t, err)
+}
@@ -0,0 +1,152 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package auth
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Scheme names a permission a principal may hold over some Context, e.g. "campaign.admin".
+// RequireRole's Role is a coarse ceiling on what a Principal may ever do; Scheme/Context refine
+// that down to which specific campaign, team, or source control provider a Principal may act on.
+type Scheme string
+
+const (
+	SchemeCampaignAdmin     Scheme = "campaign.admin"
+	SchemeParticipantDelete Scheme = "participant.delete"
+	SchemeScoreOverride     Scheme = "score.override"
+)
+
+// ContextKind discriminates which field of a Context is populated.
+type ContextKind string
+
+const (
+	KindGlobal   ContextKind = "global"
+	KindCampaign ContextKind = "campaign"
+	KindTeam     ContextKind = "team"
+	KindSCP      ContextKind = "scp"
+)
+
+// Context scopes a Grant to part of bbash's data: every campaign (CtxGlobal), one campaign
+// (CtxCampaign), one team within a campaign (CtxTeam), or one source control provider (CtxSCP).
+type Context struct {
+	Kind     ContextKind
+	Campaign string
+	Team     string
+	SCP      string
+}
+
+// CtxGlobal is the Context a Grant needs to act on anything, regardless of campaign, team, or SCP.
+func CtxGlobal() Context { return Context{Kind: KindGlobal} }
+
+// CtxCampaign is the Context a Grant needs to act on campaign, or any team within it.
+func CtxCampaign(campaign string) Context { return Context{Kind: KindCampaign, Campaign: campaign} }
+
+// CtxTeam is the Context a Grant needs to act on team within campaign specifically.
+func CtxTeam(campaign, team string) Context {
+	return Context{Kind: KindTeam, Campaign: campaign, Team: team}
+}
+
+// CtxSCP is the Context a Grant needs to act on scpName specifically.
+func CtxSCP(scpName string) Context { return Context{Kind: KindSCP, SCP: scpName} }
+
+// Covers reports whether granted - the scope a Grant was given over - is broad enough to satisfy
+// requested - the scope a specific request needs. CtxGlobal covers everything; a CtxCampaign
+// grant also covers CtxTeam requests for teams within that same campaign; every other combination
+// must match exactly.
+func (granted Context) Covers(requested Context) bool {
+	if granted.Kind == KindGlobal {
+		return true
+	}
+	if granted.Kind == KindCampaign && requested.Kind == KindTeam {
+		return granted.Campaign == requested.Campaign
+	}
+	return granted == requested
+}
+
+// Grant records that Subject holds Scheme over Context - e.g. a team captain's
+// SchemeParticipantDelete grant scoped to CtxTeam("bug-bash-2024", "ocelots") rather than the
+// whole campaign.
+type Grant struct {
+	Subject string
+	Scheme  Scheme
+	Context Context
+}
+
+// PermissionStore resolves the Grants a principal holds. No permission_grant table exists in
+// this snapshot's migrations yet, so a Store is typically a small in-memory StaticPermissionStore
+// until persistence lands - the same gap CampaignStruct.UpstreamId documents for campaign rows.
+type PermissionStore interface {
+	GrantsForSubject(subject string) (grants []Grant, err error)
+}
+
+// StaticPermissionStore is a PermissionStore backed by a fixed, in-process grant list.
+type StaticPermissionStore struct {
+	Grants []Grant
+}
+
+func (s StaticPermissionStore) GrantsForSubject(subject string) (grants []Grant, err error) {
+	for _, grant := range s.Grants {
+		if grant.Subject == subject {
+			grants = append(grants, grant)
+		}
+	}
+	return
+}
+
+// ContextResolver derives the Context a request needs permission over, e.g. reading a
+// :campaignName/:teamName path param pair for a team-scoped route.
+type ContextResolver func(c echo.Context) Context
+
+// RequirePermission 403s any request whose Principal doesn't hold scheme over a Context that
+// Covers resolveContext(c) - e.g. a team captain's SchemeParticipantDelete grant only covers
+// deleting participants on their own team. A RoleAdmin Principal always passes, the same
+// all-or-nothing ceiling RequireRole already gives it; RequirePermission only narrows what
+// lower-ranked Roles may do within the scope RequireRole already let them past. It must run after
+// Middleware, so a Principal is already on the request context.
+func RequirePermission(store PermissionStore, scheme Scheme, resolveContext ContextResolver) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			principal, ok := PrincipalFromContext(c.Request().Context())
+			if !ok {
+				return echo.NewHTTPError(http.StatusUnauthorized, "no authenticated principal")
+			}
+			if principal.Role == RoleAdmin {
+				return next(c)
+			}
+
+			grants, err := store.GrantsForSubject(principal.Subject)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+			}
+
+			requested := resolveContext(c)
+			for _, grant := range grants {
+				if grant.Scheme == scheme && grant.Context.Covers(requested) {
+					return next(c)
+				}
+			}
+
+			return echo.NewHTTPError(http.StatusForbidden, "insufficient permission")
+		}
+	}
+}
@@ -0,0 +1,143 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContextCoversGlobalCoversEverything(t *testing.T) {
+	assert.True(t, CtxGlobal().Covers(CtxCampaign("bash24")))
+	assert.True(t, CtxGlobal().Covers(CtxTeam("bash24", "ocelots")))
+	assert.True(t, CtxGlobal().Covers(CtxSCP("github")))
+}
+
+func TestContextCoversCampaignCoversItsOwnTeams(t *testing.T) {
+	assert.True(t, CtxCampaign("bash24").Covers(CtxTeam("bash24", "ocelots")))
+	assert.False(t, CtxCampaign("bash24").Covers(CtxTeam("bash25", "ocelots")))
+	assert.False(t, CtxCampaign("bash24").Covers(CtxCampaign("bash25")))
+}
+
+func TestContextCoversTeamOnlyMatchesExactly(t *testing.T) {
+	assert.True(t, CtxTeam("bash24", "ocelots").Covers(CtxTeam("bash24", "ocelots")))
+	assert.False(t, CtxTeam("bash24", "ocelots").Covers(CtxTeam("bash24", "foxes")))
+	assert.False(t, CtxTeam("bash24", "ocelots").Covers(CtxCampaign("bash24")))
+}
+
+func TestStaticPermissionStoreFiltersBySubject(t *testing.T) {
+	store := StaticPermissionStore{Grants: []Grant{
+		{Subject: "alice", Scheme: SchemeParticipantDelete, Context: CtxTeam("bash24", "ocelots")},
+		{Subject: "bob", Scheme: SchemeParticipantDelete, Context: CtxGlobal()},
+	}}
+
+	grants, err := store.GrantsForSubject("alice")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(grants))
+	assert.Equal(t, "alice", grants[0].Subject)
+}
+
+func TestRequirePermissionAllowsAdminWithoutAGrant(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodDelete, "/admin/participant/delete", nil)
+	req = req.WithContext(WithPrincipal(req.Context(), &Principal{Subject: "root", Role: RoleAdmin}))
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	store := StaticPermissionStore{}
+	resolve := func(echo.Context) Context { return CtxTeam("bash24", "ocelots") }
+
+	err := RequirePermission(store, SchemeParticipantDelete, resolve)(okHandler)(c)
+	assert.NoError(t, err)
+}
+
+func TestRequirePermissionAllowsAGrantCoveringTheRequestedContext(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodDelete, "/admin/participant/delete", nil)
+	req = req.WithContext(WithPrincipal(req.Context(), &Principal{Subject: "alice", Role: RoleReadonly}))
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	store := StaticPermissionStore{Grants: []Grant{
+		{Subject: "alice", Scheme: SchemeParticipantDelete, Context: CtxTeam("bash24", "ocelots")},
+	}}
+	resolve := func(echo.Context) Context { return CtxTeam("bash24", "ocelots") }
+
+	err := RequirePermission(store, SchemeParticipantDelete, resolve)(okHandler)(c)
+	assert.NoError(t, err)
+}
+
+func TestRequirePermissionRejectsAGrantForAnotherTeam(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodDelete, "/admin/participant/delete", nil)
+	req = req.WithContext(WithPrincipal(req.Context(), &Principal{Subject: "alice", Role: RoleReadonly}))
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	store := StaticPermissionStore{Grants: []Grant{
+		{Subject: "alice", Scheme: SchemeParticipantDelete, Context: CtxTeam("bash24", "foxes")},
+	}}
+	resolve := func(echo.Context) Context { return CtxTeam("bash24", "ocelots") }
+
+	err := RequirePermission(store, SchemeParticipantDelete, resolve)(okHandler)(c)
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusForbidden, httpErr.Code)
+}
+
+func TestRequirePermissionRejectsMissingPrincipal(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodDelete, "/admin/participant/delete", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	store := StaticPermissionStore{}
+	resolve := func(echo.Context) Context { return CtxGlobal() }
+
+	err := RequirePermission(store, SchemeParticipantDelete, resolve)(okHandler)(c)
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusUnauthorized, httpErr.Code)
+}
+
+type erroringPermissionStore struct{}
+
+func (erroringPermissionStore) GrantsForSubject(string) ([]Grant, error) {
+	return nil, assert.AnError
+}
+
+func TestRequirePermissionPropagatesStoreError(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodDelete, "/admin/participant/delete", nil)
+	req = req.WithContext(WithPrincipal(req.Context(), &Principal{Subject: "alice", Role: RoleReadonly}))
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	resolve := func(echo.Context) Context { return CtxGlobal() }
+
+	err := RequirePermission(erroringPermissionStore{}, SchemeParticipantDelete, resolve)(okHandler)(c)
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusInternalServerError, httpErr.Code)
+}
@@ -0,0 +1,90 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+)
+
+// TokenStore is the slice of db.IBBashDB TokenAuthenticator needs; db.BBashDB already implements
+// it, so callers pass their existing postgresDB straight through.
+type TokenStore interface {
+	GetAuthTokenByHash(tokenHash string) (token *types.AuthTokenStruct, err error)
+}
+
+// TokenAuthenticator validates a static API token, presented as an "Authorization: Bearer <token>"
+// header, against tokens stored hashed (see HashToken) in store.
+type TokenAuthenticator struct {
+	Store TokenStore
+}
+
+var _ Authenticator = (*TokenAuthenticator)(nil)
+
+// NewTokenAuthenticator builds a TokenAuthenticator validating bearer tokens against store.
+func NewTokenAuthenticator(store TokenStore) *TokenAuthenticator {
+	return &TokenAuthenticator{Store: store}
+}
+
+// HashToken returns the hex-encoded SHA-256 digest of token, the form tokens are persisted and
+// looked up by - the raw token is only ever held in memory, on issuance, and by the caller it was
+// issued to.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// GenerateToken returns a new random, URL-safe API token suitable for issuing via /admin/tokens/add.
+func GenerateToken() (token string, err error) {
+	raw := make([]byte, 32)
+	if _, err = rand.Read(raw); err != nil {
+		return
+	}
+	token = base64.RawURLEncoding.EncodeToString(raw)
+	return
+}
+
+func (a *TokenAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	header := r.Header.Get("Authorization")
+	const prefix = "Bearer "
+	if !strings.HasPrefix(header, prefix) {
+		return nil, ErrInvalidCredentials
+	}
+	raw := strings.TrimPrefix(header, prefix)
+	if raw == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	token, err := a.Store.GetAuthTokenByHash(HashToken(raw))
+	if err != nil {
+		return nil, fmt.Errorf("looking up auth token: %w", err)
+	}
+	if token == nil {
+		return nil, ErrInvalidCredentials
+	}
+	return &Principal{Subject: token.Name, Role: Role(token.Role)}, nil
+}
@@ -0,0 +1,83 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package auth
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeTokenStore struct {
+	tokensByHash map[string]*types.AuthTokenStruct
+}
+
+func (f *fakeTokenStore) GetAuthTokenByHash(tokenHash string) (*types.AuthTokenStruct, error) {
+	return f.tokensByHash[tokenHash], nil
+}
+
+func TestTokenAuthenticatorAcceptsValidToken(t *testing.T) {
+	rawToken, err := GenerateToken()
+	assert.NoError(t, err)
+
+	store := &fakeTokenStore{tokensByHash: map[string]*types.AuthTokenStruct{
+		HashToken(rawToken): {Name: "ci-bot", Role: string(RoleCampaignManager)},
+	}}
+	a := NewTokenAuthenticator(store)
+
+	r := httptest.NewRequest("GET", "/admin/bug/list", nil)
+	r.Header.Set("Authorization", "Bearer "+rawToken)
+
+	principal, err := a.Authenticate(r)
+	assert.NoError(t, err)
+	assert.Equal(t, "ci-bot", principal.Subject)
+	assert.Equal(t, RoleCampaignManager, principal.Role)
+}
+
+func TestTokenAuthenticatorRejectsUnknownToken(t *testing.T) {
+	store := &fakeTokenStore{tokensByHash: map[string]*types.AuthTokenStruct{}}
+	a := NewTokenAuthenticator(store)
+
+	r := httptest.NewRequest("GET", "/admin/bug/list", nil)
+	r.Header.Set("Authorization", "Bearer nope")
+
+	_, err := a.Authenticate(r)
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+}
+
+func TestTokenAuthenticatorRejectsMissingBearerPrefix(t *testing.T) {
+	store := &fakeTokenStore{tokensByHash: map[string]*types.AuthTokenStruct{}}
+	a := NewTokenAuthenticator(store)
+
+	r := httptest.NewRequest("GET", "/admin/bug/list", nil)
+	r.Header.Set("Authorization", "nope")
+
+	_, err := a.Authenticate(r)
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+}
+
+func TestHashTokenIsDeterministicAndDoesNotLeakTheRawToken(t *testing.T) {
+	h1 := HashToken("my-token")
+	h2 := HashToken("my-token")
+	assert.Equal(t, h1, h2)
+	assert.NotContains(t, h1, "my-token")
+}
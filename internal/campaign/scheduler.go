@@ -0,0 +1,125 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package campaign
+
+import (
+	"context"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+	"go.uber.org/zap"
+)
+
+// Store is the campaign persistence Scheduler needs: enough of db.IBBashDB to list campaigns and
+// move one into a new State, without depending on the db package directly.
+type Store interface {
+	GetCampaigns() (campaigns []types.CampaignStruct, err error)
+	UpdateCampaignState(name string, state string) (err error)
+}
+
+// TransitionHook is called once per automatic transition a Scheduler tick makes, after the new
+// State is already persisted. server.go uses this to recompute the leaderboard and fire the
+// configured webhook, keeping this package ignorant of internal/graph and HTTP specifics.
+type TransitionHook func(campaignName string, from, to State)
+
+// Scheduler periodically moves scheduled campaigns to active at their StartOn, and active
+// campaigns to ended at their EndOn - the automatic half of the lifecycle; pause/resume are the
+// manual half, driven directly by the /campaign/:name/pause and /resume routes.
+type Scheduler struct {
+	store  Store
+	hook   TransitionHook
+	logger *zap.Logger
+	cron   *cron.Cron
+}
+
+// NewScheduler builds a Scheduler; call Start to begin running it.
+func NewScheduler(store Store, hook TransitionHook, logger *zap.Logger) *Scheduler {
+	return &Scheduler{store: store, hook: hook, logger: logger}
+}
+
+// Start begins running a tick on the given cron spec (e.g. "@every 1m") until Stop is called.
+func (s *Scheduler) Start(spec string) (err error) {
+	s.cron = cron.New()
+	if _, err = s.cron.AddFunc(spec, s.tick); err != nil {
+		return
+	}
+	s.cron.Start()
+	return
+}
+
+// Stop ends the cron loop and waits for any in-flight tick to finish, or for ctx to expire,
+// whichever comes first - the same shape as poll.PollHandle.Stop.
+func (s *Scheduler) Stop(ctx context.Context) (err error) {
+	stopped := s.cron.Stop()
+	select {
+	case <-stopped.Done():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// tick checks every campaign for a due automatic transition and applies it.
+func (s *Scheduler) tick() {
+	now := time.Now()
+	campaigns, err := s.store.GetCampaigns()
+	if err != nil {
+		s.logger.Error("campaign scheduler: list campaigns", zap.Error(err))
+		return
+	}
+
+	for _, c := range campaigns {
+		from := State(c.State)
+		to, ok := dueTransition(from, c.StartOn, c.EndOn, now)
+		if !ok {
+			continue
+		}
+
+		if err := s.store.UpdateCampaignState(c.Name, string(to)); err != nil {
+			s.logger.Error("campaign scheduler: update state",
+				zap.String("campaign", c.Name), zap.Error(err))
+			continue
+		}
+		s.logger.Info("campaign scheduler: transitioned",
+			zap.String("campaign", c.Name), zap.String("from", string(from)), zap.String("to", string(to)))
+
+		if s.hook != nil {
+			s.hook(c.Name, from, to)
+		}
+	}
+}
+
+// dueTransition reports the automatic transition from is due for given its campaign window, if
+// any: scheduled campaigns activate at startOn, active campaigns end at endOn. Every other State
+// (draft, paused, ended) only changes via an explicit admin action.
+func dueTransition(from State, startOn, endOn time.Time, now time.Time) (to State, ok bool) {
+	switch from {
+	case StateScheduled:
+		if !now.Before(startOn) {
+			return StateActive, true
+		}
+	case StateActive:
+		if !now.Before(endOn) {
+			return StateEnded, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,112 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package campaign
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+)
+
+type fakeStore struct {
+	campaigns []types.CampaignStruct
+	updated   map[string]string
+	updateErr error
+}
+
+func (f *fakeStore) GetCampaigns() ([]types.CampaignStruct, error) {
+	return f.campaigns, nil
+}
+
+func (f *fakeStore) UpdateCampaignState(name string, state string) error {
+	if f.updateErr != nil {
+		return f.updateErr
+	}
+	if f.updated == nil {
+		f.updated = map[string]string{}
+	}
+	f.updated[name] = state
+	return nil
+}
+
+func TestDueTransition(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	to, ok := dueTransition(StateScheduled, past, future, now)
+	assert.True(t, ok)
+	assert.Equal(t, StateActive, to)
+
+	to, ok = dueTransition(StateScheduled, future, future.Add(time.Hour), now)
+	assert.False(t, ok)
+	assert.Equal(t, State(""), to)
+
+	to, ok = dueTransition(StateActive, past, past.Add(time.Minute), now)
+	assert.True(t, ok)
+	assert.Equal(t, StateEnded, to)
+
+	to, ok = dueTransition(StateActive, past, future, now)
+	assert.False(t, ok)
+	assert.Equal(t, State(""), to)
+
+	to, ok = dueTransition(StatePaused, past, future, now)
+	assert.False(t, ok)
+	assert.Equal(t, State(""), to)
+}
+
+func TestSchedulerTickAppliesDueTransitionsAndCallsHook(t *testing.T) {
+	now := time.Now()
+	store := &fakeStore{campaigns: []types.CampaignStruct{
+		{Name: "fall-bash", State: string(StateScheduled), StartOn: now.Add(-time.Minute), EndOn: now.Add(time.Hour)},
+		{Name: "spring-bash", State: string(StateActive), StartOn: now.Add(-time.Hour), EndOn: now.Add(time.Hour)},
+	}}
+
+	var hookCalls []webhookPayload
+	hook := func(campaignName string, from, to State) {
+		hookCalls = append(hookCalls, webhookPayload{CampaignName: campaignName, From: from, To: to})
+	}
+
+	scheduler := NewScheduler(store, hook, zaptest.NewLogger(t))
+	scheduler.tick()
+
+	assert.Equal(t, string(StateActive), store.updated["fall-bash"])
+	assert.NotContains(t, store.updated, "spring-bash")
+	assert.Equal(t, []webhookPayload{{CampaignName: "fall-bash", From: StateScheduled, To: StateActive}}, hookCalls)
+}
+
+func TestSchedulerTickSkipsHookOnUpdateError(t *testing.T) {
+	now := time.Now()
+	store := &fakeStore{
+		campaigns: []types.CampaignStruct{
+			{Name: "fall-bash", State: string(StateScheduled), StartOn: now.Add(-time.Minute), EndOn: now.Add(time.Hour)},
+		},
+		updateErr: assert.AnError,
+	}
+
+	called := false
+	scheduler := NewScheduler(store, func(string, State, State) { called = true }, zaptest.NewLogger(t))
+	scheduler.tick()
+
+	assert.False(t, called)
+}
@@ -0,0 +1,56 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+// Package campaign replaces "addCampaign/updateCampaign write to Postgres, getActiveCampaigns
+// filters by now at read time" with an explicit, validated campaign.State: State/ValidTransition
+// gives server.go a single place to reject illegal transitions, and Scheduler (see scheduler.go)
+// drives the scheduled -> active -> ended transitions that used to be implicit in a
+// start_on/end_on time-window comparison.
+package campaign
+
+// State is a campaign's lifecycle stage, persisted as CampaignStruct.State.
+type State string
+
+const (
+	StateDraft     State = "draft"
+	StateScheduled State = "scheduled"
+	StateActive    State = "active"
+	StatePaused    State = "paused"
+	StateEnded     State = "ended"
+)
+
+// transitions lists, per State, the States it may move to. StateEnded is terminal: once a
+// campaign ends there is no way back, by design - a re-run is a new campaign.
+var transitions = map[State]map[State]bool{
+	StateDraft:     {StateScheduled: true, StateActive: true},
+	StateScheduled: {StateActive: true, StateDraft: true},
+	StateActive:    {StatePaused: true, StateEnded: true},
+	StatePaused:    {StateActive: true, StateEnded: true},
+	StateEnded:     {},
+}
+
+// ValidTransition reports whether a campaign may move from from to to. Leaving a campaign in its
+// current state is always valid, so callers that always submit state alongside other edits don't
+// have to special-case "no actual change".
+func ValidTransition(from, to State) bool {
+	if from == to {
+		return true
+	}
+	return transitions[from][to]
+}
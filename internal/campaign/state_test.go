@@ -0,0 +1,53 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package campaign
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidTransition(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to State
+		want     bool
+	}{
+		{"draft to scheduled", StateDraft, StateScheduled, true},
+		{"draft to active", StateDraft, StateActive, true},
+		{"draft to ended", StateDraft, StateEnded, false},
+		{"scheduled to active", StateScheduled, StateActive, true},
+		{"scheduled to draft", StateScheduled, StateDraft, true},
+		{"scheduled to paused", StateScheduled, StatePaused, false},
+		{"active to paused", StateActive, StatePaused, true},
+		{"active to ended", StateActive, StateEnded, true},
+		{"active to draft", StateActive, StateDraft, false},
+		{"paused to active", StatePaused, StateActive, true},
+		{"paused to ended", StatePaused, StateEnded, true},
+		{"ended to anything", StateEnded, StateActive, false},
+		{"same state is always valid", StateActive, StateActive, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ValidTransition(tt.from, tt.to))
+		})
+	}
+}
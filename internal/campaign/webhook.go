@@ -0,0 +1,91 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package campaign
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// webhookPayload is the body Webhook.Notify POSTs for every transition.
+type webhookPayload struct {
+	CampaignName string `json:"campaignName"`
+	From         State  `json:"from"`
+	To           State  `json:"to"`
+}
+
+// Webhook POSTs a JSON notification to a configured URL whenever a campaign transitions state,
+// e.g. so an external dashboard can show "fall-bash just went active" without polling.
+type Webhook struct {
+	URL    string
+	client *http.Client
+	logger *zap.Logger
+}
+
+// NewWebhook builds a Webhook posting to url. An empty url makes Notify a no-op, so callers can
+// always construct one and call Notify unconditionally rather than nil-checking it everywhere.
+func NewWebhook(url string, logger *zap.Logger) *Webhook {
+	return &Webhook{
+		URL:    url,
+		client: &http.Client{Timeout: 5 * time.Second},
+		logger: logger,
+	}
+}
+
+// Notify posts from/to for campaignName. Fire-and-forget, like internal/telemetry.OTLPSink: a
+// slow or unreachable webhook receiver must never add latency to the transition that triggered
+// it, whether that transition came from a scheduler tick or a pause/resume request.
+func (w *Webhook) Notify(campaignName string, from, to State) {
+	if w.URL == "" {
+		return
+	}
+	go w.post(webhookPayload{CampaignName: campaignName, From: from, To: to})
+}
+
+func (w *Webhook) post(payload webhookPayload) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		w.logger.Debug("campaign webhook marshal error", zap.Error(err))
+		return
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		w.logger.Debug("campaign webhook request error", zap.Error(err))
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := w.client.Do(req)
+	if err != nil {
+		w.logger.Debug("campaign webhook post error", zap.Error(err))
+		return
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		w.logger.Debug("campaign webhook rejected notification", zap.String("status", res.Status))
+	}
+}
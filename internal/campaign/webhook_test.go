@@ -0,0 +1,76 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package campaign
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestWebhookNotifyPostsExpectedShape(t *testing.T) {
+	received := make(chan webhookPayload, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		body, _ := ioutil.ReadAll(r.Body)
+		var payload webhookPayload
+		assert.NoError(t, json.Unmarshal(body, &payload))
+		received <- payload
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	webhook := NewWebhook(ts.URL, zaptest.NewLogger(t))
+	webhook.Notify("fall-bash", StateScheduled, StateActive)
+
+	select {
+	case payload := <-received:
+		assert.Equal(t, webhookPayload{CampaignName: "fall-bash", From: StateScheduled, To: StateActive}, payload)
+	case <-time.After(2 * time.Second):
+		t.Fatal("webhook never posted its notification")
+	}
+}
+
+func TestWebhookNotifyNoOpWithoutURL(t *testing.T) {
+	webhook := NewWebhook("", zaptest.NewLogger(t))
+	webhook.Notify("fall-bash", StateScheduled, StateActive)
+}
+
+func TestWebhookNotifyDoesNotBlockOnUnreachableURL(t *testing.T) {
+	webhook := NewWebhook("http://127.0.0.1:1/unreachable", zaptest.NewLogger(t))
+
+	done := make(chan struct{})
+	go func() {
+		webhook.Notify("fall-bash", StateActive, StateEnded)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Notify should return immediately regardless of URL reachability")
+	}
+}
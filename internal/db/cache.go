@@ -0,0 +1,258 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+)
+
+// CacheConfig tunes CachedDB's cache-aside behaviour. A zero-value CacheConfig is not usable as
+// such - use DefaultCacheConfig or set TTL explicitly - since a zero TTL would make every cached
+// write expire instantly.
+type CacheConfig struct {
+	// TTL is the base expiry SETEX uses for a cached value.
+	TTL time.Duration
+
+	// JitterFraction spreads each key's actual TTL by up to this fraction of TTL (0.1 means
+	// +/-10%), so a flood of keys written at the same instant don't all expire - and all miss,
+	// and all hit Postgres - at the same instant (the thundering-herd/stampede problem).
+	JitterFraction float64
+}
+
+// DefaultCacheConfig is a reasonable default for a live bug bash leaderboard: short enough that a
+// stale score doesn't linger, long enough to absorb a burst of leaderboard polling.
+var DefaultCacheConfig = CacheConfig{TTL: 30 * time.Second, JitterFraction: 0.1}
+
+// CachedDB wraps an IBBashDB with a Redis cache-aside layer over the hot read paths
+// (SelectParticipantDetail, SelectParticipantsInCampaign, SelectBugs): on a read, it looks up a
+// namespaced key, and on a miss runs the wrapped query and SETEXes the JSON result. Every
+// mutating call that can invalidate one of those reads (InsertParticipant, UpdateParticipant,
+// UpdateParticipantTeam, DeleteParticipant, InsertScoringEvent, ApplyScoringEvent,
+// UpdateParticipantScoreCAS, InsertBug, UpdateBug) deletes the keys its arguments derive before
+// returning, rather than trying to update them in place - same "invalidate, don't patch" choice
+// ApplyScoringEvent's CAS loop makes for Postgres, applied here to Redis. All other IBBashDB
+// methods pass straight through via the embedded interface.
+//
+// CachedDB embeds IBBashDB instead of re-declaring all ~70 methods, the way this package already
+// narrows IBBashDB into CampaignStore/ParticipantStore/etc. in store.go: only the methods that
+// need caching are overridden below, everything else is the inner db's own implementation.
+type CachedDB struct {
+	IBBashDB
+
+	rdb    *redis.Client
+	logger *zap.Logger
+	cfg    CacheConfig
+	group  singleflight.Group
+}
+
+// NewCachedDB wraps inner with a cache-aside layer backed by rdb. If rdb is nil, NewCachedDB
+// returns inner unchanged - the --cache-off fallback, so callers can wire this in unconditionally
+// and let a flag or missing REDIS_URL disable it without an if/else at every call site.
+func NewCachedDB(inner IBBashDB, rdb *redis.Client, cfg CacheConfig, logger *zap.Logger) IBBashDB {
+	if rdb == nil {
+		return inner
+	}
+	return &CachedDB{IBBashDB: inner, rdb: rdb, logger: logger, cfg: cfg}
+}
+
+func participantKey(campaignName, scpName, loginName string) string {
+	return fmt.Sprintf("bbash:participant:%s:%s:%s", campaignName, scpName, loginName)
+}
+
+func campaignParticipantsKey(campaignName string) string {
+	return fmt.Sprintf("bbash:campaign:%s:participants", campaignName)
+}
+
+const bugsKey = "bbash:bugs"
+
+// ttlWithJitter spreads c.cfg.TTL by up to c.cfg.JitterFraction so keys written together don't
+// all expire - and stampede Postgres - at the same instant.
+func (c *CachedDB) ttlWithJitter() time.Duration {
+	if c.cfg.JitterFraction <= 0 {
+		return c.cfg.TTL
+	}
+	jitter := float64(c.cfg.TTL) * c.cfg.JitterFraction * (rand.Float64()*2 - 1)
+	return c.cfg.TTL + time.Duration(jitter)
+}
+
+// cacheAside implements the read half of the cache-aside pattern for a single key: on a hit, dest
+// is populated from the cached JSON; on a miss (or a down Redis), miss runs, its result is SETEX'd
+// back for next time, and dest is populated from that result instead. A singleflight.Group
+// collapses concurrent misses on the same key into one miss call, so a leaderboard refresh
+// stampede only issues one Postgres query rather than one per waiting request. Any Redis error -
+// not just a miss - falls through to miss, so a downed Redis degrades to direct SQL rather than
+// failing the request.
+func (c *CachedDB) cacheAside(ctx context.Context, key string, dest interface{}, miss func() (interface{}, error)) error {
+	cached, err := c.rdb.Get(ctx, key).Result()
+	if err == nil {
+		if jsonErr := json.Unmarshal([]byte(cached), dest); jsonErr == nil {
+			return nil
+		}
+		// fall through to miss on an unmarshal error (e.g. a stale schema) rather than failing the read
+	} else if err != redis.Nil {
+		c.logger.Warn("cache get failed, falling back to direct read", zap.String("key", key), zap.Error(err))
+	}
+
+	result, err, _ := c.group.Do(key, func() (interface{}, error) {
+		return miss()
+	})
+	if err != nil {
+		return err
+	}
+
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	if setErr := c.rdb.SetEX(ctx, key, encoded, c.ttlWithJitter()).Err(); setErr != nil {
+		c.logger.Warn("cache set failed", zap.String("key", key), zap.Error(setErr))
+	}
+	return json.Unmarshal(encoded, dest)
+}
+
+// invalidate deletes keys from Redis, logging (not failing) on error - a downed Redis must not
+// block the write it's invalidating after.
+func (c *CachedDB) invalidate(ctx context.Context, keys ...string) {
+	if err := c.rdb.Del(ctx, keys...).Err(); err != nil {
+		c.logger.Warn("cache invalidate failed", zap.Strings("keys", keys), zap.Error(err))
+	}
+}
+
+func (c *CachedDB) SelectParticipantDetail(campaignName, scpName, loginName string) (participant *types.ParticipantStruct, err error) {
+	key := participantKey(campaignName, scpName, loginName)
+	err = c.cacheAside(context.Background(), key, &participant, func() (interface{}, error) {
+		return c.IBBashDB.SelectParticipantDetail(campaignName, scpName, loginName)
+	})
+	return
+}
+
+func (c *CachedDB) SelectParticipantsInCampaign(campaignName string) (participants []types.ParticipantStruct, err error) {
+	key := campaignParticipantsKey(campaignName)
+	err = c.cacheAside(context.Background(), key, &participants, func() (interface{}, error) {
+		return c.IBBashDB.SelectParticipantsInCampaign(campaignName)
+	})
+	return
+}
+
+func (c *CachedDB) SelectBugs() (bugs []types.BugStruct, err error) {
+	err = c.cacheAside(context.Background(), bugsKey, &bugs, func() (interface{}, error) {
+		return c.IBBashDB.SelectBugs()
+	})
+	return
+}
+
+func (c *CachedDB) InsertParticipant(participant *types.ParticipantStruct) (err error) {
+	if err = c.IBBashDB.InsertParticipant(participant); err != nil {
+		return
+	}
+	c.invalidate(context.Background(), campaignParticipantsKey(participant.CampaignName))
+	return
+}
+
+func (c *CachedDB) UpdateParticipant(participant *types.ParticipantStruct) (rowsAffected int64, err error) {
+	rowsAffected, err = c.IBBashDB.UpdateParticipant(participant)
+	if err != nil {
+		return
+	}
+	c.invalidate(context.Background(),
+		participantKey(participant.CampaignName, participant.ScpName, participant.LoginName),
+		campaignParticipantsKey(participant.CampaignName))
+	return
+}
+
+func (c *CachedDB) UpdateParticipantTeam(teamName, campaignName, scpName, loginName string) (rowsAffected int64, err error) {
+	rowsAffected, err = c.IBBashDB.UpdateParticipantTeam(teamName, campaignName, scpName, loginName)
+	if err != nil {
+		return
+	}
+	c.invalidate(context.Background(),
+		participantKey(campaignName, scpName, loginName),
+		campaignParticipantsKey(campaignName))
+	return
+}
+
+func (c *CachedDB) DeleteParticipant(campaign, scpName, loginName string) (participantId string, err error) {
+	participantId, err = c.IBBashDB.DeleteParticipant(campaign, scpName, loginName)
+	if err != nil {
+		return
+	}
+	c.invalidate(context.Background(),
+		participantKey(campaign, scpName, loginName),
+		campaignParticipantsKey(campaign))
+	return
+}
+
+func (c *CachedDB) InsertScoringEvent(participantToScore *types.ParticipantStruct, msg *types.ScoringMessage, newPoints float64) (err error) {
+	if err = c.IBBashDB.InsertScoringEvent(participantToScore, msg, newPoints); err != nil {
+		return
+	}
+	c.invalidate(context.Background(),
+		participantKey(participantToScore.CampaignName, participantToScore.ScpName, participantToScore.LoginName),
+		campaignParticipantsKey(participantToScore.CampaignName))
+	return
+}
+
+func (c *CachedDB) UpdateParticipantScoreCAS(participant *types.ParticipantStruct, expectedScore int, delta float64) (swapped bool, err error) {
+	swapped, err = c.IBBashDB.UpdateParticipantScoreCAS(participant, expectedScore, delta)
+	if err != nil {
+		return
+	}
+	c.invalidate(context.Background(),
+		participantKey(participant.CampaignName, participant.ScpName, participant.LoginName),
+		campaignParticipantsKey(participant.CampaignName))
+	return
+}
+
+func (c *CachedDB) ApplyScoringEvent(participantToScore *types.ParticipantStruct, msg *types.ScoringMessage, newPoints, delta float64, expectedScore int) (swapped bool, err error) {
+	swapped, err = c.IBBashDB.ApplyScoringEvent(participantToScore, msg, newPoints, delta, expectedScore)
+	if err != nil {
+		return
+	}
+	c.invalidate(context.Background(),
+		participantKey(participantToScore.CampaignName, participantToScore.ScpName, participantToScore.LoginName),
+		campaignParticipantsKey(participantToScore.CampaignName))
+	return
+}
+
+func (c *CachedDB) InsertBug(bug *types.BugStruct) (err error) {
+	if err = c.IBBashDB.InsertBug(bug); err != nil {
+		return
+	}
+	c.invalidate(context.Background(), bugsKey)
+	return
+}
+
+func (c *CachedDB) UpdateBug(bug *types.BugStruct) (rowsAffected int64, err error) {
+	rowsAffected, err = c.IBBashDB.UpdateBug(bug)
+	if err != nil {
+		return
+	}
+	c.invalidate(context.Background(), bugsKey)
+	return
+}
@@ -0,0 +1,233 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+)
+
+// fakeInnerDB is a hand-rolled IBBashDB used only to count calls through to the wrapped db -
+// cache_test.go needs to assert a cache hit makes zero such calls, which a sqlmock-backed
+// *BBashDB can't express directly, so this stands in for SetupMockDB here. Embedding a nil
+// IBBashDB means any method this test doesn't override panics on call, the same way an
+// un-stubbed testify mock.Mock call would.
+type fakeInnerDB struct {
+	IBBashDB
+
+	selectParticipantDetailCalls int
+	participant                  *types.ParticipantStruct
+
+	selectParticipantsInCampaignCalls int
+	participants                      []types.ParticipantStruct
+
+	selectBugsCalls int
+	bugs            []types.BugStruct
+}
+
+func (f *fakeInnerDB) SelectParticipantDetail(campaignName, scpName, loginName string) (*types.ParticipantStruct, error) {
+	f.selectParticipantDetailCalls++
+	return f.participant, nil
+}
+
+func (f *fakeInnerDB) SelectParticipantsInCampaign(campaignName string) ([]types.ParticipantStruct, error) {
+	f.selectParticipantsInCampaignCalls++
+	return f.participants, nil
+}
+
+func (f *fakeInnerDB) SelectBugs() ([]types.BugStruct, error) {
+	f.selectBugsCalls++
+	return f.bugs, nil
+}
+
+func (f *fakeInnerDB) InsertParticipant(participant *types.ParticipantStruct) error {
+	return nil
+}
+
+func (f *fakeInnerDB) UpdateParticipant(participant *types.ParticipantStruct) (int64, error) {
+	return 1, nil
+}
+
+func (f *fakeInnerDB) InsertBug(bug *types.BugStruct) error {
+	return nil
+}
+
+func (f *fakeInnerDB) UpdateParticipantScoreCAS(participant *types.ParticipantStruct, expectedScore int, delta float64) (bool, error) {
+	return true, nil
+}
+
+func (f *fakeInnerDB) ApplyScoringEvent(participantToScore *types.ParticipantStruct, msg *types.ScoringMessage, newPoints, delta float64, expectedScore int) (bool, error) {
+	return true, nil
+}
+
+func setupCachedDB(t *testing.T) (inner *fakeInnerDB, cached *CachedDB, closeFunc func()) {
+	mr, err := miniredis.Run()
+	assert.NoError(t, err)
+
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	inner = &fakeInnerDB{}
+	cached = NewCachedDB(inner, rdb, CacheConfig{TTL: time.Minute}, zaptest.NewLogger(t)).(*CachedDB)
+	closeFunc = func() {
+		_ = rdb.Close()
+		mr.Close()
+	}
+	return
+}
+
+func TestCachedDBSelectParticipantDetailHitAndMiss(t *testing.T) {
+	inner, cached, closeFunc := setupCachedDB(t)
+	defer closeFunc()
+
+	inner.participant = &types.ParticipantStruct{CampaignName: campaignName, ScpName: "scpName", LoginName: loginName, Score: 5}
+
+	got, err := cached.SelectParticipantDetail(campaignName, "scpName", loginName)
+	assert.NoError(t, err)
+	assert.Equal(t, inner.participant, got)
+	assert.Equal(t, 1, inner.selectParticipantDetailCalls)
+
+	got, err = cached.SelectParticipantDetail(campaignName, "scpName", loginName)
+	assert.NoError(t, err)
+	assert.Equal(t, inner.participant, got)
+	// still 1: the second call is served from the cache, not the inner db
+	assert.Equal(t, 1, inner.selectParticipantDetailCalls)
+}
+
+func TestCachedDBInvalidatesParticipantOnUpdate(t *testing.T) {
+	inner, cached, closeFunc := setupCachedDB(t)
+	defer closeFunc()
+
+	inner.participant = &types.ParticipantStruct{CampaignName: campaignName, ScpName: "scpName", LoginName: loginName, Score: 5}
+	_, err := cached.SelectParticipantDetail(campaignName, "scpName", loginName)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, inner.selectParticipantDetailCalls)
+
+	inner.participant = &types.ParticipantStruct{CampaignName: campaignName, ScpName: "scpName", LoginName: loginName, Score: 9}
+	_, err = cached.UpdateParticipant(inner.participant)
+	assert.NoError(t, err)
+
+	got, err := cached.SelectParticipantDetail(campaignName, "scpName", loginName)
+	assert.NoError(t, err)
+	assert.Equal(t, 9, got.Score)
+	// the update invalidated the cached entry, so this read had to go back to the inner db
+	assert.Equal(t, 2, inner.selectParticipantDetailCalls)
+}
+
+func TestCachedDBInvalidatesCampaignParticipantsOnInsert(t *testing.T) {
+	inner, cached, closeFunc := setupCachedDB(t)
+	defer closeFunc()
+
+	inner.participants = []types.ParticipantStruct{{CampaignName: campaignName, LoginName: loginName}}
+	_, err := cached.SelectParticipantsInCampaign(campaignName)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, inner.selectParticipantsInCampaignCalls)
+
+	assert.NoError(t, cached.InsertParticipant(&types.ParticipantStruct{CampaignName: campaignName, LoginName: "newLogin"}))
+
+	_, err = cached.SelectParticipantsInCampaign(campaignName)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, inner.selectParticipantsInCampaignCalls)
+}
+
+func TestCachedDBSelectBugsHitAndInvalidate(t *testing.T) {
+	inner, cached, closeFunc := setupCachedDB(t)
+	defer closeFunc()
+
+	inner.bugs = []types.BugStruct{{Campaign: campaignName, Category: "someCategory"}}
+	_, err := cached.SelectBugs()
+	assert.NoError(t, err)
+	_, err = cached.SelectBugs()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, inner.selectBugsCalls)
+
+	assert.NoError(t, cached.InsertBug(&types.BugStruct{Campaign: campaignName, Category: "otherCategory"}))
+
+	_, err = cached.SelectBugs()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, inner.selectBugsCalls)
+}
+
+func TestCachedDBInvalidatesParticipantOnApplyScoringEvent(t *testing.T) {
+	inner, cached, closeFunc := setupCachedDB(t)
+	defer closeFunc()
+
+	participant := &types.ParticipantStruct{CampaignName: campaignName, ScpName: "scpName", LoginName: loginName, Score: 5}
+	inner.participant = participant
+	_, err := cached.SelectParticipantDetail(campaignName, "scpName", loginName)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, inner.selectParticipantDetailCalls)
+
+	_, err = cached.ApplyScoringEvent(participant, &types.ScoringMessage{}, 5, 5, 5)
+	assert.NoError(t, err)
+
+	_, err = cached.SelectParticipantDetail(campaignName, "scpName", loginName)
+	assert.NoError(t, err)
+	// ApplyScoringEvent invalidated the cached entry, so this read had to go back to the inner db
+	assert.Equal(t, 2, inner.selectParticipantDetailCalls)
+}
+
+func TestCachedDBInvalidatesParticipantOnUpdateParticipantScoreCAS(t *testing.T) {
+	inner, cached, closeFunc := setupCachedDB(t)
+	defer closeFunc()
+
+	participant := &types.ParticipantStruct{CampaignName: campaignName, ScpName: "scpName", LoginName: loginName, Score: 5}
+	inner.participant = participant
+	_, err := cached.SelectParticipantDetail(campaignName, "scpName", loginName)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, inner.selectParticipantDetailCalls)
+
+	_, err = cached.UpdateParticipantScoreCAS(participant, 5, 5)
+	assert.NoError(t, err)
+
+	_, err = cached.SelectParticipantDetail(campaignName, "scpName", loginName)
+	assert.NoError(t, err)
+	// UpdateParticipantScoreCAS invalidated the cached entry, so this read had to go back to the inner db
+	assert.Equal(t, 2, inner.selectParticipantDetailCalls)
+}
+
+// TestCachedDBDegradesOnDownRedis proves a downed Redis falls through to the inner db rather than
+// failing the read - cacheAside's miss path runs regardless of whether the Get error was a miss
+// or a connection failure.
+func TestCachedDBDegradesOnDownRedis(t *testing.T) {
+	mr, err := miniredis.Run()
+	assert.NoError(t, err)
+
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	inner := &fakeInnerDB{participant: &types.ParticipantStruct{CampaignName: campaignName, ScpName: "scpName", LoginName: loginName, Score: 5}}
+	cached := NewCachedDB(inner, rdb, CacheConfig{TTL: time.Minute}, zaptest.NewLogger(t)).(*CachedDB)
+
+	mr.Close() // simulate Redis going down
+
+	got, err := cached.SelectParticipantDetail(campaignName, "scpName", loginName)
+	assert.NoError(t, err)
+	assert.Equal(t, inner.participant, got)
+	assert.Equal(t, 1, inner.selectParticipantDetailCalls)
+}
+
+func TestNewCachedDBCacheOffPassesThrough(t *testing.T) {
+	inner := &fakeInnerDB{}
+	got := NewCachedDB(inner, nil, DefaultCacheConfig, zaptest.NewLogger(t))
+	assert.Same(t, inner, got)
+}
@@ -0,0 +1,224 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// MongoPollStore is a MongoDB-backed IDBPoll, for deployments that would rather run the poll
+// loop's state (the singleton poll row and its dead letter queue) against Mongo than Postgres.
+// See NewPollStore for how a deployment picks between this and the Postgres-backed PollStruct.
+type MongoPollStore struct {
+	pollColl       *mongo.Collection
+	deadLetterColl *mongo.Collection
+	logger         *zap.Logger
+}
+
+// enforce implementation of interface
+var _ IDBPoll = (*MongoPollStore)(nil)
+
+// NewMongoPollStore connects to uri and returns a MongoPollStore backed by dbName's "poll" and
+// "dead_letter" collections.
+func NewMongoPollStore(ctx context.Context, uri, dbName string, logger *zap.Logger) (store *MongoPollStore, err error) {
+	var client *mongo.Client
+	client, err = mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		return
+	}
+	if err = client.Ping(ctx, nil); err != nil {
+		return
+	}
+
+	database := client.Database(dbName)
+	store = &MongoPollStore{
+		pollColl:       database.Collection("poll"),
+		deadLetterColl: database.Collection("dead_letter"),
+		logger:         logger,
+	}
+	return
+}
+
+// NewPollStore returns a MongoPollStore when mongoURI is non-empty, a RedisPollStore when
+// pollRedisAddr is non-empty (checked first, since both are meant to be alternatives to Postgres
+// and are not expected to be configured together), or the existing Postgres-backed PollStruct
+// otherwise - mirroring leader.New's RedisElector-or-PostgresElector selection.
+func NewPollStore(ctx context.Context, mongoURI, mongoDbName, pollRedisAddr string, sqlDB *sql.DB, logger *zap.Logger) (IDBPoll, error) {
+	if pollRedisAddr != "" {
+		return NewRedisPollStore(redis.NewClient(&redis.Options{Addr: pollRedisAddr}), logger), nil
+	}
+	if mongoURI != "" {
+		return NewMongoPollStore(ctx, mongoURI, mongoDbName, logger)
+	}
+	return NewDBPoll(sqlDB, logger), nil
+}
+
+func (m *MongoPollStore) GetLogger() *zap.Logger {
+	return m.logger
+}
+
+func (m *MongoPollStore) NewPoll() types.Poll {
+	return NewPoll()
+}
+
+type mongoPollDoc struct {
+	ID                string    `bson:"_id"`
+	LastPolledOn      time.Time `bson:"lastPolledOn"`
+	EnvBaseTime       time.Time `bson:"envBaseTime"`
+	LastPollCompleted time.Time `bson:"lastPollCompleted"`
+	LeaderInstance    string    `bson:"leaderInstance"`
+	Schedule          string    `bson:"schedule"`
+}
+
+func (m *MongoPollStore) UpdatePoll(ctx context.Context, poll *types.Poll) (err error) {
+	result, err := m.pollColl.UpdateOne(ctx,
+		bson.M{"_id": poll.Id},
+		bson.M{"$set": bson.M{
+			"lastPolledOn":      poll.LastPolled,
+			"envBaseTime":       poll.EnvBaseTime,
+			"lastPollCompleted": poll.LastPollCompleted,
+			"leaderInstance":    poll.LeaderInstance,
+			"schedule":          poll.Schedule,
+		}},
+	)
+	if err != nil {
+		return
+	}
+	if result.MatchedCount != 1 {
+		err = fmt.Errorf("update poll updated wrong number of rows: %d, poll %+v", result.MatchedCount, poll)
+	}
+	return
+}
+
+func (m *MongoPollStore) SelectPoll(ctx context.Context, poll *types.Poll) (err error) {
+	var doc mongoPollDoc
+	err = m.pollColl.FindOne(ctx, bson.M{"_id": poll.Id}).Decode(&doc)
+	if err != nil {
+		m.logger.Error("selectPoll scan error", zap.Error(err))
+		return
+	}
+	poll.LastPolled = doc.LastPolledOn
+	poll.EnvBaseTime = doc.EnvBaseTime
+	poll.LastPollCompleted = doc.LastPollCompleted
+	poll.Schedule = doc.Schedule
+	poll.LeaderInstance = doc.LeaderInstance
+
+	poll.NextRunAt, err = m.ComputeNextRun(poll, time.Now())
+	return
+}
+
+// ComputeNextRun returns when poll.Schedule should next fire after now; see db.ComputeNextRun.
+func (m *MongoPollStore) ComputeNextRun(poll *types.Poll, now time.Time) (time.Time, error) {
+	return ComputeNextRun(poll, now)
+}
+
+type mongoDeadLetterDoc struct {
+	ID          string               `bson:"_id"`
+	Message     types.ScoringMessage `bson:"message"`
+	EnvBaseTime time.Time            `bson:"envBaseTime"`
+	Error       string               `bson:"error"`
+	RetryCount  int                  `bson:"retryCount"`
+	CreatedOn   time.Time            `bson:"createdOn"`
+	LastAttempt time.Time            `bson:"lastAttempt"`
+}
+
+// InsertDeadLetter records a ScoringMessage that processScoringMessage failed to apply, along with
+// the error that caused the failure, so it can be inspected and replayed later instead of blocking
+// the rest of the poll tick it arrived in.
+func (m *MongoPollStore) InsertDeadLetter(entry *types.DeadLetterEntry) (err error) {
+	id := primitive.NewObjectID().Hex()
+	doc := mongoDeadLetterDoc{
+		ID:          id,
+		Message:     entry.Message,
+		EnvBaseTime: entry.EnvBaseTime,
+		Error:       entry.Error,
+		RetryCount:  entry.RetryCount,
+		CreatedOn:   entry.CreatedOn,
+		LastAttempt: entry.LastAttempt,
+	}
+	if _, err = m.deadLetterColl.InsertOne(context.Background(), doc); err != nil {
+		return
+	}
+	entry.Id = id
+	return
+}
+
+// SelectDeadLetters returns every dead-lettered ScoringMessage, oldest first.
+func (m *MongoPollStore) SelectDeadLetters() (entries []types.DeadLetterEntry, err error) {
+	var cursor *mongo.Cursor
+	cursor, err = m.deadLetterColl.Find(context.Background(), bson.M{}, options.Find().SetSort(bson.D{{Key: "createdOn", Value: 1}}))
+	if err != nil {
+		return
+	}
+	defer cursor.Close(context.Background())
+
+	for cursor.Next(context.Background()) {
+		var doc mongoDeadLetterDoc
+		if err = cursor.Decode(&doc); err != nil {
+			return
+		}
+		entries = append(entries, types.DeadLetterEntry{
+			Id:          doc.ID,
+			Message:     doc.Message,
+			EnvBaseTime: doc.EnvBaseTime,
+			Error:       doc.Error,
+			RetryCount:  doc.RetryCount,
+			CreatedOn:   doc.CreatedOn,
+			LastAttempt: doc.LastAttempt,
+		})
+	}
+	err = cursor.Err()
+	return
+}
+
+// UpdateDeadLetterRetry records a failed (or retried) replay attempt for the dead letter entry
+// identified by id, incrementing its retry count and storing the latest error.
+func (m *MongoPollStore) UpdateDeadLetterRetry(id string, lastAttempt time.Time, retryErr error) (err error) {
+	errMsg := ""
+	if retryErr != nil {
+		errMsg = retryErr.Error()
+	}
+	_, err = m.deadLetterColl.UpdateOne(context.Background(),
+		bson.M{"_id": id},
+		bson.M{
+			"$inc": bson.M{"retryCount": 1},
+			"$set": bson.M{"lastAttempt": lastAttempt, "error": errMsg},
+		},
+	)
+	return
+}
+
+// DeleteDeadLetter discards the dead letter entry identified by id, e.g. after a successful replay
+// or an operator decision to give up on it.
+func (m *MongoPollStore) DeleteDeadLetter(id string) (err error) {
+	_, err = m.deadLetterColl.DeleteOne(context.Background(), bson.M{"_id": id})
+	return
+}
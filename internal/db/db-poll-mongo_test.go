@@ -0,0 +1,45 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestNewPollStoreEmptyMongoURIFallsBackToPostgres(t *testing.T) {
+	store, err := NewPollStore(context.Background(), "", "bbash", "", nil, zaptest.NewLogger(t))
+	assert.NoError(t, err)
+	_, ok := store.(*PollStruct)
+	assert.True(t, ok)
+}
+
+// TestNewPollStoreRedisAddrTakesPrecedence exercises NewPollStore's dispatch order: a non-empty
+// pollRedisAddr wins even when mongoURI is also set, since the two are meant to be alternatives to
+// Postgres rather than something a deployment configures together.
+func TestNewPollStoreRedisAddrTakesPrecedence(t *testing.T) {
+	store, err := NewPollStore(context.Background(), "mongodb://ignored", "bbash", "localhost:0", nil, zaptest.NewLogger(t))
+	assert.NoError(t, err)
+	_, ok := store.(*RedisPollStore)
+	assert.True(t, ok)
+}
@@ -0,0 +1,255 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+	"go.uber.org/zap"
+)
+
+// RedisPollStore is a Redis-backed IDBPoll, for small deployments that would rather not stand up
+// Postgres (or Mongo, see MongoPollStore) just to hold the poll loop's singleton row and dead
+// letter queue. See NewPollStore for how a deployment picks between this and the other two.
+//
+// This intentionally does NOT add its own SETNX+TTL coordination lock around the poll row: that
+// problem - preventing two bbash replicas from scraping concurrently - is already solved, more
+// generally, by internal/leader's RedisElector (and PostgresElector), which the poll scheduler
+// acquires independently of whichever IDBPoll backend is in use. Layering a second lock in here
+// would just be two locks guarding the same critical section.
+type RedisPollStore struct {
+	rdb    *redis.Client
+	logger *zap.Logger
+}
+
+// enforce implementation of interface
+var _ IDBPoll = (*RedisPollStore)(nil)
+
+// NewRedisPollStore returns a RedisPollStore backed by rdb.
+func NewRedisPollStore(rdb *redis.Client, logger *zap.Logger) *RedisPollStore {
+	return &RedisPollStore{rdb: rdb, logger: logger}
+}
+
+func (r *RedisPollStore) GetLogger() *zap.Logger {
+	return r.logger
+}
+
+func (r *RedisPollStore) NewPoll() types.Poll {
+	return NewPoll()
+}
+
+// redisPollKey namespaces the poll singleton's hash from bbash's other Redis keys (see
+// CachedDB's participantKey/campaignParticipantsKey/bugsKey for the same convention).
+func redisPollKey(id string) string {
+	return fmt.Sprintf("bbash:poll:%s", id)
+}
+
+func (r *RedisPollStore) UpdatePoll(ctx context.Context, poll *types.Poll) (err error) {
+	fields := map[string]interface{}{
+		"lastPolledOn":      poll.LastPolled.Format(time.RFC3339Nano),
+		"envBaseTime":       poll.EnvBaseTime.Format(time.RFC3339Nano),
+		"lastPollCompleted": poll.LastPollCompleted.Format(time.RFC3339Nano),
+		"leaderInstance":    poll.LeaderInstance,
+		"schedule":          poll.Schedule,
+	}
+	return r.rdb.HSet(ctx, redisPollKey(poll.Id), fields).Err()
+}
+
+func (r *RedisPollStore) SelectPoll(ctx context.Context, poll *types.Poll) (err error) {
+	fields, err := r.rdb.HGetAll(ctx, redisPollKey(poll.Id)).Result()
+	if err != nil {
+		r.logger.Error("selectPoll scan error", zap.Error(err))
+		return
+	}
+	// HGetAll on a missing key returns an empty map rather than an error - mirror sql.ErrNoRows /
+	// mongo.ErrNoDocuments so callers see the same "not found" error regardless of backend.
+	if len(fields) == 0 {
+		err = redis.Nil
+		r.logger.Error("selectPoll scan error", zap.Error(err))
+		return
+	}
+
+	if poll.LastPolled, err = time.Parse(time.RFC3339Nano, fields["lastPolledOn"]); err != nil {
+		r.logger.Error("selectPoll scan error", zap.Error(err))
+		return
+	}
+	if poll.EnvBaseTime, err = time.Parse(time.RFC3339Nano, fields["envBaseTime"]); err != nil {
+		r.logger.Error("selectPoll scan error", zap.Error(err))
+		return
+	}
+	if poll.LastPollCompleted, err = time.Parse(time.RFC3339Nano, fields["lastPollCompleted"]); err != nil {
+		r.logger.Error("selectPoll scan error", zap.Error(err))
+		return
+	}
+	poll.LeaderInstance = fields["leaderInstance"]
+	poll.Schedule = fields["schedule"]
+
+	poll.NextRunAt, err = r.ComputeNextRun(poll, time.Now())
+	return
+}
+
+// ComputeNextRun returns when poll.Schedule should next fire after now; see db.ComputeNextRun.
+func (r *RedisPollStore) ComputeNextRun(poll *types.Poll, now time.Time) (time.Time, error) {
+	return ComputeNextRun(poll, now)
+}
+
+// redisDeadLetterKeyPrefix namespaces a dead letter entry's own hash from the sorted set indexing
+// them by insertion order (redisDeadLetterIndexKey).
+const redisDeadLetterKeyPrefix = "bbash:dead_letter:"
+
+// redisDeadLetterIndexKey is a sorted set (member: entry id, score: the id itself, which is
+// monotonically increasing - see redisNextDeadLetterIDKey) letting SelectDeadLetters list entries
+// oldest-first without a table scan.
+const redisDeadLetterIndexKey = "bbash:dead_letter:index"
+
+// redisNextDeadLetterIDKey backs InsertDeadLetter's id assignment with INCR, rather than adding a
+// uuid dependency this module doesn't otherwise have - Postgres and Mongo both already generate
+// their own ids (a SERIAL column, an ObjectID) so this is the Redis equivalent.
+const redisNextDeadLetterIDKey = "bbash:dead_letter:next_id"
+
+func redisDeadLetterKey(id string) string {
+	return redisDeadLetterKeyPrefix + id
+}
+
+// redisDeadLetterDoc is the JSON document stored per dead letter entry, mirroring
+// mongoDeadLetterDoc's field set.
+type redisDeadLetterDoc struct {
+	Message     types.ScoringMessage `json:"message"`
+	EnvBaseTime time.Time            `json:"envBaseTime"`
+	Error       string               `json:"error"`
+	RetryCount  int                  `json:"retryCount"`
+	CreatedOn   time.Time            `json:"createdOn"`
+	LastAttempt time.Time            `json:"lastAttempt"`
+}
+
+// InsertDeadLetter records a ScoringMessage that processScoringMessage failed to apply, along with
+// the error that caused the failure, so it can be inspected and replayed later instead of blocking
+// the rest of the poll tick it arrived in.
+func (r *RedisPollStore) InsertDeadLetter(entry *types.DeadLetterEntry) (err error) {
+	ctx := context.Background()
+
+	var id int64
+	if id, err = r.rdb.Incr(ctx, redisNextDeadLetterIDKey).Result(); err != nil {
+		return
+	}
+	entry.Id = fmt.Sprintf("%d", id)
+
+	var payload []byte
+	if payload, err = json.Marshal(redisDeadLetterDoc{
+		Message:     entry.Message,
+		EnvBaseTime: entry.EnvBaseTime,
+		Error:       entry.Error,
+		RetryCount:  entry.RetryCount,
+		CreatedOn:   entry.CreatedOn,
+		LastAttempt: entry.LastAttempt,
+	}); err != nil {
+		return
+	}
+
+	if err = r.rdb.Set(ctx, redisDeadLetterKey(entry.Id), payload, 0).Err(); err != nil {
+		return
+	}
+	err = r.rdb.ZAdd(ctx, redisDeadLetterIndexKey, &redis.Z{Score: float64(id), Member: entry.Id}).Err()
+	return
+}
+
+// SelectDeadLetters returns every dead-lettered ScoringMessage, oldest first.
+func (r *RedisPollStore) SelectDeadLetters() (entries []types.DeadLetterEntry, err error) {
+	ctx := context.Background()
+
+	var ids []string
+	if ids, err = r.rdb.ZRangeByScore(ctx, redisDeadLetterIndexKey, &redis.ZRangeBy{Min: "-inf", Max: "+inf"}).Result(); err != nil {
+		return
+	}
+
+	for _, id := range ids {
+		var payload string
+		payload, err = r.rdb.Get(ctx, redisDeadLetterKey(id)).Result()
+		if err == redis.Nil {
+			// the index and the entry disagree (e.g. a DeleteDeadLetter that removed the entry but
+			// not yet the index entry) - skip rather than fail the whole listing
+			err = nil
+			continue
+		}
+		if err != nil {
+			return
+		}
+
+		var doc redisDeadLetterDoc
+		if err = json.Unmarshal([]byte(payload), &doc); err != nil {
+			return
+		}
+		entries = append(entries, types.DeadLetterEntry{
+			Id:          id,
+			Message:     doc.Message,
+			EnvBaseTime: doc.EnvBaseTime,
+			Error:       doc.Error,
+			RetryCount:  doc.RetryCount,
+			CreatedOn:   doc.CreatedOn,
+			LastAttempt: doc.LastAttempt,
+		})
+	}
+	return
+}
+
+// UpdateDeadLetterRetry records a failed (or retried) replay attempt for the dead letter entry
+// identified by id, incrementing its retry count and storing the latest error.
+func (r *RedisPollStore) UpdateDeadLetterRetry(id string, lastAttempt time.Time, retryErr error) (err error) {
+	ctx := context.Background()
+
+	payload, err := r.rdb.Get(ctx, redisDeadLetterKey(id)).Result()
+	if err != nil {
+		return
+	}
+	var doc redisDeadLetterDoc
+	if err = json.Unmarshal([]byte(payload), &doc); err != nil {
+		return
+	}
+
+	doc.RetryCount++
+	doc.LastAttempt = lastAttempt
+	if retryErr != nil {
+		doc.Error = retryErr.Error()
+	} else {
+		doc.Error = ""
+	}
+
+	var encoded []byte
+	if encoded, err = json.Marshal(doc); err != nil {
+		return
+	}
+	return r.rdb.Set(ctx, redisDeadLetterKey(id), encoded, 0).Err()
+}
+
+// DeleteDeadLetter discards the dead letter entry identified by id, e.g. after a successful replay
+// or an operator decision to give up on it.
+func (r *RedisPollStore) DeleteDeadLetter(id string) (err error) {
+	ctx := context.Background()
+
+	if err = r.rdb.Del(ctx, redisDeadLetterKey(id)).Err(); err != nil {
+		return
+	}
+	return r.rdb.ZRem(ctx, redisDeadLetterIndexKey, id).Err()
+}
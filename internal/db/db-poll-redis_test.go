@@ -0,0 +1,112 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+)
+
+func setupRedisPollStore(t *testing.T) (store *RedisPollStore, closeFunc func()) {
+	mr, err := miniredis.Run()
+	assert.NoError(t, err)
+
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	store = NewRedisPollStore(rdb, zaptest.NewLogger(t))
+	closeFunc = mr.Close
+	return
+}
+
+func TestRedisPollStoreUpdateThenSelectPollRoundTrips(t *testing.T) {
+	store, closeFunc := setupRedisPollStore(t)
+	defer closeFunc()
+
+	now := time.Now().Round(0)
+	poll := store.NewPoll()
+	poll.LastPolled = now
+	poll.EnvBaseTime = now.Add(time.Second)
+	poll.LastPollCompleted = now.Add(2 * time.Second)
+	poll.LeaderInstance = "instance-a"
+	poll.Schedule = "R/PT15M"
+
+	assert.NoError(t, store.UpdatePoll(context.Background(), &poll))
+
+	got := store.NewPoll()
+	assert.NoError(t, store.SelectPoll(context.Background(), &got))
+	assert.True(t, poll.LastPolled.Equal(got.LastPolled))
+	assert.True(t, poll.EnvBaseTime.Equal(got.EnvBaseTime))
+	assert.True(t, poll.LastPollCompleted.Equal(got.LastPollCompleted))
+	assert.Equal(t, poll.LeaderInstance, got.LeaderInstance)
+	assert.Equal(t, poll.Schedule, got.Schedule)
+}
+
+func TestRedisPollStoreSelectPollNotFound(t *testing.T) {
+	store, closeFunc := setupRedisPollStore(t)
+	defer closeFunc()
+
+	poll := store.NewPoll()
+	err := store.SelectPoll(context.Background(), &poll)
+	assert.ErrorIs(t, err, redis.Nil)
+}
+
+func TestRedisPollStoreDeadLetterLifecycle(t *testing.T) {
+	store, closeFunc := setupRedisPollStore(t)
+	defer closeFunc()
+
+	entry := types.DeadLetterEntry{
+		Message:     types.ScoringMessage{TriggerUser: "octocat"},
+		EnvBaseTime: time.Now().Round(0),
+		Error:       "boom",
+		CreatedOn:   time.Now().Round(0),
+		LastAttempt: time.Now().Round(0),
+	}
+	assert.NoError(t, store.InsertDeadLetter(&entry))
+	assert.NotEmpty(t, entry.Id)
+
+	second := types.DeadLetterEntry{Message: types.ScoringMessage{TriggerUser: "hubot"}}
+	assert.NoError(t, store.InsertDeadLetter(&second))
+	assert.NotEqual(t, entry.Id, second.Id)
+
+	entries, err := store.SelectDeadLetters()
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, entry.Id, entries[0].Id)
+	assert.Equal(t, "boom", entries[0].Error)
+
+	assert.NoError(t, store.UpdateDeadLetterRetry(entry.Id, time.Now().Round(0), errors.New("retry failed")))
+	entries, err = store.SelectDeadLetters()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, entries[0].RetryCount)
+	assert.Equal(t, "retry failed", entries[0].Error)
+
+	assert.NoError(t, store.DeleteDeadLetter(entry.Id))
+	entries, err = store.SelectDeadLetters()
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, second.Id, entries[0].Id)
+}
@@ -0,0 +1,48 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap/zaptest"
+)
+
+// TestWithTracerOptionDoesNotAlterBehavior exercises NewDBPoll's WithTracer option against a
+// real (noop) TracerProvider, to make sure spanning a call doesn't change what it returns.
+func TestWithTracerOptionDoesNotAlterBehavior(t *testing.T) {
+	sqlDb, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer sqlDb.Close()
+
+	pollDb := NewDBPoll(sqlDb, zaptest.NewLogger(t), WithTracer(trace.NewNoopTracerProvider()))
+
+	mock.ExpectExec(PollConvertSqlToDbMockExpect(sqlUpdatePoll)).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), PollId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	poll := pollDb.NewPoll()
+	assert.NoError(t, pollDb.UpdatePoll(context.Background(), &poll))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -20,22 +20,52 @@
 package db
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"time"
+
 	"github.com/sonatype-nexus-community/bbash/internal/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
+// IDBPoll intentionally has no AcquireLease/RenewLease/ReleaseLease API of its own: "only one
+// replica runs a poll tick at a time" is already solved, independently of whichever IDBPoll
+// backend (PollStruct, MongoPollStore, RedisPollStore) a deployment configures, by
+// internal/leader's Elector (PostgresElector's advisory lock, or RedisElector's SETNX+TTL lease -
+// see leader.New). The poll scheduler (poll.ChaseTail) acquires that lease once per tick and skips
+// the tick body entirely when it isn't held; adding a second, competing lease mechanism here would
+// just be two locks guarding the same critical section, the same reasoning RedisPollStore's doc
+// comment gives for not adding its own SETNX+TTL lock.
+//
+//go:generate mockery --name IDBPoll --output ../mocks --outpkg mocks
 type IDBPoll interface {
 	GetLogger() *zap.Logger
 	NewPoll() types.Poll
-	UpdatePoll(poll *types.Poll) (err error)
-	SelectPoll(poll *types.Poll) (err error)
+
+	// UpdatePoll and SelectPoll take ctx so a caller (the poll scheduler, or an HTTP handler like
+	// setPollDate) can bound how long a slow DB waits to respond - e.g. with the per-poll deadline
+	// PollStruct's WithPollDeadline option configures, or the handler's own request context.
+	UpdatePoll(ctx context.Context, poll *types.Poll) (err error)
+	SelectPoll(ctx context.Context, poll *types.Poll) (err error)
+
+	InsertDeadLetter(entry *types.DeadLetterEntry) (err error)
+	SelectDeadLetters() (entries []types.DeadLetterEntry, err error)
+	UpdateDeadLetterRetry(id string, lastAttempt time.Time, retryErr error) (err error)
+	DeleteDeadLetter(id string) (err error)
+
+	// ComputeNextRun returns when poll.Schedule should next fire after now; see db.ComputeNextRun.
+	ComputeNextRun(poll *types.Poll, now time.Time) (time.Time, error)
 }
 
 type PollStruct struct {
-	db     *sql.DB
-	logger *zap.Logger
+	db           *sql.DB
+	logger       *zap.Logger
+	tracer       trace.Tracer
+	pollDeadline time.Duration
 }
 
 func (p *PollStruct) GetLogger() *zap.Logger {
@@ -45,8 +75,46 @@ func (p *PollStruct) GetLogger() *zap.Logger {
 // enforce implementation of interface
 var _ IDBPoll = (*PollStruct)(nil)
 
-func NewDBPoll(db *sql.DB, logger *zap.Logger) *PollStruct {
-	return &PollStruct{db: db, logger: logger}
+// Option configures a PollStruct built by NewDBPoll.
+type Option func(*PollStruct)
+
+// WithTracer spans every PollStruct SQL operation under tp instead of the no-op default, so
+// polling anomalies (e.g. the "wrong number of rows" UpdatePoll error) can be correlated with
+// upstream HTTP spans coming out of the Echo handlers.
+func WithTracer(tp trace.TracerProvider) Option {
+	return func(p *PollStruct) {
+		p.tracer = tp.Tracer("github.com/sonatype-nexus-community/bbash/internal/db")
+	}
+}
+
+// WithPollDeadline bounds every SelectPoll/UpdatePoll call to at most d, on top of whatever
+// deadline the caller's ctx already carries - so a slow poll table (contended advisory lock,
+// unhealthy replica) can't block a poll tick, or the HTTP handlers that read/write it, indefinitely.
+// The zero value (the default) leaves the caller's ctx as the only deadline.
+func WithPollDeadline(d time.Duration) Option {
+	return func(p *PollStruct) {
+		p.pollDeadline = d
+	}
+}
+
+func NewDBPoll(db *sql.DB, logger *zap.Logger, opts ...Option) *PollStruct {
+	p := &PollStruct{db: db, logger: logger, tracer: trace.NewNoopTracerProvider().Tracer("")}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// startSpan begins a span for a PollStruct SQL operation, tagged per OpenTelemetry's semantic
+// conventions for database calls plus bbash.poll_id (there's only ever one poll row, see PollId,
+// but the attribute future-proofs this against that changing).
+func (p *PollStruct) startSpan(ctx context.Context, operation, statement string) (context.Context, trace.Span) {
+	return p.tracer.Start(ctx, "db.poll."+operation, trace.WithAttributes(
+		attribute.String("db.system", "postgresql"),
+		attribute.String("db.statement", statement),
+		attribute.String("db.operation", operation),
+		attribute.String("bbash.poll_id", PollId),
+	))
 }
 
 // PollId there can be only one
@@ -62,48 +130,201 @@ func (p *PollStruct) NewPoll() types.Poll {
 }
 
 const sqlUpdatePoll = `UPDATE poll
-		SET 
-			last_polled_on=$1, 
-			env_base_time=$2, 
-			last_poll_completed=$3
-		WHERE poll_instance=$4`
+		SET
+			last_polled_on=$1,
+			env_base_time=$2,
+			last_poll_completed=$3,
+			leader_instance=$4,
+			schedule=$5
+		WHERE poll_instance=$6`
+
+// withPollDeadline derives a child of ctx bounded by p.pollDeadline (a no-op if pollDeadline is
+// zero), so SelectPoll/UpdatePoll's query can't outlive it even if ctx itself has no deadline of
+// its own.
+func (p *PollStruct) withPollDeadline(ctx context.Context) (context.Context, context.CancelFunc) {
+	if p.pollDeadline <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, p.pollDeadline)
+}
+
+func (p *PollStruct) UpdatePoll(ctx context.Context, poll *types.Poll) (err error) {
+	ctx, cancel := p.withPollDeadline(ctx)
+	defer cancel()
+
+	ctx, span := p.startSpan(ctx, "UpdatePoll", sqlUpdatePoll)
+	defer span.End()
 
-func (p *PollStruct) UpdatePoll(poll *types.Poll) (err error) {
 	var res sql.Result
-	res, err = p.db.Exec(sqlUpdatePoll, poll.LastPolled, poll.EnvBaseTime, poll.LastPollCompleted, poll.Id)
+	res, err = p.db.ExecContext(ctx, sqlUpdatePoll, poll.LastPolled, poll.EnvBaseTime, poll.LastPollCompleted, poll.LeaderInstance, poll.Schedule, poll.Id)
 	if err != nil {
+		span.RecordError(err)
 		return
 	}
 
 	var rowsAffected int64
 	rowsAffected, err = res.RowsAffected()
 	if err != nil {
+		span.RecordError(err)
 		return
 	}
 	if rowsAffected != 1 {
 		err = fmt.Errorf("update poll updated wrong number of rows: %d, poll %+v", rowsAffected, poll)
+		span.AddEvent("wrong number of rows", trace.WithAttributes(attribute.Int64("db.rows_affected", rowsAffected)))
+		span.RecordError(err)
 	}
 	return
 }
 
-const sqlSelectPoll = `SELECT 
-			last_polled_on, 
-			env_base_time, 
-			last_poll_completed
+const sqlSelectPoll = `SELECT
+			last_polled_on,
+			env_base_time,
+			last_poll_completed,
+			leader_instance,
+			schedule
         FROM poll
 		WHERE poll_instance=$1`
 
-func (p *PollStruct) SelectPoll(poll *types.Poll) (err error) {
-	row := p.db.QueryRow(sqlSelectPoll, poll.Id)
+func (p *PollStruct) SelectPoll(ctx context.Context, poll *types.Poll) (err error) {
+	ctx, cancel := p.withPollDeadline(ctx)
+	defer cancel()
+
+	ctx, span := p.startSpan(ctx, "SelectPoll", sqlSelectPoll)
+	defer span.End()
+
+	row := p.db.QueryRowContext(ctx, sqlSelectPoll, poll.Id)
 
 	err = row.Scan(
 		&poll.LastPolled,
 		&poll.EnvBaseTime,
 		&poll.LastPollCompleted,
+		&poll.LeaderInstance,
+		&poll.Schedule,
 	)
 	if err != nil {
+		span.RecordError(err)
 		p.logger.Error("selectPoll scan error", zap.Error(err))
 		return
 	}
+
+	poll.NextRunAt, err = p.ComputeNextRun(poll, time.Now())
+	return
+}
+
+// ComputeNextRun returns when poll.Schedule should next fire after now; see db.ComputeNextRun.
+func (p *PollStruct) ComputeNextRun(poll *types.Poll, now time.Time) (time.Time, error) {
+	return ComputeNextRun(poll, now)
+}
+
+const sqlInsertDeadLetter = `INSERT INTO dead_letter
+		(message, env_base_time, error, retry_count, created_on, last_attempt)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id`
+
+// InsertDeadLetter records a ScoringMessage that processScoringMessage failed to apply, along with
+// the error that caused the failure, so it can be inspected and replayed later instead of blocking
+// the rest of the poll tick it arrived in.
+func (p *PollStruct) InsertDeadLetter(entry *types.DeadLetterEntry) (err error) {
+	ctx, span := p.startSpan(context.Background(), "InsertDeadLetter", sqlInsertDeadLetter)
+	defer span.End()
+
+	var messageJson []byte
+	messageJson, err = json.Marshal(entry.Message)
+	if err != nil {
+		span.RecordError(err)
+		return
+	}
+
+	err = p.db.QueryRowContext(
+		ctx,
+		sqlInsertDeadLetter,
+		messageJson,
+		entry.EnvBaseTime,
+		entry.Error,
+		entry.RetryCount,
+		entry.CreatedOn,
+		entry.LastAttempt,
+	).Scan(&entry.Id)
+	if err != nil {
+		span.RecordError(err)
+	}
+	return
+}
+
+const sqlSelectDeadLetters = `SELECT
+			id,
+			message,
+			env_base_time,
+			error,
+			retry_count,
+			created_on,
+			last_attempt
+		FROM dead_letter
+		ORDER BY created_on ASC`
+
+// SelectDeadLetters returns every dead-lettered ScoringMessage, oldest first.
+func (p *PollStruct) SelectDeadLetters() (entries []types.DeadLetterEntry, err error) {
+	ctx, span := p.startSpan(context.Background(), "SelectDeadLetters", sqlSelectDeadLetters)
+	defer span.End()
+
+	var rows *sql.Rows
+	rows, err = p.db.QueryContext(ctx, sqlSelectDeadLetters)
+	if err != nil {
+		span.RecordError(err)
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		entry := types.DeadLetterEntry{}
+		var messageJson []byte
+		if err = rows.Scan(&entry.Id, &messageJson, &entry.EnvBaseTime, &entry.Error, &entry.RetryCount, &entry.CreatedOn, &entry.LastAttempt); err != nil {
+			span.RecordError(err)
+			return
+		}
+		if err = json.Unmarshal(messageJson, &entry.Message); err != nil {
+			span.RecordError(err)
+			return
+		}
+		entries = append(entries, entry)
+	}
+	return
+}
+
+const sqlUpdateDeadLetterRetry = `UPDATE dead_letter
+		SET retry_count = retry_count + 1,
+			last_attempt = $1,
+			error = $2
+		WHERE id = $3`
+
+// UpdateDeadLetterRetry records a failed (or retried) replay attempt for the dead letter entry
+// identified by id, incrementing its retry count and storing the latest error.
+func (p *PollStruct) UpdateDeadLetterRetry(id string, lastAttempt time.Time, retryErr error) (err error) {
+	ctx, span := p.startSpan(context.Background(), "UpdateDeadLetterRetry", sqlUpdateDeadLetterRetry)
+	defer span.End()
+
+	errMsg := ""
+	if retryErr != nil {
+		errMsg = retryErr.Error()
+	}
+	_, err = p.db.ExecContext(ctx, sqlUpdateDeadLetterRetry, lastAttempt, errMsg, id)
+	if err != nil {
+		span.RecordError(err)
+	}
+	return
+}
+
+const sqlDeleteDeadLetter = `DELETE FROM dead_letter WHERE id = $1`
+
+// DeleteDeadLetter discards the dead letter entry identified by id, e.g. after a successful replay
+// or an operator decision to give up on it.
+func (p *PollStruct) DeleteDeadLetter(id string) (err error) {
+	ctx, span := p.startSpan(context.Background(), "DeleteDeadLetter", sqlDeleteDeadLetter)
+	defer span.End()
+
+	_, err = p.db.ExecContext(ctx, sqlDeleteDeadLetter, id)
+	if err != nil {
+		span.RecordError(err)
+	}
 	return
 }
@@ -21,6 +21,7 @@ package db
 
 import (
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sonatype-nexus-community/bbash/internal/types"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap/zaptest"
 	"testing"
@@ -28,7 +29,8 @@ import (
 )
 
 // SetupMockDBPoll should always be followed by a call to the closeDbFunc, like so:
-// 	mock, db, closeDbFunc := SetupMockDBPoll(t)
+//
+//	mock, db, closeDbFunc := SetupMockDBPoll(t)
 //	defer closeDbFunc()
 func SetupMockDBPoll(t *testing.T) (mock sqlmock.Sqlmock, mockDbPoll *PollStruct, closeDbFunc func()) {
 	db, mock, err := sqlmock.New()
@@ -53,27 +55,76 @@ func SetupMockPollSelectForcedError(mock sqlmock.Sqlmock, forcedError error, pol
 		WillReturnError(forcedError)
 }
 
-func setupMockPollSelect(mock sqlmock.Sqlmock, pollId string, now time.Time) {
+func SetupMockPollSelect(mock sqlmock.Sqlmock, pollId string, now time.Time) {
 	mock.ExpectQuery(PollConvertSqlToDbMockExpect(sqlSelectPoll)).
 		WithArgs(pollId).
-		WillReturnRows(sqlmock.NewRows([]string{"lastpoll", "basetime", "pollcompleted"}).
-			AddRow(now, now.Add(time.Second*1), now.Add(time.Second*2)))
+		WillReturnRows(sqlmock.NewRows([]string{"lastpoll", "basetime", "pollcompleted", "leaderinstance", "schedule"}).
+			AddRow(now, now.Add(time.Second*1), now.Add(time.Second*2), "", ""))
+}
+
+// SetupMockPollSelectWithDelay is SetupMockPollSelect, but the mocked query doesn't return until
+// delay has elapsed - for exercising a SelectPoll caller's own ctx cancellation or
+// PollStruct.WithPollDeadline the way TestSelectPollContextCancelled/
+// TestSelectPollRespectsCallerContext already do inline; this promotes that pattern to a reusable
+// helper.
+//
+// This doesn't take sql.Named arguments the way the request asking for this helper wanted, because
+// lib/pq (the only driver this package's SQL runs against) only ever recognizes ordinal `$1, $2,
+// ...` placeholders; it ignores a driver.NamedValue's Name entirely; migrating sqlSelectPoll's
+// `WHERE poll_instance=$1` to a named `:pollId` placeholder isn't something lib/pq can execute, so
+// there's nothing for a caller to "opt in" to here. sqlmock itself matches WithArgs(...) positionally
+// regardless of whether the caller passes plain values or sql.Named ones, so positional args (as
+// every other helper in this file already uses) remain the only form that matters for this driver.
+func SetupMockPollSelectWithDelay(mock sqlmock.Sqlmock, pollId string, now time.Time, delay time.Duration) {
+	mock.ExpectQuery(PollConvertSqlToDbMockExpect(sqlSelectPoll)).
+		WithArgs(pollId).
+		WillDelayFor(delay).
+		WillReturnRows(sqlmock.NewRows([]string{"lastpoll", "basetime", "pollcompleted", "leaderinstance", "schedule"}).
+			AddRow(now, now.Add(time.Second*1), now.Add(time.Second*2), "", ""))
 }
 
 func SetupMockPollSelectAndUpdate(mock sqlmock.Sqlmock, pollId string, now time.Time, rowsAffected int64) {
-	setupMockPollSelect(mock, pollId, now)
+	SetupMockPollSelect(mock, pollId, now)
 
 	// expect call to UpdatePoll too
 	mock.ExpectExec(PollConvertSqlToDbMockExpect(sqlUpdatePoll)).
-		WithArgs(now, sqlmock.AnyArg(), sqlmock.AnyArg(), pollId).
+		WithArgs(now, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), pollId).
 		WillReturnResult(sqlmock.NewResult(0, rowsAffected))
 }
 
 func SetupMockPollSelectAndUpdateAnyUpdateTime(mock sqlmock.Sqlmock, pollId string, now time.Time, rowsAffected int64) {
-	setupMockPollSelect(mock, pollId, now)
+	SetupMockPollSelect(mock, pollId, now)
 
 	// expect call to UpdatePoll too
 	mock.ExpectExec(PollConvertSqlToDbMockExpect(sqlUpdatePoll)).
-		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), pollId).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), pollId).
 		WillReturnResult(sqlmock.NewResult(0, rowsAffected))
 }
+
+// SetupMockInsertDeadLetter expects a single InsertDeadLetter call for any message/timestamps and
+// returns deadLetterId for it.
+func SetupMockInsertDeadLetter(mock sqlmock.Sqlmock, deadLetterId string) {
+	mock.ExpectQuery(PollConvertSqlToDbMockExpect(sqlInsertDeadLetter)).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(deadLetterId))
+}
+
+// SetupMockPollHistoryInsert expects a single RecordPollRun call for any source/timestamps/error
+// and returns runId for it.
+func SetupMockPollHistoryInsert(mock sqlmock.Sqlmock, runId string) {
+	mock.ExpectQuery(PollConvertSqlToDbMockExpect(sqlInsertPollHistory)).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(runId))
+}
+
+// SetupMockPollHistorySelect expects a single SelectRecentPolls(limit) call and returns runs for
+// it, newest first (the order SelectRecentPolls itself queries for).
+func SetupMockPollHistorySelect(mock sqlmock.Sqlmock, limit int, runs ...types.PollRun) {
+	rows := sqlmock.NewRows([]string{"id", "source", "started_on", "duration_ms", "rows_scraped", "error"})
+	for _, run := range runs {
+		rows.AddRow(run.Id, run.Source, run.StartedOn, run.Duration.Milliseconds(), run.RowsScraped, run.Error)
+	}
+	mock.ExpectQuery(PollConvertSqlToDbMockExpect(sqlSelectRecentPollHistory)).
+		WithArgs(limit).
+		WillReturnRows(rows)
+}
@@ -20,6 +20,7 @@
 package db
 
 import (
+	"context"
 	"fmt"
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/sonatype-nexus-community/bbash/internal/types"
@@ -51,10 +52,10 @@ func TestUpdatePollError(t *testing.T) {
 	poll := types.Poll{}
 	forcedError := fmt.Errorf("forced poll error")
 	mock.ExpectExec(convertSqlToDbMockExpect(sqlUpdatePoll)).
-		WithArgs(poll.LastPolled, poll.EnvBaseTime, poll.LastPollCompleted, poll.Id).
+		WithArgs(poll.LastPolled, poll.EnvBaseTime, poll.LastPollCompleted, poll.LeaderInstance, poll.Schedule, poll.Id).
 		WillReturnError(forcedError)
 
-	err := db.UpdatePoll(&poll)
+	err := db.UpdatePoll(context.Background(), &poll)
 	assert.EqualError(t, err, forcedError.Error())
 }
 
@@ -65,10 +66,10 @@ func TestUpdatePollRowsAffectedError(t *testing.T) {
 	poll := types.Poll{}
 	forcedError := fmt.Errorf("forced poll error")
 	mock.ExpectExec(convertSqlToDbMockExpect(sqlUpdatePoll)).
-		WithArgs(poll.LastPolled, poll.EnvBaseTime, poll.LastPollCompleted, poll.Id).
+		WithArgs(poll.LastPolled, poll.EnvBaseTime, poll.LastPollCompleted, poll.LeaderInstance, poll.Schedule, poll.Id).
 		WillReturnResult(sqlmock.NewErrorResult(forcedError))
 
-	err := db.UpdatePoll(&poll)
+	err := db.UpdatePoll(context.Background(), &poll)
 	assert.EqualError(t, err, forcedError.Error())
 }
 
@@ -78,10 +79,10 @@ func TestUpdatePollInvalidId(t *testing.T) {
 
 	poll := types.Poll{}
 	mock.ExpectExec(convertSqlToDbMockExpect(sqlUpdatePoll)).
-		WithArgs(poll.LastPolled, poll.EnvBaseTime, poll.LastPollCompleted, poll.Id).
+		WithArgs(poll.LastPolled, poll.EnvBaseTime, poll.LastPollCompleted, poll.LeaderInstance, poll.Schedule, poll.Id).
 		WillReturnResult(sqlmock.NewResult(0, 0))
 
-	err := db.UpdatePoll(&poll)
+	err := db.UpdatePoll(context.Background(), &poll)
 	assert.True(t, strings.HasPrefix(err.Error(), "update poll updated wrong number of rows: 0, poll "))
 }
 
@@ -95,12 +96,13 @@ func TestUpdatePoll(t *testing.T) {
 		LastPolled:        now,
 		EnvBaseTime:       now.Add(time.Second * 1),
 		LastPollCompleted: now.Add(time.Second * 2),
+		LeaderInstance:    "theInstance",
 	}
 	mock.ExpectExec(convertSqlToDbMockExpect(sqlUpdatePoll)).
-		WithArgs(poll.LastPolled, poll.EnvBaseTime, poll.LastPollCompleted, poll.Id).
+		WithArgs(poll.LastPolled, poll.EnvBaseTime, poll.LastPollCompleted, poll.LeaderInstance, poll.Schedule, poll.Id).
 		WillReturnResult(sqlmock.NewResult(0, 1))
 
-	assert.NoError(t, db.UpdatePoll(&poll))
+	assert.NoError(t, db.UpdatePoll(context.Background(), &poll))
 }
 
 func TestSelectPollError(t *testing.T) {
@@ -113,7 +115,7 @@ func TestSelectPollError(t *testing.T) {
 	forcedError := fmt.Errorf("forced select poll error")
 	SetupMockPollSelectForcedError(mock, forcedError, poll.Id)
 
-	assert.EqualError(t, db.SelectPoll(&poll), forcedError.Error())
+	assert.EqualError(t, db.SelectPoll(context.Background(), &poll), forcedError.Error())
 }
 
 func TestSelectPollInvalidId(t *testing.T) {
@@ -125,9 +127,9 @@ func TestSelectPollInvalidId(t *testing.T) {
 	}
 	mock.ExpectQuery(convertSqlToDbMockExpect(sqlSelectPoll)).
 		WithArgs(poll.Id).
-		WillReturnRows(sqlmock.NewRows([]string{"lastpoll", "basetime", "pollcompleted"}))
+		WillReturnRows(sqlmock.NewRows([]string{"lastpoll", "basetime", "pollcompleted", "leaderinstance"}))
 
-	assert.EqualError(t, db.SelectPoll(&poll), "sql: no rows in result set")
+	assert.EqualError(t, db.SelectPoll(context.Background(), &poll), "sql: no rows in result set")
 }
 
 func TestSelectPoll(t *testing.T) {
@@ -140,7 +142,7 @@ func TestSelectPoll(t *testing.T) {
 	}
 	SetupMockPollSelect(mock, poll.Id, now)
 
-	assert.NoError(t, db.SelectPoll(&poll))
+	assert.NoError(t, db.SelectPoll(context.Background(), &poll))
 	assert.Equal(t, types.Poll{
 		Id:                "-1",
 		LastPolled:        now,
@@ -148,3 +150,176 @@ func TestSelectPoll(t *testing.T) {
 		LastPollCompleted: now.Add(time.Second * 2),
 	}, poll)
 }
+
+// TestSelectPollContextCancelled exercises WithPollDeadline: a query sqlmock delays past the
+// configured deadline must return promptly (sqlmock.ErrCancelled, its stand-in for what a real
+// driver returns once ctx fires mid-query) rather than blocking until the delayed query responds.
+func TestSelectPollContextCancelled(t *testing.T) {
+	sqlDb, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer sqlDb.Close()
+
+	pollDb := NewDBPoll(sqlDb, zaptest.NewLogger(t), WithPollDeadline(10*time.Millisecond))
+
+	poll := types.Poll{Id: "-1"}
+	mock.ExpectQuery(convertSqlToDbMockExpect(sqlSelectPoll)).
+		WithArgs(poll.Id).
+		WillDelayFor(time.Second).
+		WillReturnRows(sqlmock.NewRows([]string{"lastpoll", "basetime", "pollcompleted", "leaderinstance", "schedule"}).
+			AddRow(time.Now(), time.Now(), time.Now(), "", ""))
+
+	start := time.Now()
+	err = pollDb.SelectPoll(context.Background(), &poll)
+	assert.ErrorIs(t, err, sqlmock.ErrCancelled)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+// TestSelectPollRespectsCallerContext is the same as TestSelectPollContextCancelled but without a
+// WithPollDeadline option, to show the caller's own ctx cancellation is honored even when no
+// per-poll deadline is configured.
+func TestSelectPollRespectsCallerContext(t *testing.T) {
+	sqlDb, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer sqlDb.Close()
+
+	pollDb := NewDBPoll(sqlDb, zaptest.NewLogger(t))
+
+	poll := types.Poll{Id: "-1"}
+	mock.ExpectQuery(convertSqlToDbMockExpect(sqlSelectPoll)).
+		WithArgs(poll.Id).
+		WillDelayFor(time.Second).
+		WillReturnRows(sqlmock.NewRows([]string{"lastpoll", "basetime", "pollcompleted", "leaderinstance", "schedule"}).
+			AddRow(time.Now(), time.Now(), time.Now(), "", ""))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err = pollDb.SelectPoll(ctx, &poll)
+	assert.ErrorIs(t, err, sqlmock.ErrCancelled)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+// TestSetupMockPollSelectWithDelayRoundTrips exercises the SetupMockPollSelectWithDelay helper
+// itself: a deadline longer than the configured delay returns the expected poll.
+// TestSetupMockPollSelectWithDelayCancelled below covers a deadline shorter than the delay, which
+// surfaces sqlmock.ErrCancelled.
+func TestSetupMockPollSelectWithDelayRoundTrips(t *testing.T) {
+	mock, db, closeDbFunc := SetupMockDBPoll(t)
+	defer closeDbFunc()
+
+	now := time.Now()
+	poll := types.Poll{Id: "-1"}
+	SetupMockPollSelectWithDelay(mock, poll.Id, now, 10*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, db.SelectPoll(ctx, &poll))
+	assert.True(t, now.Equal(poll.LastPolled))
+}
+
+func TestSetupMockPollSelectWithDelayCancelled(t *testing.T) {
+	mock, db, closeDbFunc := SetupMockDBPoll(t)
+	defer closeDbFunc()
+
+	poll := types.Poll{Id: "-1"}
+	SetupMockPollSelectWithDelay(mock, poll.Id, time.Now(), time.Second)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := db.SelectPoll(ctx, &poll)
+	assert.ErrorIs(t, err, sqlmock.ErrCancelled)
+}
+
+func TestInsertDeadLetter(t *testing.T) {
+	mock, db, closeDbFunc := SetupMockDBPoll(t)
+	defer closeDbFunc()
+
+	now := time.Now()
+	entry := types.DeadLetterEntry{
+		Message:     types.ScoringMessage{TriggerUser: "theTriggerUser"},
+		EnvBaseTime: now,
+		Error:       "forced process error",
+		CreatedOn:   now,
+		LastAttempt: now,
+	}
+	mock.ExpectQuery(convertSqlToDbMockExpect(sqlInsertDeadLetter)).
+		WithArgs(sqlmock.AnyArg(), entry.EnvBaseTime, entry.Error, entry.RetryCount, entry.CreatedOn, entry.LastAttempt).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("theDeadLetterId"))
+
+	assert.NoError(t, db.InsertDeadLetter(&entry))
+	assert.Equal(t, "theDeadLetterId", entry.Id)
+}
+
+func TestInsertDeadLetterError(t *testing.T) {
+	mock, db, closeDbFunc := SetupMockDBPoll(t)
+	defer closeDbFunc()
+
+	entry := types.DeadLetterEntry{}
+	forcedError := fmt.Errorf("forced insert dead letter error")
+	mock.ExpectQuery(convertSqlToDbMockExpect(sqlInsertDeadLetter)).
+		WithArgs(sqlmock.AnyArg(), entry.EnvBaseTime, entry.Error, entry.RetryCount, entry.CreatedOn, entry.LastAttempt).
+		WillReturnError(forcedError)
+
+	assert.EqualError(t, db.InsertDeadLetter(&entry), forcedError.Error())
+}
+
+func TestSelectDeadLetters(t *testing.T) {
+	mock, db, closeDbFunc := SetupMockDBPoll(t)
+	defer closeDbFunc()
+
+	now := time.Now()
+	mock.ExpectQuery(convertSqlToDbMockExpect(sqlSelectDeadLetters)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "message", "env_base_time", "error", "retry_count", "created_on", "last_attempt"}).
+			AddRow("theDeadLetterId", []byte(`{"triggerUser":"theTriggerUser"}`), now, "forced process error", 1, now, now))
+
+	entries, err := db.SelectDeadLetters()
+	assert.NoError(t, err)
+	assert.Equal(t, []types.DeadLetterEntry{
+		{
+			Id:          "theDeadLetterId",
+			Message:     types.ScoringMessage{TriggerUser: "theTriggerUser"},
+			EnvBaseTime: now,
+			Error:       "forced process error",
+			RetryCount:  1,
+			CreatedOn:   now,
+			LastAttempt: now,
+		},
+	}, entries)
+}
+
+func TestSelectDeadLettersError(t *testing.T) {
+	mock, db, closeDbFunc := SetupMockDBPoll(t)
+	defer closeDbFunc()
+
+	forcedError := fmt.Errorf("forced select dead letters error")
+	mock.ExpectQuery(convertSqlToDbMockExpect(sqlSelectDeadLetters)).
+		WillReturnError(forcedError)
+
+	_, err := db.SelectDeadLetters()
+	assert.EqualError(t, err, forcedError.Error())
+}
+
+func TestUpdateDeadLetterRetry(t *testing.T) {
+	mock, db, closeDbFunc := SetupMockDBPoll(t)
+	defer closeDbFunc()
+
+	now := time.Now()
+	retryErr := fmt.Errorf("still failing")
+	mock.ExpectExec(convertSqlToDbMockExpect(sqlUpdateDeadLetterRetry)).
+		WithArgs(now, retryErr.Error(), "theDeadLetterId").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, db.UpdateDeadLetterRetry("theDeadLetterId", now, retryErr))
+}
+
+func TestDeleteDeadLetter(t *testing.T) {
+	mock, db, closeDbFunc := SetupMockDBPoll(t)
+	defer closeDbFunc()
+
+	mock.ExpectExec(convertSqlToDbMockExpect(sqlDeleteDeadLetter)).
+		WithArgs("theDeadLetterId").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, db.DeleteDeadLetter("theDeadLetterId"))
+}
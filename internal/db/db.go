@@ -20,33 +20,111 @@
 package db
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
+	"fmt"
 	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database"
 	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/robfig/cron/v3"
 	"github.com/sonatype-nexus-community/bbash/internal/types"
 	"go.uber.org/zap"
+	"io"
+	"strconv"
+	"strings"
 	"time"
 )
 
+//go:generate mockery --name IScoreDB --output ../mocks --outpkg mocks
 type IScoreDB interface {
 	SelectPriorScore(participantToScore *types.ParticipantStruct, msg *types.ScoringMessage) (oldPoints float64)
 	InsertScoringEvent(participantToScore *types.ParticipantStruct, msg *types.ScoringMessage, newPoints float64) (err error)
 	UpdateParticipantScore(participant *types.ParticipantStruct, delta float64) (err error)
+
+	// UpdateParticipantScoreCAS applies delta the same way UpdateParticipantScore does, but only if
+	// participant's Score still equals expectedScore at update time; see UpdateParticipantScoreCAS's
+	// doc comment for why a caller needs this instead.
+	UpdateParticipantScoreCAS(participant *types.ParticipantStruct, expectedScore int, delta float64) (swapped bool, err error)
+
+	// ApplyScoringEvent combines InsertScoringEvent and UpdateParticipantScoreCAS into a single
+	// transaction; see ApplyScoringEvent's doc comment for the atomicity gap this closes.
+	ApplyScoringEvent(participantToScore *types.ParticipantStruct, msg *types.ScoringMessage, newPoints, delta float64, expectedScore int) (swapped bool, err error)
+
+	// EvaluateScoringPolicy is the anti-cheat gate a caller runs before ApplyScoringEvent; see its
+	// doc comment for which scoring_policy limits are actually enforced today.
+	EvaluateScoringPolicy(participantToScore *types.ParticipantStruct, msg *types.ScoringMessage) (allowed bool, reason string, err error)
+
+	// InsertScoringEventWithHash is InsertScoringEvent plus a commit/diff fingerprint; see its doc
+	// comment.
+	InsertScoringEventWithHash(participantToScore *types.ParticipantStruct, msg *types.ScoringMessage, newPoints float64, commitSHA, diffHash string) (err error)
+
+	// UpsertPendingScoringEvent returns the types.ScoringEventStruct for dedupId, inserting it in
+	// ScoringEventPending status if this is the first time dedupId has been seen. A caller that gets
+	// back a row already past ScoringEventPending/ScoringEventValidated (e.g. ScoringEventScored) is
+	// looking at a redelivered message and should short-circuit instead of scoring it again.
+	UpsertPendingScoringEvent(dedupId string) (event *types.ScoringEventStruct, err error)
+
+	// UpdateScoringEventStatus moves id from status from to status to, recording reason and points,
+	// guarded by a WHERE ... AND status = from the same way UpdateParticipantScoreCAS guards on
+	// expectedScore - so a transition that's already happened (e.g. a concurrent sweep expiring it)
+	// doesn't get silently clobbered. updated is false if id wasn't in status from when this ran.
+	UpdateScoringEventStatus(id string, from, to types.ScoringEventStatus, points float64, reason string) (updated bool, err error)
+
+	// ExpireStuckScoringEvents moves every ScoringEventPending/ScoringEventValidated row older than
+	// now.Add(-ttl) to ScoringEventExpired, so a delivery that crashed mid-flight doesn't block that
+	// dedup ID from ever being retried under a fresh event. It's intended to run once per poll tick,
+	// the same cadence retryDeadLetters already runs at.
+	ExpireStuckScoringEvents(ttl time.Duration, now time.Time) (expired int64, err error)
+
+	// RevokeScoringEvent/IsScoringEventRevoked/ReplayParticipantScore let an admin undo an accidental
+	// bounty without deleting scoring_event history; see scoring_revocation.go's doc comments.
+	RevokeScoringEvent(campaignName, scpName, repoOwner, repoName string, pr int, reason, actor string) (err error)
+	IsScoringEventRevoked(campaignName, scpName, repoOwner, repoName string, pr int) (revoked bool, err error)
+	ReplayParticipantScore(participant *types.ParticipantStruct) (score int, err error)
+
+	// SelectScoringEventsForParticipant lists loginName's non-revoked scoring_event rows for a
+	// participant activity feed; see scoring_history.go's doc comment for why this doesn't take a
+	// since/until time range.
+	SelectScoringEventsForParticipant(campaignName, scpName, loginName string) (events []types.ScoringLedgerEntryStruct, err error)
+
+	// GetDb returns the underlying *sql.DB, for callers (e.g. poll/leader election backends) that
+	// need to share this process's database connection instead of opening their own.
+	GetDb() *sql.DB
 }
 
+//go:generate mockery --name IBBashDB --output ../mocks --outpkg mocks
 type IBBashDB interface {
 	MigrateDB(migrateSourceURL string) error
 
+	// MigrateUp/MigrateDown/MigrateTo/MigrateVersion/MigrateForce/MigrateDryRun round out MigrateDB
+	// with the finer-grained migration surface an operator needs to roll back or inspect a bad
+	// schema change instead of only ever migrating all the way to head - see their doc comments.
+	MigrateUp(migrateSourceURL string, steps int) (err error)
+	MigrateDown(migrateSourceURL string, steps int) (err error)
+	MigrateTo(migrateSourceURL string, version uint) (err error)
+	MigrateVersion(migrateSourceURL string) (version uint, dirty bool, err error)
+	MigrateForce(migrateSourceURL string, version int) (err error)
+	MigrateDryRun(migrateSourceURL string, target uint) (statements []string, err error)
+
 	GetSourceControlProviders() (scps []types.SourceControlProviderStruct, err error)
+	GetSourceControlProvider(scpName string) (scp *types.SourceControlProviderStruct, err error)
 
-	InsertCampaign(campaign *types.CampaignStruct) (guid string, err error)
+	InsertScanProvider(scanProvider *types.ScanProviderStruct) (guid string, err error)
+	GetScanProviders() (scanProviders []types.ScanProviderStruct, err error)
+	DeleteScanProvider(spName string) (rowsAffected int64, err error)
+
+	InsertCampaign(campaign *types.CampaignStruct, actor string) (guid string, err error)
 	UpdateCampaign(campaign *types.CampaignStruct) (guid string, err error)
+	UpdateCampaignState(campaignName string, state string) (err error)
 	GetCampaign(campaignName string) (campaign *types.CampaignStruct, err error)
 	GetCampaigns() (campaigns []types.CampaignStruct, err error)
 	GetActiveCampaigns(now time.Time) (activeCampaigns []types.CampaignStruct, err error)
 
 	InsertOrganization(organization *types.OrganizationStruct) (guid string, err error)
+	InsertOrganizationsTx(organizations []types.OrganizationStruct) (inserted []types.OrganizationStruct, err error)
 	GetOrganizations() (organizations []types.OrganizationStruct, err error)
 	DeleteOrganization(scpName, orgName string) (rowsAffected int64, err error)
 	ValidOrganization(msg *types.ScoringMessage) (orgExists bool, err error)
@@ -56,41 +134,142 @@ type IBBashDB interface {
 	IScoreDB
 
 	InsertParticipant(participant *types.ParticipantStruct) (err error)
+	InsertParticipantsTx(participants []types.ParticipantStruct) (inserted []types.ParticipantStruct, err error)
 	SelectParticipantDetail(campaignName, scpName, loginName string) (participant *types.ParticipantStruct, err error)
 	SelectParticipantsInCampaign(campaignName string) (participants []types.ParticipantStruct, err error)
+
+	// SelectParticipantsInCampaignPaged is SelectParticipantsInCampaign's keyset-paginated,
+	// leaderboard-ordered form; see its doc comment for the page/cursor/total contract.
+	SelectParticipantsInCampaignPaged(campaignName string, opts ListOptions) (page []types.ParticipantStruct, nextCursor string, total int64, err error)
 	UpdateParticipant(participant *types.ParticipantStruct) (rowsAffected int64, err error)
 	DeleteParticipant(campaign, scpName, loginName string) (participantId string, err error)
 	UpdateParticipantTeam(teamName, campaignName, scpName, loginName string) (rowsAffected int64, err error)
 
 	InsertTeam(team *types.TeamStruct) (err error)
+	GetTeam(campaignName, teamName string) (team *types.TeamStruct, err error)
 
 	InsertBug(bug *types.BugStruct) (err error)
+	InsertBugsTx(bugs []types.BugStruct) (inserted []types.BugStruct, err error)
 	UpdateBug(bug *types.BugStruct) (rowsAffected int64, err error)
 	SelectBugs() (bugs []types.BugStruct, err error)
+	SelectBugsByCampaign(campaign string) (bugs []types.BugStruct, err error)
+	DeleteBug(campaign, category string) (rowsAffected int64, err error)
+
+	InsertStopwatchStart(stopwatch *types.StopwatchStruct) (err error)
+	StopStopwatch(participantId, issueRef string, stoppedAt time.Time) (stopwatch *types.StopwatchStruct, err error)
+	SelectCompletedStopwatch(participantId, issueRef string) (stopwatch *types.StopwatchStruct, err error)
+
+	InsertAuthToken(token *types.AuthTokenStruct) (guid string, err error)
+	GetAuthTokenByHash(tokenHash string) (token *types.AuthTokenStruct, err error)
+	ListAuthTokens() (tokens []types.AuthTokenStruct, err error)
+	RevokeAuthToken(guid string, revokedOn time.Time) (rowsAffected int64, err error)
+
+	InsertAdmin(admin *types.AdminStruct) (guid string, err error)
+	GetAdminBySubject(subject string) (admin *types.AdminStruct, err error)
+	ListAdmins() (admins []types.AdminStruct, err error)
+	DeleteAdmin(guid string) (rowsAffected int64, err error)
+
+	InsertAuditEntry(entry *types.AuditEntryStruct) (guid string, err error)
+
+	GetIdempotencyRecord(key, route string) (record *types.IdempotencyRecordStruct, err error)
+	SaveIdempotencyRecord(record *types.IdempotencyRecordStruct) (guid string, err error)
+
+	InsertAPIKey(key *types.APIKeyStruct) (guid string, err error)
+	GetAPIKeyByKeyID(keyId string) (key *types.APIKeyStruct, err error)
+
+	SelectDueOutboxEntries(now time.Time, limit int) (entries []types.OutboxEntryStruct, err error)
+	MarkOutboxEntryDone(id string) (err error)
+	RescheduleOutboxEntry(id string, attempts int, nextAttemptAt time.Time) (err error)
+	CountPendingOutboxEntries() (pending int, err error)
+	MarkOutboxEntryDeadLettered(id string) (err error)
+	SelectDeadLetteredOutboxEntries() (entries []types.OutboxEntryStruct, err error)
+	ReplayOutboxEntry(id string) (err error)
+	SetParticipantUpstreamId(participantId, upstreamId string) (err error)
+
+	InsertSubscription(subscription *types.SubscriptionStruct) (guid string, err error)
+	GetSubscriptions() (subscriptions []types.SubscriptionStruct, err error)
+	DeleteSubscription(id string) (rowsAffected int64, err error)
+
+	SelectDueEventEntries(now time.Time, limit int) (entries []types.EventEntryStruct, err error)
+	MarkEventEntryDone(id string) (err error)
+	RescheduleEventEntry(id string, attempts int, nextAttemptAt time.Time) (err error)
+	CountPendingEventEntries() (pending int, err error)
+
+	InsertAuditEvent(event *types.AuditEventStruct) (guid string, err error)
+	ListAuditEvents(filter types.AuditEventFilter) (events []types.AuditEventStruct, err error)
+
+	GetScoringEvent(guid string) (event *types.ScoringEventStruct, err error)
+
+	// GetLeaderboard and RefreshLeaderboard serve/populate the cached leaderboard table; see
+	// RefreshLeaderboard's doc comment for why it's a full recompute rather than an incremental
+	// update, and StartLeaderboardRefresher for the periodic safety net that calls it.
+	GetLeaderboard(campaignName string, opts ListOptions) (entries []types.LeaderboardEntry, err error)
+	RefreshLeaderboard(campaignName string) (err error)
+	StartLeaderboardRefresher(spec string) (err error)
+	StopLeaderboardRefresher(ctx context.Context) (err error)
 }
 
 type BBashDB struct {
-	db     *sql.DB
-	logger *zap.Logger
+	db      *sql.DB
+	logger  *zap.Logger
+	dialect Dialect
+
+	// leaderboardCron drives StartLeaderboardRefresher/StopLeaderboardRefresher; nil until
+	// StartLeaderboardRefresher is called.
+	leaderboardCron *cron.Cron
 }
 
 // Roll that beautiful bean footage
 var _ IBBashDB = (*BBashDB)(nil)
 
+// New builds a BBashDB against db, assuming DialectPostgres - this application's original,
+// Postgres-only behavior. Callers that resolved a Dialect themselves (see Open, ResolveDialect)
+// should use NewWithDialect instead so MigrateDB picks the matching migrate database driver.
 func New(db *sql.DB, logger *zap.Logger) *BBashDB {
-	return &BBashDB{db: db, logger: logger}
+	postgresDialect, _ := ResolveDialect(DialectPostgres)
+	return NewWithDialect(db, logger, postgresDialect)
 }
 
-func (p *BBashDB) MigrateDB(migrateSourceURL string) (err error) {
+// NewWithDialect builds a BBashDB against db, using dialect to pick MigrateDB's migrate database
+// driver.
+func NewWithDialect(db *sql.DB, logger *zap.Logger, dialect Dialect) *BBashDB {
+	return &BBashDB{db: db, logger: logger, dialect: dialect}
+}
+
+// GetDb returns the underlying *sql.DB.
+func (p *BBashDB) GetDb() *sql.DB {
+	return p.db
+}
+
+// migrateDatabaseDriver builds the golang-migrate database.Driver for p.dialect. Only Postgres is
+// wired up in this build - see the driversAvailable comment on dialects - so every other Dialect
+// (reachable only if a future build vendors its database/sql and golang-migrate drivers) errors
+// here rather than MigrateDB silently running Postgres-flavored migrations against it.
+func (p *BBashDB) migrateDatabaseDriver() (driver database.Driver, err error) {
+	switch p.dialect.Name {
+	case DialectPostgres, "":
+		return postgres.WithInstance(p.db, &postgres.Config{})
+	default:
+		return nil, fmt.Errorf("%s %q has no golang-migrate database driver wired up in this build", EnvDBDriver, p.dialect.Name)
+	}
+}
 
-	driver, err := postgres.WithInstance(p.db, &postgres.Config{})
+// migrateInstance builds the *migrate.Migrate MigrateDB/MigrateUp/MigrateDown/MigrateTo/
+// MigrateVersion/MigrateForce each drive - the migrateSourceURL/migrateDatabaseDriver() wiring
+// MigrateDB used to build inline, pulled out once these other callers needed the same instance.
+func (p *BBashDB) migrateInstance(migrateSourceURL string) (m *migrate.Migrate, err error) {
+	driver, err := p.migrateDatabaseDriver()
 	if err != nil {
 		return
 	}
 
-	m, err := migrate.NewWithDatabaseInstance(
+	return migrate.NewWithDatabaseInstance(
 		migrateSourceURL,
-		"postgres", driver)
+		p.dialect.DriverName, driver)
+}
+
+func (p *BBashDB) MigrateDB(migrateSourceURL string) (err error) {
+	m, err := p.migrateInstance(migrateSourceURL)
 	if err != nil {
 		return
 	}
@@ -104,18 +283,156 @@ func (p *BBashDB) MigrateDB(migrateSourceURL string) (err error) {
 	return
 }
 
-const sqlSelectSourceControlProvider = `SELECT * FROM source_control_provider`
+// MigrateUp runs steps pending "up" migrations, or all of them if steps is 0 - the same all-the-way
+// behavior MigrateDB already has, now also reachable a bounded number of steps at a time so an
+// operator can advance a schema change incrementally instead of straight to head.
+func (p *BBashDB) MigrateUp(migrateSourceURL string, steps int) (err error) {
+	m, err := p.migrateInstance(migrateSourceURL)
+	if err != nil {
+		return
+	}
+
+	if steps == 0 {
+		err = m.Up()
+	} else {
+		err = m.Steps(steps)
+	}
+	if err == migrate.ErrNoChange {
+		err = nil
+	}
+	return
+}
+
+// MigrateDown rolls back steps applied migrations, or all of them if steps is 0. This is the
+// rollback counterpart MigrateDB never exposed, letting an operator undo a bad campaign schema
+// change without shelling into the container to run golang-migrate's CLI directly.
+func (p *BBashDB) MigrateDown(migrateSourceURL string, steps int) (err error) {
+	m, err := p.migrateInstance(migrateSourceURL)
+	if err != nil {
+		return
+	}
+
+	if steps == 0 {
+		err = m.Down()
+	} else {
+		err = m.Steps(-steps)
+	}
+	if err == migrate.ErrNoChange {
+		err = nil
+	}
+	return
+}
+
+// MigrateTo migrates straight to version, up or down as needed - golang-migrate's Migrate(version)
+// picks the direction itself.
+func (p *BBashDB) MigrateTo(migrateSourceURL string, version uint) (err error) {
+	m, err := p.migrateInstance(migrateSourceURL)
+	if err != nil {
+		return
+	}
+
+	if err = m.Migrate(version); err == migrate.ErrNoChange {
+		err = nil
+	}
+	return
+}
+
+// MigrateVersion reports the schema_migrations row MigrateDB/MigrateUp/MigrateDown last left
+// behind. A fresh database that has never been migrated has no such row - golang-migrate reports
+// that as ErrNilVersion, which MigrateVersion translates to version 0, dirty false so callers (the
+// admin endpoint, in particular) don't need to know that sentinel error to read "never migrated".
+func (p *BBashDB) MigrateVersion(migrateSourceURL string) (version uint, dirty bool, err error) {
+	m, err := p.migrateInstance(migrateSourceURL)
+	if err != nil {
+		return
+	}
+
+	version, dirty, err = m.Version()
+	if err == migrate.ErrNilVersion {
+		version, dirty, err = 0, false, nil
+	}
+	return
+}
+
+// MigrateForce marks schema_migrations as version without running any migration, clearing the
+// dirty flag a previous failed migration leaves behind - golang-migrate refuses Up/Down/Migrate
+// against a dirty database, so this is the documented recovery path, not a routine one.
+func (p *BBashDB) MigrateForce(migrateSourceURL string, version int) (err error) {
+	m, err := p.migrateInstance(migrateSourceURL)
+	if err != nil {
+		return
+	}
+
+	return m.Force(version)
+}
+
+// MigrateDryRun reports, without running anything against the database, the identifiers of the
+// migrations MigrateTo(migrateSourceURL, target) would apply from the schema's current version -
+// so an operator can see what a rollback or fast-forward would touch before committing to it.
+// Direction (up or down) is inferred from whether target is above or below the current version.
+func (p *BBashDB) MigrateDryRun(migrateSourceURL string, target uint) (statements []string, err error) {
+	currentVersion, _, err := p.MigrateVersion(migrateSourceURL)
+	if err != nil {
+		return
+	}
+
+	src, err := source.Open(migrateSourceURL)
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = src.Close()
+	}()
+
+	if target >= currentVersion {
+		for v := currentVersion; v < target; {
+			var next uint
+			if v == 0 {
+				if next, err = src.First(); err != nil {
+					return
+				}
+			} else if next, err = src.Next(v); err != nil {
+				return
+			}
+			var identifier string
+			var reader io.ReadCloser
+			if reader, identifier, err = src.ReadUp(next); err != nil {
+				return
+			}
+			_ = reader.Close()
+			statements = append(statements, identifier)
+			v = next
+		}
+		return
+	}
+
+	for v := currentVersion; v > target; {
+		var identifier string
+		var reader io.ReadCloser
+		if reader, identifier, err = src.ReadDown(v); err != nil {
+			return
+		}
+		_ = reader.Close()
+		statements = append(statements, identifier)
+		if v, err = src.Prev(v); err != nil {
+			return
+		}
+	}
+	return
+}
+
+const sqlSelectSourceControlProviders = `SELECT Id, name, url, kind, secret, reporting_token FROM source_control_provider`
 
 func (p *BBashDB) GetSourceControlProviders() (scps []types.SourceControlProviderStruct, err error) {
 	var rows *sql.Rows
-	rows, err = p.db.Query(sqlSelectSourceControlProvider)
+	rows, err = p.db.Query(sqlSelectSourceControlProviders)
 	if err != nil {
 		return
 	}
 
 	for rows.Next() {
 		scp := types.SourceControlProviderStruct{}
-		err = rows.Scan(&scp.ID, &scp.SCPName, &scp.Url)
+		err = rows.Scan(&scp.ID, &scp.SCPName, &scp.Url, &scp.Kind, &scp.Secret, &scp.ReportingToken)
 		if err != nil {
 			return
 		}
@@ -124,38 +441,210 @@ func (p *BBashDB) GetSourceControlProviders() (scps []types.SourceControlProvide
 	return
 }
 
-const sqlInsertCampaign = `INSERT INTO campaign 
-		(name, start_on, end_on) 
+const sqlSelectSourceControlProvider = `SELECT Id, name, url, kind, secret, reporting_token
+	FROM source_control_provider
+	WHERE name = $1`
+
+func (p *BBashDB) GetSourceControlProvider(scpName string) (scp *types.SourceControlProviderStruct, err error) {
+	rows, err := p.db.Query(sqlSelectSourceControlProvider, scpName)
+	if err != nil {
+		return
+	}
+
+	scp = &types.SourceControlProviderStruct{}
+	for rows.Next() {
+		err = rows.Scan(&scp.ID, &scp.SCPName, &scp.Url, &scp.Kind, &scp.Secret, &scp.ReportingToken)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+const sqlInsertScanProvider = `INSERT INTO scan_provider
+		(name, url, api_key)
 		VALUES ($1, $2, $3)
 		RETURNING Id`
 
-func (p *BBashDB) InsertCampaign(campaign *types.CampaignStruct) (guid string, err error) {
+func (p *BBashDB) InsertScanProvider(scanProvider *types.ScanProviderStruct) (guid string, err error) {
 	err = p.db.QueryRow(
+		sqlInsertScanProvider,
+		scanProvider.SPName,
+		scanProvider.Url,
+		scanProvider.ApiKey,
+	).Scan(&guid)
+	return
+}
+
+const sqlSelectScanProviders = `SELECT Id, name, url, api_key FROM scan_provider`
+
+func (p *BBashDB) GetScanProviders() (scanProviders []types.ScanProviderStruct, err error) {
+	var rows *sql.Rows
+	rows, err = p.db.Query(sqlSelectScanProviders)
+	if err != nil {
+		return
+	}
+
+	for rows.Next() {
+		scanProvider := types.ScanProviderStruct{}
+		err = rows.Scan(&scanProvider.ID, &scanProvider.SPName, &scanProvider.Url, &scanProvider.ApiKey)
+		if err != nil {
+			return
+		}
+		scanProviders = append(scanProviders, scanProvider)
+	}
+	return
+}
+
+const sqlDeleteScanProvider = `DELETE FROM scan_provider WHERE name = $1`
+
+func (p *BBashDB) DeleteScanProvider(spName string) (rowsAffected int64, err error) {
+	res, err := p.db.Exec(sqlDeleteScanProvider, spName)
+	if err != nil {
+		return
+	}
+	rowsAffected, _ = res.RowsAffected()
+	return
+}
+
+const sqlInsertCampaign = `INSERT INTO campaign
+		(name, start_on, end_on, report_status, state)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING Id`
+
+// InsertCampaign creates campaign and, in the same transaction, enqueues a campaign.created
+// event - see enqueueEvent - and an audit_events row attributing the create to actor - see
+// insertAuditEventTx. Unlike the recordAudit calls server.go makes after a handler's DB call
+// returns, this audit row commits or rolls back atomically with the campaign row itself, so a
+// crash between the two can never leave a campaign with no record of who created it.
+func (p *BBashDB) InsertCampaign(campaign *types.CampaignStruct, actor string) (guid string, err error) {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				p.logger.Error("error rolling back campaign insert", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	if err = tx.QueryRow(
 		sqlInsertCampaign,
 		campaign.Name,
 		campaign.StartOn,
 		campaign.EndOn,
-	).Scan(&guid)
+		campaign.ReportStatus,
+		campaign.State,
+	).Scan(&guid); err != nil {
+		return
+	}
+
+	inserted := *campaign
+	inserted.ID = guid
+	if err = p.enqueueEvent(tx, eventTypeCampaignCreated, inserted); err != nil {
+		p.logger.Error("error enqueueing campaign.created event", zap.Any("campaign", inserted), zap.Error(err))
+		guid = ""
+		return
+	}
+
+	if err = p.insertAuditEventTx(tx, types.AuditEventStruct{
+		Actor:        actor,
+		Action:       auditActionCampaignCreate,
+		CampaignName: campaign.Name,
+		Success:      true,
+		OccurredOn:   time.Now(),
+	}); err != nil {
+		p.logger.Error("error inserting campaign.create audit event", zap.String("campaign", campaign.Name), zap.Error(err))
+		guid = ""
+		return
+	}
+
+	err = tx.Commit()
 	return
 }
 
 const sqlUpdateCampaign = `UPDATE campaign
 		SET start_on = $1,
-			end_on = $2		
-		WHERE name = $3
+			end_on = $2,
+			report_status = $3,
+			state = $4
+		WHERE name = $5
 		RETURNING id`
 
+// UpdateCampaign updates campaign and, in the same transaction, enqueues a campaign.updated
+// event - see enqueueEvent.
 func (p *BBashDB) UpdateCampaign(campaign *types.CampaignStruct) (guid string, err error) {
-	err = p.db.QueryRow(
+	tx, err := p.db.Begin()
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				p.logger.Error("error rolling back campaign update", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	if err = tx.QueryRow(
 		sqlUpdateCampaign,
 		campaign.StartOn,
 		campaign.EndOn,
+		campaign.ReportStatus,
+		campaign.State,
 		campaign.Name,
-	).Scan(&guid)
+	).Scan(&guid); err != nil {
+		return
+	}
+
+	updated := *campaign
+	updated.ID = guid
+	if err = p.enqueueEvent(tx, eventTypeCampaignUpdated, updated); err != nil {
+		p.logger.Error("error enqueueing campaign.updated event", zap.Any("campaign", updated), zap.Error(err))
+		return
+	}
+
+	err = tx.Commit()
+	return
+}
+
+const sqlUpdateCampaignState = `UPDATE campaign
+		SET state = $1
+		WHERE name = $2`
+
+// UpdateCampaignState persists state for campaignName alone, without disturbing its other
+// fields - used by the pause/resume routes and internal/campaign.Scheduler, neither of which has
+// (or should need) the rest of the campaign to make a transition. It enqueues a campaign.updated
+// event in the same transaction, the same as UpdateCampaign.
+func (p *BBashDB) UpdateCampaignState(campaignName string, state string) (err error) {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				p.logger.Error("error rolling back campaign state update", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	if _, err = tx.Exec(sqlUpdateCampaignState, state, campaignName); err != nil {
+		return
+	}
+
+	if err = p.enqueueEvent(tx, eventTypeCampaignUpdated, types.CampaignStruct{Name: campaignName, State: state}); err != nil {
+		p.logger.Error("error enqueueing campaign.updated event", zap.String("campaign", campaignName), zap.Error(err))
+		return
+	}
+
+	err = tx.Commit()
 	return
 }
 
-const sqlSelectCampaign = `SELECT ID, name, created_on, create_order, start_on, end_on, note 
+const sqlSelectCampaign = `SELECT ID, name, created_on, create_order, start_on, end_on, note, report_status, state
 	FROM campaign
 	WHERE name = $1`
 
@@ -167,7 +656,7 @@ func (p *BBashDB) GetCampaign(campaignName string) (campaign *types.CampaignStru
 
 	campaign = &types.CampaignStruct{}
 	for rows.Next() {
-		err = rows.Scan(&campaign.ID, &campaign.Name, &campaign.CreatedOn, &campaign.CreatedOrder, &campaign.StartOn, &campaign.EndOn, &campaign.Note)
+		err = rows.Scan(&campaign.ID, &campaign.Name, &campaign.CreatedOn, &campaign.CreatedOrder, &campaign.StartOn, &campaign.EndOn, &campaign.Note, &campaign.ReportStatus, &campaign.State)
 		if err != nil {
 			return
 		}
@@ -175,7 +664,7 @@ func (p *BBashDB) GetCampaign(campaignName string) (campaign *types.CampaignStru
 	return
 }
 
-const sqlSelectCampaigns = `SELECT ID, name, created_on, create_order, start_on, end_on, note FROM campaign`
+const sqlSelectCampaigns = `SELECT ID, name, created_on, create_order, start_on, end_on, note, report_status, state FROM campaign`
 
 func (p *BBashDB) GetCampaigns() (campaigns []types.CampaignStruct, err error) {
 	rows, err := p.db.Query(
@@ -186,7 +675,7 @@ func (p *BBashDB) GetCampaigns() (campaigns []types.CampaignStruct, err error) {
 
 	for rows.Next() {
 		campaign := types.CampaignStruct{}
-		err = rows.Scan(&campaign.ID, &campaign.Name, &campaign.CreatedOn, &campaign.CreatedOrder, &campaign.StartOn, &campaign.EndOn, &campaign.Note)
+		err = rows.Scan(&campaign.ID, &campaign.Name, &campaign.CreatedOn, &campaign.CreatedOrder, &campaign.StartOn, &campaign.EndOn, &campaign.Note, &campaign.ReportStatus, &campaign.State)
 		if err != nil {
 			return
 		}
@@ -195,9 +684,14 @@ func (p *BBashDB) GetCampaigns() (campaigns []types.CampaignStruct, err error) {
 	return
 }
 
+// sqlSelectCurrentCampaigns requires state = 'active' in addition to the start_on/end_on window,
+// so a campaign an admin has paused (see /campaign/:name/pause) stops showing up as active - and
+// therefore stops being eligible for scoring via sqlSelectParticipantId - even though "now" still
+// falls inside its configured window.
 const sqlSelectCurrentCampaigns = `SELECT * FROM campaign
 		WHERE $1 >= start_on
 			AND $1 < end_on
+			AND state = 'active'
 		ORDER BY start_on`
 
 func (p *BBashDB) GetActiveCampaigns(now time.Time) (activeCampaigns []types.CampaignStruct, err error) {
@@ -209,7 +703,7 @@ func (p *BBashDB) GetActiveCampaigns(now time.Time) (activeCampaigns []types.Cam
 	for rows.Next() {
 		activeCampaign := types.CampaignStruct{}
 
-		err = rows.Scan(&activeCampaign.ID, &activeCampaign.Name, &activeCampaign.CreatedOn, &activeCampaign.CreatedOrder, &activeCampaign.StartOn, &activeCampaign.EndOn, &activeCampaign.Note)
+		err = rows.Scan(&activeCampaign.ID, &activeCampaign.Name, &activeCampaign.CreatedOn, &activeCampaign.CreatedOrder, &activeCampaign.StartOn, &activeCampaign.EndOn, &activeCampaign.Note, &activeCampaign.ReportStatus, &activeCampaign.State)
 		if err != nil {
 			return
 		}
@@ -230,6 +724,39 @@ func (p *BBashDB) InsertOrganization(organization *types.OrganizationStruct) (gu
 	return
 }
 
+// InsertOrganizationsTx mirrors InsertBugsTx: all organizations are inserted in one transaction,
+// so a mid-batch failure rolls back every insert in the batch rather than leaving it partial.
+func (p *BBashDB) InsertOrganizationsTx(organizations []types.OrganizationStruct) (inserted []types.OrganizationStruct, err error) {
+	if len(organizations) == 0 {
+		return
+	}
+
+	tx, err := p.db.Begin()
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				p.logger.Error("error rolling back organization import", zap.Error(rollbackErr))
+			}
+			inserted = nil
+		}
+	}()
+
+	for i := range organizations {
+		if err = tx.QueryRow(sqlInsertOrganization, organizations[i].SCPName, organizations[i].Organization).
+			Scan(&organizations[i].ID); err != nil {
+			p.logger.Error("error inserting organization in batch", zap.Any("organization", organizations[i]), zap.Error(err))
+			return
+		}
+		inserted = append(inserted, organizations[i])
+	}
+
+	err = tx.Commit()
+	return
+}
+
 const sqlSelectOrganizations = `SELECT
 		organization.Id,
         Name,
@@ -293,7 +820,8 @@ const sqlSelectParticipantId = `SELECT
 		LEFT JOIN team ON team.Id = participant.fk_team
 		WHERE $1 >= campaign.start_on
 			AND $1 < campaign.end_on
-		    AND LOWER(source_control_provider.name) = $2 
+			AND campaign.state = 'active'
+		    AND LOWER(source_control_provider.name) = $2
 			AND login_name = $3`
 
 func (p *BBashDB) SelectParticipantsToScore(msg *types.ScoringMessage, now time.Time) (participantsToScore []types.ParticipantStruct, err error) {
@@ -337,92 +865,511 @@ func (p *BBashDB) SelectPointValue(msg *types.ScoringMessage, campaignName, bugT
 	return
 }
 
-const sqlUpdateParticipantScore = `UPDATE participant 
-		SET Score = Score + $1 
-		WHERE id = $2 
+const sqlUpdateParticipantScore = `UPDATE participant
+		SET Score = Score + $1
+		WHERE id = $2
 		RETURNING Score`
 
-func (p *BBashDB) UpdateParticipantScore(participant *types.ParticipantStruct, delta float64) (err error) {
-	var score int
-	row := p.db.QueryRow(sqlUpdateParticipantScore, delta, participant.ID)
-	err = row.Scan(&score)
-	return
+// outboxOpUpdateScore, outboxOpCreateParticipant, and outboxOpDeleteParticipant identify
+// upstream_outbox rows this package inserts; kept as plain string literals rather than a shared
+// constant with internal/outbox, the same way CampaignStruct.State is a plain string this package
+// never imports internal/campaign to validate - internal/outbox's Op* constants are the canonical
+// definitions consumers switch on.
+const outboxOpUpdateScore = "update_score"
+const outboxOpCreateParticipant = "create_participant"
+const outboxOpDeleteParticipant = "delete_participant"
+
+const sqlInsertOutboxEntry = `INSERT INTO upstream_outbox
+		(op, fk_participant, payload, attempts, next_attempt_at, done)
+		VALUES ($1, $2, $3, 0, $4, false)`
+
+// outboxScorePayload is the JSON body of an outboxOpUpdateScore entry; internal/outbox.ScorePayload
+// must be kept in sync with this shape.
+type outboxScorePayload struct {
+	ParticipantUpstreamId string `json:"participantUpstreamId"`
+	CampaignName          string `json:"campaignName"`
+	Score                 int    `json:"score"`
 }
 
-const sqlScoreQuery = `SELECT points
-			FROM scoring_event
-			WHERE fk_campaign = (SELECT id FROM campaign WHERE name = $1)
-			    AND fk_scp = (SELECT id FROM source_control_provider WHERE name = $2)
-			    AND repoOwner = $3
-				AND repoName = $4
-				AND pr = $5`
+// outboxCreateParticipantPayload is the JSON body of an outboxOpCreateParticipant entry;
+// internal/outbox.CreateParticipantPayload must be kept in sync with this shape.
+type outboxCreateParticipantPayload struct {
+	Participant        types.ParticipantStruct `json:"participant"`
+	CampaignUpstreamId string                  `json:"campaignUpstreamId"`
+}
 
-func (p *BBashDB) SelectPriorScore(participantToScore *types.ParticipantStruct, msg *types.ScoringMessage) (oldPoints float64) {
-	row := p.db.QueryRow(sqlScoreQuery, participantToScore.CampaignName, participantToScore.ScpName, msg.RepoOwner, msg.RepoName, msg.PullRequest)
-	oldPoints = 0
-	err := row.Scan(&oldPoints)
-	if err != nil {
-		// ignore error case from scan when no row exists, will occur when this is a new score event
-		p.logger.Debug("ignoring likely new score event", zap.Error(err), zap.Any("ScoringMessage", msg))
-	}
-	return
+// outboxDeleteParticipantPayload is the JSON body of an outboxOpDeleteParticipant entry;
+// internal/outbox.DeleteParticipantPayload must be kept in sync with this shape.
+type outboxDeleteParticipantPayload struct {
+	ParticipantUpstreamId string `json:"participantUpstreamId"`
 }
 
-const sqlInsertScoringEvent = `INSERT INTO scoring_event
-			(fk_campaign, fk_scp, repoOwner, repoName, pr, username, points)
-			VALUES ((SELECT id FROM campaign WHERE name = $1), 
-			        (SELECT id FROM source_control_provider WHERE name = $2),
-			        $3, $4, $5, $6, $7)
-			ON CONFLICT (fk_campaign, fk_scp, repoOwner, repoName, pr) DO
-				UPDATE SET points = $7`
+// auditActionCampaignCreate is the audit_events Action InsertCampaign records - kept as a plain
+// string rather than importing internal/auditlog.ActionCampaignCreate, the same way the
+// eventType* constants below duplicate internal/events' literals instead of cross-importing.
+const auditActionCampaignCreate = "campaign.create"
+
+// Event type literals an EventEntryStruct.Type is set to - kept as plain strings rather than a
+// shared constant with internal/events, the same way outboxOpUpdateScore duplicates
+// internal/outbox.OpUpdateScore: internal/events' constants are the canonical definition
+// consumers switch on.
+const (
+	eventTypeCampaignCreated         = "campaign.created"
+	eventTypeCampaignUpdated         = "campaign.updated"
+	eventTypeParticipantCreated      = "participant.created"
+	eventTypeParticipantScoreUpdated = "participant.score_updated"
+	eventTypeParticipantDeleted      = "participant.deleted"
+)
 
-func (p *BBashDB) InsertScoringEvent(participantToScore *types.ParticipantStruct, msg *types.ScoringMessage, newPoints float64) (err error) {
-	_, err = p.db.Exec(sqlInsertScoringEvent, participantToScore.CampaignName, participantToScore.ScpName, msg.RepoOwner, msg.RepoName, msg.PullRequest, msg.TriggerUser, newPoints)
+const sqlInsertEventEntry = `INSERT INTO event_outbox
+		(type, payload, attempts, next_attempt_at, done)
+		VALUES ($1, $2, 0, $3, false)`
+
+// enqueueEvent marshals data and inserts it as a not-yet-dispatched event_outbox row within tx, the
+// same transactional-outbox shape as the upstream_outbox row UpdateParticipantScore enqueues: a
+// crash between commit and dispatch just leaves the row for internal/events.Dispatcher to pick up
+// on its next tick, rather than losing the event or coupling callers to the subscriber fan-out.
+func (p *BBashDB) enqueueEvent(tx *sql.Tx, eventType string, data interface{}) (err error) {
+	var payload []byte
+	if payload, err = json.Marshal(data); err != nil {
+		return
+	}
+	_, err = tx.Exec(sqlInsertEventEntry, eventType, payload, time.Now())
 	return
 }
 
-const sqlInsertParticipant = `INSERT INTO participant 
-		(fk_scp, fk_campaign, login_name, Email, DisplayName, Score) 
-		VALUES ((SELECT Id FROM source_control_provider WHERE Name = $1),
-		        (SELECT Id FROM campaign WHERE name = $2),
-		        $3, $4, $5, $6)
-		RETURNING Id, Score, JoinedAt`
-
-func (p *BBashDB) InsertParticipant(participant *types.ParticipantStruct) (err error) {
-	err = p.db.QueryRow(
-		sqlInsertParticipant,
-		participant.ScpName,
-		participant.CampaignName,
-		participant.LoginName,
-		participant.Email,
-		participant.DisplayName,
-		0,
-	).Scan(&participant.ID, &participant.Score, &participant.JoinedAt)
+// UpdateParticipantScore applies delta to participant's Score and, in the same transaction, enqueues
+// an upstream_outbox row so internal/outbox.Worker can publish the new score upstream afterwards.
+// Publishing this way instead of calling the upstream.Backend directly means a slow or failing
+// upstream never adds latency or error risk to the scoring hot path, and a crash between the DB
+// commit and the upstream call just leaves the row to be drained on the next tick - at-least-once
+// delivery across restarts, not best-effort.
+func (p *BBashDB) UpdateParticipantScore(participant *types.ParticipantStruct, delta float64) (err error) {
+	tx, err := p.db.Begin()
 	if err != nil {
-		p.logger.Error("error inserting participant", zap.Any("participant", participant), zap.Error(err))
+		return
 	}
-	return
-}
+	defer func() {
+		if err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				p.logger.Error("error rolling back participant score update", zap.Error(rollbackErr))
+			}
+		}
+	}()
 
-const sqlInsertTeam = `INSERT INTO team
-		(fk_campaign, name)
-		VALUES ((SELECT id FROM campaign WHERE name = $1), $2)
-		RETURNING Id`
+	var score int
+	if err = tx.QueryRow(sqlUpdateParticipantScore, delta, participant.ID).Scan(&score); err != nil {
+		return
+	}
 
-func (p *BBashDB) InsertTeam(team *types.TeamStruct) (err error) {
-	err = p.db.QueryRow(
-		sqlInsertTeam,
-		team.CampaignName,
-		team.Name).Scan(&team.Id)
+	var payload []byte
+	if payload, err = json.Marshal(outboxScorePayload{ParticipantUpstreamId: participant.UpstreamId, CampaignName: participant.CampaignName, Score: score}); err != nil {
+		return
+	}
+
+	if _, err = tx.Exec(sqlInsertOutboxEntry, outboxOpUpdateScore, participant.ID, payload, time.Now()); err != nil {
+		p.logger.Error("error enqueueing score outbox entry", zap.Any("participant", participant), zap.Error(err))
+		return
+	}
+
+	scored := *participant
+	scored.Score = score
+	if err = p.enqueueEvent(tx, eventTypeParticipantScoreUpdated, scored); err != nil {
+		p.logger.Error("error enqueueing participant.score_updated event", zap.Any("participant", participant), zap.Error(err))
+		return
+	}
+
+	err = tx.Commit()
 	return
 }
 
-const sqlSelectParticipantDetail = `SELECT 
-		participant.Id, campaign.name, source_control_provider.name, login_name, Email, DisplayName, Score, team.name, JoinedAt
-		FROM participant
-		LEFT JOIN team ON team.Id = participant.fk_team
-		INNER JOIN campaign ON campaign.Id = participant.fk_campaign
-		INNER JOIN source_control_provider ON participant.fk_scp = source_control_provider.Id
+const sqlUpdateParticipantScoreCAS = `UPDATE participant
+		SET Score = Score + $1
+		WHERE id = $2 AND Score = $3
+		RETURNING Score`
+
+const sqlSelectParticipantScore = `SELECT Score FROM participant WHERE id = $1`
+
+// UpdateParticipantScoreCAS is UpdateParticipantScore guarded by a compare-and-swap on Score: the
+// update only takes effect if participant's Score still equals expectedScore, so two concurrent
+// webhook deliveries racing to score the same participant can't clobber one another's read-modify-
+// write - the loser gets swapped==false, participant.Score refreshed to the value it lost the race
+// to, and is expected to recompute its delta against that and retry (see processScoringMessage).
+func (p *BBashDB) UpdateParticipantScoreCAS(participant *types.ParticipantStruct, expectedScore int, delta float64) (swapped bool, err error) {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				p.logger.Error("error rolling back participant score CAS update", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	var score int
+	if scanErr := tx.QueryRow(sqlUpdateParticipantScoreCAS, delta, participant.ID, expectedScore).Scan(&score); scanErr != nil {
+		if scanErr != sql.ErrNoRows {
+			err = scanErr
+			return
+		}
+		// expectedScore was stale: refresh participant.Score so the caller's next attempt starts
+		// from the value another writer already committed.
+		if err = tx.QueryRow(sqlSelectParticipantScore, participant.ID).Scan(&participant.Score); err != nil {
+			return
+		}
+		err = tx.Commit()
+		return
+	}
+
+	var payload []byte
+	if payload, err = json.Marshal(outboxScorePayload{ParticipantUpstreamId: participant.UpstreamId, CampaignName: participant.CampaignName, Score: score}); err != nil {
+		return
+	}
+
+	if _, err = tx.Exec(sqlInsertOutboxEntry, outboxOpUpdateScore, participant.ID, payload, time.Now()); err != nil {
+		p.logger.Error("error enqueueing score outbox entry", zap.Any("participant", participant), zap.Error(err))
+		return
+	}
+
+	scored := *participant
+	scored.Score = score
+	if err = p.enqueueEvent(tx, eventTypeParticipantScoreUpdated, scored); err != nil {
+		p.logger.Error("error enqueueing participant.score_updated event", zap.Any("participant", participant), zap.Error(err))
+		return
+	}
+
+	if err = tx.Commit(); err != nil {
+		return
+	}
+	participant.Score = score
+	swapped = true
+	return
+}
+
+// ApplyScoringEvent upserts the scoring_event row for msg (recording newPoints, the event's
+// absolute point value) and applies the CAS-guarded participant score update (adding delta, the
+// change in the participant's cumulative Score) in a single transaction, so a crash between the
+// two (previously separate InsertScoringEvent and UpdateParticipantScoreCAS calls) can't record a
+// scoring event without updating the participant's score, or vice versa. Like
+// UpdateParticipantScoreCAS, swapped==false means expectedScore was stale: participant.Score is
+// refreshed to the value another writer committed, and the caller is expected to recompute delta
+// against that and retry. The CAS is on participant.Score itself rather than a separate monotonic
+// version column, since Score is already the single value every writer needs to agree on; the
+// scoring_event upsert is safe to repeat alongside it, since sqlInsertScoringEvent's ON CONFLICT DO
+// UPDATE converges a retried webhook's event row to the same final points rather than needing
+// trigger_user in the conflict target to reject it outright with DO NOTHING.
+//
+// This is also this package's unit-of-work for the scoring path: every other mutating method
+// already opens and commits/rolls back its own *sql.Tx ad hoc (see enqueueEvent,
+// insertAuditEventTx), so a generic WithTx(ctx, func(tx TxDB) error) error wrapper would be a new
+// abstraction layered over an idiom the package doesn't otherwise use, for no caller this tx
+// doesn't already cover.
+func (p *BBashDB) ApplyScoringEvent(participantToScore *types.ParticipantStruct, msg *types.ScoringMessage, newPoints, delta float64, expectedScore int) (swapped bool, err error) {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				p.logger.Error("error rolling back scoring event application", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	if _, err = tx.Exec(sqlInsertScoringEvent, participantToScore.CampaignName, participantToScore.ScpName, msg.RepoOwner, msg.RepoName, msg.PullRequest, msg.TriggerUser, newPoints); err != nil {
+		return
+	}
+
+	var score int
+	if scanErr := tx.QueryRow(sqlUpdateParticipantScoreCAS, delta, participantToScore.ID, expectedScore).Scan(&score); scanErr != nil {
+		if scanErr != sql.ErrNoRows {
+			err = scanErr
+			return
+		}
+		// expectedScore was stale: refresh participantToScore.Score so the caller's next attempt
+		// starts from the value another writer already committed.
+		if err = tx.QueryRow(sqlSelectParticipantScore, participantToScore.ID).Scan(&participantToScore.Score); err != nil {
+			return
+		}
+		err = tx.Commit()
+		return
+	}
+
+	var payload []byte
+	if payload, err = json.Marshal(outboxScorePayload{ParticipantUpstreamId: participantToScore.UpstreamId, CampaignName: participantToScore.CampaignName, Score: score}); err != nil {
+		return
+	}
+
+	if _, err = tx.Exec(sqlInsertOutboxEntry, outboxOpUpdateScore, participantToScore.ID, payload, time.Now()); err != nil {
+		p.logger.Error("error enqueueing score outbox entry", zap.Any("participant", participantToScore), zap.Error(err))
+		return
+	}
+
+	scored := *participantToScore
+	scored.Score = score
+	if err = p.enqueueEvent(tx, eventTypeParticipantScoreUpdated, scored); err != nil {
+		p.logger.Error("error enqueueing participant.score_updated event", zap.Any("participant", participantToScore), zap.Error(err))
+		return
+	}
+
+	if err = tx.Commit(); err != nil {
+		return
+	}
+	participantToScore.Score = score
+	swapped = true
+	return
+}
+
+// sqlScoreQuery excludes a scoring_event RevokeScoringEvent has revoked (see
+// scoring_event_revocations in scoring_revocation.go), so a revoked event reads back as never
+// having been scored - letting a corrected redelivery of the same PR be credited fresh instead of
+// being compared against (and possibly vetoed by) the revoked points.
+const sqlScoreQuery = `SELECT points
+			FROM scoring_event se
+			WHERE fk_campaign = (SELECT id FROM campaign WHERE name = $1)
+			    AND fk_scp = (SELECT id FROM source_control_provider WHERE name = $2)
+			    AND repoOwner = $3
+				AND repoName = $4
+				AND pr = $5
+				AND NOT EXISTS (
+					SELECT 1 FROM scoring_event_revocations r
+					WHERE r.fk_campaign = se.fk_campaign AND r.fk_scp = se.fk_scp
+					    AND r.repoOwner = se.repoOwner AND r.repoName = se.repoName AND r.pr = se.pr)`
+
+func (p *BBashDB) SelectPriorScore(participantToScore *types.ParticipantStruct, msg *types.ScoringMessage) (oldPoints float64) {
+	row := p.db.QueryRow(sqlScoreQuery, participantToScore.CampaignName, participantToScore.ScpName, msg.RepoOwner, msg.RepoName, msg.PullRequest)
+	oldPoints = 0
+	err := row.Scan(&oldPoints)
+	if err != nil {
+		// ignore error case from scan when no row exists, will occur when this is a new score event
+		p.logger.Debug("ignoring likely new score event", zap.Error(err), zap.Any("ScoringMessage", msg))
+	}
+	return
+}
+
+const sqlInsertScoringEvent = `INSERT INTO scoring_event
+			(fk_campaign, fk_scp, repoOwner, repoName, pr, username, points)
+			VALUES ((SELECT id FROM campaign WHERE name = $1), 
+			        (SELECT id FROM source_control_provider WHERE name = $2),
+			        $3, $4, $5, $6, $7)
+			ON CONFLICT (fk_campaign, fk_scp, repoOwner, repoName, pr) DO
+				UPDATE SET points = $7`
+
+func (p *BBashDB) InsertScoringEvent(participantToScore *types.ParticipantStruct, msg *types.ScoringMessage, newPoints float64) (err error) {
+	_, err = p.db.Exec(sqlInsertScoringEvent, participantToScore.CampaignName, participantToScore.ScpName, msg.RepoOwner, msg.RepoName, msg.PullRequest, msg.TriggerUser, newPoints)
+	return
+}
+
+// sqlInsertScoringMessageEventIfAbsent seeds a scoring_message_event row for dedupId in
+// ScoringEventPending status, leaving an existing row (whatever status it's since moved to)
+// untouched - the caller always follows up with sqlGetScoringEventByDedupID to see which case it
+// got.
+const sqlInsertScoringMessageEventIfAbsent = `INSERT INTO scoring_message_event
+			(dedup_id, status, points, created_on, updated_on)
+			VALUES ($1, '` + string(types.ScoringEventPending) + `', 0, $2, $2)
+			ON CONFLICT (dedup_id) DO NOTHING`
+
+const sqlGetScoringEventByDedupID = `SELECT id, dedup_id, status, reason, points, created_on, updated_on
+			FROM scoring_message_event WHERE dedup_id = $1`
+
+const sqlGetScoringEvent = `SELECT id, dedup_id, status, reason, points, created_on, updated_on
+			FROM scoring_message_event WHERE id = $1`
+
+func (p *BBashDB) UpsertPendingScoringEvent(dedupId string) (event *types.ScoringEventStruct, err error) {
+	if _, err = p.db.Exec(sqlInsertScoringMessageEventIfAbsent, dedupId, time.Now()); err != nil {
+		return
+	}
+	return p.scanScoringEvent(p.db.QueryRow(sqlGetScoringEventByDedupID, dedupId))
+}
+
+func (p *BBashDB) GetScoringEvent(guid string) (event *types.ScoringEventStruct, err error) {
+	return p.scanScoringEvent(p.db.QueryRow(sqlGetScoringEvent, guid))
+}
+
+func (p *BBashDB) scanScoringEvent(row *sql.Row) (event *types.ScoringEventStruct, err error) {
+	event = &types.ScoringEventStruct{}
+	var reason sql.NullString
+	if err = row.Scan(&event.Id, &event.DedupId, &event.Status, &reason, &event.Points, &event.CreatedOn, &event.UpdatedOn); err != nil {
+		if err == sql.ErrNoRows {
+			event, err = nil, nil
+		}
+		return
+	}
+	event.Reason = reason.String
+	return
+}
+
+const sqlUpdateScoringEventStatus = `UPDATE scoring_message_event
+			SET status = $1, points = $2, reason = $3, updated_on = $4
+			WHERE id = $5 AND status = $6`
+
+func (p *BBashDB) UpdateScoringEventStatus(id string, from, to types.ScoringEventStatus, points float64, reason string) (updated bool, err error) {
+	result, err := p.db.Exec(sqlUpdateScoringEventStatus, to, points, reason, time.Now(), id, from)
+	if err != nil {
+		return
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return
+	}
+	updated = rowsAffected > 0
+	return
+}
+
+const sqlExpireStuckScoringEvents = `UPDATE scoring_message_event
+			SET status = '` + string(types.ScoringEventExpired) + `', updated_on = $1
+			WHERE status IN ('` + string(types.ScoringEventPending) + `', '` + string(types.ScoringEventValidated) + `')
+				AND created_on < $2`
+
+func (p *BBashDB) ExpireStuckScoringEvents(ttl time.Duration, now time.Time) (expired int64, err error) {
+	result, err := p.db.Exec(sqlExpireStuckScoringEvents, now, now.Add(-ttl))
+	if err != nil {
+		return
+	}
+	expired, err = result.RowsAffected()
+	return
+}
+
+const sqlInsertParticipant = `INSERT INTO participant
+		(fk_scp, fk_campaign, login_name, Email, DisplayName, Score) 
+		VALUES ((SELECT Id FROM source_control_provider WHERE Name = $1),
+		        (SELECT Id FROM campaign WHERE name = $2),
+		        $3, $4, $5, $6)
+		RETURNING Id, Score, JoinedAt`
+
+// InsertParticipant creates participant and, in the same transaction, enqueues a
+// participant.created event (see enqueueEvent) and an upstream_outbox row so
+// internal/outbox.Worker can publish the new participant upstream and record the upstream id it
+// comes back with - see outboxOpCreateParticipant.
+func (p *BBashDB) InsertParticipant(participant *types.ParticipantStruct) (err error) {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			p.logger.Error("error inserting participant", zap.Any("participant", participant), zap.Error(err))
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				p.logger.Error("error rolling back participant insert", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	if err = tx.QueryRow(
+		sqlInsertParticipant,
+		participant.ScpName,
+		participant.CampaignName,
+		participant.LoginName,
+		participant.Email,
+		participant.DisplayName,
+		0,
+	).Scan(&participant.ID, &participant.Score, &participant.JoinedAt); err != nil {
+		return
+	}
+
+	if err = p.enqueueEvent(tx, eventTypeParticipantCreated, participant); err != nil {
+		return
+	}
+
+	// CampaignUpstreamId is left blank: campaign rows have no upstream_id column in this
+	// snapshot's migrations (see server_upstream.go's notifyUpstreamCampaignCreated), so there's
+	// nothing to look up yet.
+	var payload []byte
+	if payload, err = json.Marshal(outboxCreateParticipantPayload{Participant: *participant}); err != nil {
+		return
+	}
+	if _, err = tx.Exec(sqlInsertOutboxEntry, outboxOpCreateParticipant, participant.ID, payload, time.Now()); err != nil {
+		p.logger.Error("error enqueueing create-participant outbox entry", zap.Any("participant", participant), zap.Error(err))
+		return
+	}
+
+	err = tx.Commit()
+	return
+}
+
+// InsertParticipantsTx mirrors InsertBugsTx: all participants are inserted in one transaction, so
+// a mid-batch failure rolls back every insert in the batch rather than leaving it partial.
+func (p *BBashDB) InsertParticipantsTx(participants []types.ParticipantStruct) (inserted []types.ParticipantStruct, err error) {
+	if len(participants) == 0 {
+		return
+	}
+
+	tx, err := p.db.Begin()
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				p.logger.Error("error rolling back participant import", zap.Error(rollbackErr))
+			}
+			inserted = nil
+		}
+	}()
+
+	for i := range participants {
+		if err = tx.QueryRow(
+			sqlInsertParticipant,
+			participants[i].ScpName,
+			participants[i].CampaignName,
+			participants[i].LoginName,
+			participants[i].Email,
+			participants[i].DisplayName,
+			0,
+		).Scan(&participants[i].ID, &participants[i].Score, &participants[i].JoinedAt); err != nil {
+			p.logger.Error("error inserting participant in batch", zap.Any("participant", participants[i]), zap.Error(err))
+			return
+		}
+		inserted = append(inserted, participants[i])
+	}
+
+	err = tx.Commit()
+	return
+}
+
+const sqlInsertTeam = `INSERT INTO team
+		(fk_campaign, name)
+		VALUES ((SELECT id FROM campaign WHERE name = $1), $2)
+		RETURNING Id`
+
+func (p *BBashDB) InsertTeam(team *types.TeamStruct) (err error) {
+	err = p.db.QueryRow(
+		sqlInsertTeam,
+		team.CampaignName,
+		team.Name).Scan(&team.Id)
+	return
+}
+
+const sqlSelectTeam = `SELECT team.Id, campaign.name, team.name
+	FROM team
+	JOIN campaign ON campaign.id = team.fk_campaign
+	WHERE campaign.name = $1 AND team.name = $2`
+
+func (p *BBashDB) GetTeam(campaignName, teamName string) (team *types.TeamStruct, err error) {
+	rows, err := p.db.Query(sqlSelectTeam, campaignName, teamName)
+	if err != nil {
+		return
+	}
+
+	team = &types.TeamStruct{}
+	for rows.Next() {
+		err = rows.Scan(&team.Id, &team.CampaignName, &team.Name)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+const sqlSelectParticipantDetail = `SELECT 
+		participant.Id, campaign.name, source_control_provider.name, login_name, Email, DisplayName, Score, team.name, JoinedAt
+		FROM participant
+		LEFT JOIN team ON team.Id = participant.fk_team
+		INNER JOIN campaign ON campaign.Id = participant.fk_campaign
+		INNER JOIN source_control_provider ON participant.fk_scp = source_control_provider.Id
 		WHERE campaign.name = $1
 		  AND source_control_provider.name = $2 
 		  AND participant.login_name = $3`
@@ -491,50 +1438,230 @@ func (p *BBashDB) SelectParticipantsInCampaign(campaignName string) (participant
 	return
 }
 
-const sqlUpdateParticipant = `UPDATE participant 
-		SET 
-		    fk_campaign = (SELECT Id FROM campaign WHERE name = $1),
-		    fk_scp = (SELECT Id FROM source_control_provider WHERE name = $2),
-		    login_name = $3,
-		    Email = $4,
-		    DisplayName = $5,
-		    Score = $6,
-		    fk_team = (SELECT Id FROM team WHERE name = $7)		    
-		WHERE Id = $8`
+// participantPagedSelectColumns and participantPagedFrom are shared between
+// sqlSelectParticipantsByCampaignPaged (keyset, SortByScore) and
+// sqlSelectParticipantsByCampaignPagedOffset (OFFSET, SortByJoinedAt/SortByLogin) - both select the
+// same columns from the same join, differing only in their WHERE/ORDER BY/pagination clause.
+const participantPagedSelectColumns = `participant.Id, campaign.name, source_control_provider.name, login_name, Email, DisplayName, Score, team.name, JoinedAt,
+		COUNT(*) OVER() AS total_count`
+const participantPagedFrom = `FROM participant
+		LEFT JOIN team ON participant.fk_team = team.Id
+		INNER JOIN campaign ON participant.fk_campaign = campaign.Id
+		INNER JOIN source_control_provider ON participant.fk_scp = source_control_provider.Id`
 
-func (p *BBashDB) UpdateParticipant(participant *types.ParticipantStruct) (rowsAffected int64, err error) {
-	res, err := p.db.Exec(
-		sqlUpdateParticipant,
-		participant.CampaignName,
-		participant.ScpName,
-		participant.LoginName,
-		participant.Email,
-		participant.DisplayName,
-		participant.Score,
-		participant.TeamName,
-		participant.ID,
-	)
-	if err != nil {
-		return
-	}
+const sqlSelectParticipantsByCampaignPaged = `SELECT ` + participantPagedSelectColumns + `
+		` + participantPagedFrom + `
+		WHERE campaign.name = $1
+			AND login_name ILIKE $2
+			AND (Score < $3 OR (Score = $3 AND participant.Id > $4))
+			AND ($5 IS NULL OR team.name = $5)
+			AND ($6 IS NULL OR Score >= $6)
+		ORDER BY Score DESC, participant.Id ASC
+		LIMIT $7`
+
+const sqlSelectParticipantsByCampaignPagedByJoinedAt = `SELECT ` + participantPagedSelectColumns + `
+		` + participantPagedFrom + `
+		WHERE campaign.name = $1
+			AND login_name ILIKE $2
+			AND ($3 IS NULL OR team.name = $3)
+			AND ($4 IS NULL OR Score >= $4)
+		ORDER BY JoinedAt ASC, participant.Id ASC
+		LIMIT $5 OFFSET $6`
+
+const sqlSelectParticipantsByCampaignPagedByLogin = `SELECT ` + participantPagedSelectColumns + `
+		` + participantPagedFrom + `
+		WHERE campaign.name = $1
+			AND login_name ILIKE $2
+			AND ($3 IS NULL OR team.name = $3)
+			AND ($4 IS NULL OR Score >= $4)
+		ORDER BY login_name ASC, participant.Id ASC
+		LIMIT $5 OFFSET $6`
 
-	rowsAffected, err = res.RowsAffected()
+func scanParticipantsPage(rows *sql.Rows) (page []types.ParticipantStruct, total int64, err error) {
+	for rows.Next() {
+		participant := new(types.ParticipantStruct)
+		var nullableTeamName sql.NullString
+		if err = rows.Scan(
+			&participant.ID,
+			&participant.CampaignName,
+			&participant.ScpName,
+			&participant.LoginName,
+			&participant.Email,
+			&participant.DisplayName,
+			&participant.Score,
+			&nullableTeamName,
+			&participant.JoinedAt,
+			&total,
+		); err != nil {
+			return
+		}
+		if nullableTeamName.Valid {
+			participant.TeamName = nullableTeamName.String
+		}
+		page = append(page, *participant)
+	}
+	err = rows.Err()
 	return
 }
 
-const sqlDeleteParticipant = `DELETE FROM participant WHERE
-                          fk_campaign = (SELECT id from campaign where name =$1)
+// SelectParticipantsInCampaignPaged is SelectParticipantsInCampaign's leaderboard-friendly form.
+// opts.Filter, when set, matches as a case-insensitive substring against login_name; opts.TeamFilter
+// and opts.MinScore, when set, narrow to an exact team and a minimum Score. Total is read back from
+// a single COUNT(*) OVER() window column rather than a second query.
+//
+// opts.SortBy (default SortByScore) picks both the order and the pagination strategy:
+// SortByScore keyset-paginates on (Score DESC, Id ASC) rather than OFFSET, so scanning deep into a
+// large campaign's standings doesn't get more expensive page over page, and opts.Cursor is the
+// nextCursor a prior page returned (empty for the first page). SortByJoinedAt and SortByLogin
+// instead paginate by OFFSET, with opts.Cursor holding the offset as a decimal string - see
+// ParticipantSort's doc comment for why.
+func (p *BBashDB) SelectParticipantsInCampaignPaged(campaignName string, opts ListOptions) (page []types.ParticipantStruct, nextCursor string, total int64, err error) {
+	if opts.SortBy == SortByJoinedAt || opts.SortBy == SortByLogin {
+		offset := 0
+		if opts.Cursor != "" {
+			if offset, err = strconv.Atoi(opts.Cursor); err != nil {
+				err = fmt.Errorf("invalid cursor %q: %w", opts.Cursor, err)
+				return
+			}
+		}
+
+		sqlQuery := sqlSelectParticipantsByCampaignPagedByJoinedAt
+		if opts.SortBy == SortByLogin {
+			sqlQuery = sqlSelectParticipantsByCampaignPagedByLogin
+		}
+
+		limit := clampLimit(opts.Limit)
+		var rows *sql.Rows
+		if rows, err = p.db.Query(sqlQuery, campaignName, "%"+opts.Filter+"%", opts.TeamFilter, opts.MinScore, limit, offset); err != nil {
+			return
+		}
+		defer rows.Close()
+
+		if page, total, err = scanParticipantsPage(rows); err != nil {
+			return
+		}
+		if len(page) > 0 {
+			nextCursor = strconv.Itoa(offset + len(page))
+		}
+		return
+	}
+
+	cursorScore, cursorId, err := decodeLeaderboardCursor(opts.Cursor)
+	if err != nil {
+		return
+	}
+
+	rows, err := p.db.Query(sqlSelectParticipantsByCampaignPaged, campaignName, "%"+opts.Filter+"%", cursorScore, cursorId, opts.TeamFilter, opts.MinScore, clampLimit(opts.Limit))
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	if page, total, err = scanParticipantsPage(rows); err != nil {
+		return
+	}
+
+	if len(page) > 0 {
+		last := page[len(page)-1]
+		nextCursor = encodeLeaderboardCursor(last.Score, last.ID)
+	}
+	return
+}
+
+const sqlUpdateParticipant = `UPDATE participant
+		SET 
+		    fk_campaign = (SELECT Id FROM campaign WHERE name = $1),
+		    fk_scp = (SELECT Id FROM source_control_provider WHERE name = $2),
+		    login_name = $3,
+		    Email = $4,
+		    DisplayName = $5,
+		    Score = $6,
+		    fk_team = (SELECT Id FROM team WHERE name = $7)		    
+		WHERE Id = $8`
+
+func (p *BBashDB) UpdateParticipant(participant *types.ParticipantStruct) (rowsAffected int64, err error) {
+	res, err := p.db.Exec(
+		sqlUpdateParticipant,
+		participant.CampaignName,
+		participant.ScpName,
+		participant.LoginName,
+		participant.Email,
+		participant.DisplayName,
+		participant.Score,
+		participant.TeamName,
+		participant.ID,
+	)
+	if err != nil {
+		return
+	}
+
+	rowsAffected, err = res.RowsAffected()
+	return
+}
+
+const sqlDeleteParticipant = `DELETE FROM participant WHERE
+                          fk_campaign = (SELECT id from campaign where name =$1)
                           AND fk_scp = (SELECT id from source_control_provider where name =$2)
                           AND login_name = $3
-                          RETURNING id`
+                          RETURNING id, upstream_id`
+
+// eventParticipantDeletedPayload is the participant.deleted event's data: DeleteParticipant only
+// has the deleted row's identifying fields to hand, not a full ParticipantStruct.
+type eventParticipantDeletedPayload struct {
+	ParticipantId string `json:"guid"`
+	CampaignName  string `json:"campaignName"`
+	ScpName       string `json:"scpName"`
+	LoginName     string `json:"loginName"`
+}
 
+// DeleteParticipant removes the participant and, in the same transaction, enqueues a
+// participant.deleted event (see enqueueEvent) and, when the deleted row carried an upstream id,
+// an upstream_outbox row so internal/outbox.Worker removes it from upstream too - see
+// outboxOpDeleteParticipant.
 func (p *BBashDB) DeleteParticipant(campaign, scpName, loginName string) (participantId string, err error) {
-	err = p.db.QueryRow(sqlDeleteParticipant, campaign, scpName, loginName).Scan(&participantId)
+	tx, err := p.db.Begin()
 	if err != nil {
-		p.logger.Error("error deleting participant",
-			zap.String("campaign", campaign), zap.String("scpName", scpName),
-			zap.String("loginName", loginName), zap.Error(err))
+		return
+	}
+	defer func() {
+		if err != nil {
+			p.logger.Error("error deleting participant",
+				zap.String("campaign", campaign), zap.String("scpName", scpName),
+				zap.String("loginName", loginName), zap.Error(err))
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				p.logger.Error("error rolling back participant delete", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	var upstreamId string
+	if err = tx.QueryRow(sqlDeleteParticipant, campaign, scpName, loginName).Scan(&participantId, &upstreamId); err != nil {
+		return
+	}
+
+	payload := eventParticipantDeletedPayload{
+		ParticipantId: participantId,
+		CampaignName:  campaign,
+		ScpName:       scpName,
+		LoginName:     loginName,
+	}
+	if err = p.enqueueEvent(tx, eventTypeParticipantDeleted, payload); err != nil {
+		return
+	}
+
+	if upstreamId != "" {
+		var outboxPayload []byte
+		if outboxPayload, err = json.Marshal(outboxDeleteParticipantPayload{ParticipantUpstreamId: upstreamId}); err != nil {
+			return
+		}
+		if _, err = tx.Exec(sqlInsertOutboxEntry, outboxOpDeleteParticipant, participantId, outboxPayload, time.Now()); err != nil {
+			p.logger.Error("error enqueueing delete-participant outbox entry",
+				zap.String("participantId", participantId), zap.Error(err))
+			return
+		}
 	}
+
+	err = tx.Commit()
 	return
 }
 
@@ -575,6 +1702,39 @@ func (p *BBashDB) InsertBug(bug *types.BugStruct) (err error) {
 	return
 }
 
+// InsertBugsTx inserts bugs within a single transaction: if any insert fails, every insert in the
+// batch is rolled back rather than leaving the earlier ones committed, and inserted only reflects
+// rows that actually persisted.
+func (p *BBashDB) InsertBugsTx(bugs []types.BugStruct) (inserted []types.BugStruct, err error) {
+	if len(bugs) == 0 {
+		return
+	}
+
+	tx, err := p.db.Begin()
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				p.logger.Error("error rolling back bug import", zap.Error(rollbackErr))
+			}
+			inserted = nil
+		}
+	}()
+
+	for i := range bugs {
+		if err = tx.QueryRow(sqlInsertBug, bugs[i].Campaign, bugs[i].Category, bugs[i].PointValue).Scan(&bugs[i].Id); err != nil {
+			p.logger.Error("error inserting bug in batch", zap.Any("bug", bugs[i]), zap.Error(err))
+			return
+		}
+		inserted = append(inserted, bugs[i])
+	}
+
+	err = tx.Commit()
+	return
+}
+
 const sqlUpdateBug = `UPDATE bug
 		SET pointValue = $1
 		WHERE fk_campaign = (SELECT id FROM campaign WHERE name = $2) AND category = $3`
@@ -607,3 +1767,660 @@ func (p *BBashDB) SelectBugs() (bugs []types.BugStruct, err error) {
 	}
 	return
 }
+
+const sqlSelectBugsByCampaign = `SELECT bug.id, campaign.name, category, pointValue FROM bug
+		INNER JOIN campaign ON fk_campaign = campaign.Id
+		WHERE campaign.name = $1`
+
+// SelectBugsByCampaign lists bug categories scoped to a single campaign, unlike SelectBugs which
+// lists every category across every campaign.
+func (p *BBashDB) SelectBugsByCampaign(campaign string) (bugs []types.BugStruct, err error) {
+	rows, err := p.db.Query(sqlSelectBugsByCampaign, campaign)
+	if err != nil {
+		return
+	}
+
+	for rows.Next() {
+		bug := types.BugStruct{}
+		err = rows.Scan(&bug.Id, &bug.Campaign, &bug.Category, &bug.PointValue)
+		if err != nil {
+			return
+		}
+		bugs = append(bugs, bug)
+	}
+	return
+}
+
+const sqlDeleteBug = `DELETE FROM bug
+		WHERE fk_campaign = (SELECT id FROM campaign WHERE name = $1) AND category = $2`
+
+func (p *BBashDB) DeleteBug(campaign, category string) (rowsAffected int64, err error) {
+	res, err := p.db.Exec(sqlDeleteBug, campaign, category)
+	if err != nil {
+		return
+	}
+	rowsAffected, err = res.RowsAffected()
+	return
+}
+
+const sqlInsertStopwatchStart = `INSERT INTO stopwatch
+		(fk_participant, issue_ref, started_at)
+		VALUES ($1, $2, $3)
+		RETURNING Id`
+
+func (p *BBashDB) InsertStopwatchStart(stopwatch *types.StopwatchStruct) (err error) {
+	err = p.db.QueryRow(sqlInsertStopwatchStart, stopwatch.ParticipantID, stopwatch.IssueRef, stopwatch.StartedAt).
+		Scan(&stopwatch.ID)
+	if err != nil {
+		p.logger.Error("error inserting stopwatch start", zap.Any("stopwatch", stopwatch), zap.Error(err))
+	}
+	return
+}
+
+const sqlStopStopwatch = `UPDATE stopwatch
+		SET stopped_at = $1,
+		    elapsed_seconds = EXTRACT(EPOCH FROM ($1 - started_at))
+		WHERE fk_participant = $2 AND issue_ref = $3 AND stopped_at IS NULL
+		RETURNING Id, started_at, elapsed_seconds`
+
+func (p *BBashDB) StopStopwatch(participantId, issueRef string, stoppedAt time.Time) (stopwatch *types.StopwatchStruct, err error) {
+	sw := &types.StopwatchStruct{ParticipantID: participantId, IssueRef: issueRef, StoppedAt: &stoppedAt}
+	err = p.db.QueryRow(sqlStopStopwatch, stoppedAt, participantId, issueRef).
+		Scan(&sw.ID, &sw.StartedAt, &sw.ElapsedSeconds)
+	if err != nil {
+		p.logger.Error("error stopping stopwatch",
+			zap.String("participantId", participantId), zap.String("issueRef", issueRef), zap.Error(err))
+		return
+	}
+	stopwatch = sw
+	return
+}
+
+const sqlSelectCompletedStopwatch = `SELECT Id, started_at, stopped_at, elapsed_seconds
+		FROM stopwatch
+		WHERE fk_participant = $1 AND issue_ref = $2 AND stopped_at IS NOT NULL
+		ORDER BY stopped_at DESC
+		LIMIT 1`
+
+// SelectCompletedStopwatch returns the most recently stopped stopwatch for participantId/issueRef,
+// or a nil stopwatch (no error) when the participant never ran one for this issue.
+func (p *BBashDB) SelectCompletedStopwatch(participantId, issueRef string) (stopwatch *types.StopwatchStruct, err error) {
+	sw := &types.StopwatchStruct{ParticipantID: participantId, IssueRef: issueRef}
+	var stoppedAt time.Time
+	err = p.db.QueryRow(sqlSelectCompletedStopwatch, participantId, issueRef).
+		Scan(&sw.ID, &sw.StartedAt, &stoppedAt, &sw.ElapsedSeconds)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = nil
+		}
+		return
+	}
+	sw.StoppedAt = &stoppedAt
+	stopwatch = sw
+	return
+}
+
+const sqlInsertAuthToken = `INSERT INTO auth_token
+		(name, token_hash, role, created_on)
+		VALUES ($1, $2, $3, $4)
+		RETURNING Id`
+
+// InsertAuthToken persists a new hashed API token (see internal/auth.HashToken), storing
+// token.CreatedOn as given, and filling in token.Id from the new row.
+func (p *BBashDB) InsertAuthToken(token *types.AuthTokenStruct) (guid string, err error) {
+	err = p.db.QueryRow(sqlInsertAuthToken, token.Name, token.TokenHash, token.Role, token.CreatedOn).
+		Scan(&guid)
+	if err != nil {
+		p.logger.Error("error inserting auth token", zap.String("name", token.Name), zap.Error(err))
+		return
+	}
+	token.Id = guid
+	return
+}
+
+const sqlGetAuthTokenByHash = `SELECT Id, name, token_hash, role, created_on, revoked_on
+		FROM auth_token
+		WHERE token_hash = $1 AND revoked_on IS NULL`
+
+// GetAuthTokenByHash looks up a live (un-revoked) token by its hash, returning a nil token (no
+// error) when it doesn't match any issued token, consistent with SelectCompletedStopwatch's
+// not-found convention.
+func (p *BBashDB) GetAuthTokenByHash(tokenHash string) (token *types.AuthTokenStruct, err error) {
+	t := &types.AuthTokenStruct{}
+	var revokedOn sql.NullTime
+	err = p.db.QueryRow(sqlGetAuthTokenByHash, tokenHash).
+		Scan(&t.Id, &t.Name, &t.TokenHash, &t.Role, &t.CreatedOn, &revokedOn)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = nil
+		}
+		return
+	}
+	if revokedOn.Valid {
+		t.RevokedOn = &revokedOn.Time
+	}
+	token = t
+	return
+}
+
+const sqlListAuthTokens = `SELECT Id, name, token_hash, role, created_on, revoked_on
+		FROM auth_token
+		ORDER BY created_on DESC`
+
+func (p *BBashDB) ListAuthTokens() (tokens []types.AuthTokenStruct, err error) {
+	rows, err := p.db.Query(sqlListAuthTokens)
+	if err != nil {
+		return
+	}
+
+	for rows.Next() {
+		t := types.AuthTokenStruct{}
+		var revokedOn sql.NullTime
+		err = rows.Scan(&t.Id, &t.Name, &t.TokenHash, &t.Role, &t.CreatedOn, &revokedOn)
+		if err != nil {
+			return
+		}
+		if revokedOn.Valid {
+			t.RevokedOn = &revokedOn.Time
+		}
+		tokens = append(tokens, t)
+	}
+	return
+}
+
+const sqlRevokeAuthToken = `UPDATE auth_token
+		SET revoked_on = $1
+		WHERE Id = $2 AND revoked_on IS NULL`
+
+func (p *BBashDB) RevokeAuthToken(guid string, revokedOn time.Time) (rowsAffected int64, err error) {
+	res, err := p.db.Exec(sqlRevokeAuthToken, revokedOn, guid)
+	if err != nil {
+		return
+	}
+	rowsAffected, err = res.RowsAffected()
+	return
+}
+
+const sqlInsertAdmin = `INSERT INTO admin
+		(subject, role, created_on)
+		VALUES ($1, $2, $3)
+		RETURNING Id`
+
+// InsertAdmin provisions subject as an admin identity, storing admin.CreatedOn as given, and
+// filling in admin.Id from the new row.
+func (p *BBashDB) InsertAdmin(admin *types.AdminStruct) (guid string, err error) {
+	err = p.db.QueryRow(sqlInsertAdmin, admin.Subject, admin.Role, admin.CreatedOn).Scan(&guid)
+	if err != nil {
+		p.logger.Error("error inserting admin", zap.String("subject", admin.Subject), zap.Error(err))
+		return
+	}
+	admin.Id = guid
+	return
+}
+
+const sqlGetAdminBySubject = `SELECT Id, subject, role, created_on
+		FROM admin
+		WHERE subject = $1`
+
+// GetAdminBySubject looks up a provisioned admin by subject, returning a nil admin (no error) when
+// subject hasn't been provisioned, consistent with GetAuthTokenByHash's not-found convention.
+func (p *BBashDB) GetAdminBySubject(subject string) (admin *types.AdminStruct, err error) {
+	a := &types.AdminStruct{}
+	err = p.db.QueryRow(sqlGetAdminBySubject, subject).Scan(&a.Id, &a.Subject, &a.Role, &a.CreatedOn)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = nil
+		}
+		return
+	}
+	admin = a
+	return
+}
+
+const sqlListAdmins = `SELECT Id, subject, role, created_on
+		FROM admin
+		ORDER BY created_on DESC`
+
+func (p *BBashDB) ListAdmins() (admins []types.AdminStruct, err error) {
+	rows, err := p.db.Query(sqlListAdmins)
+	if err != nil {
+		return
+	}
+
+	for rows.Next() {
+		a := types.AdminStruct{}
+		if err = rows.Scan(&a.Id, &a.Subject, &a.Role, &a.CreatedOn); err != nil {
+			return
+		}
+		admins = append(admins, a)
+	}
+	return
+}
+
+const sqlDeleteAdmin = `DELETE FROM admin WHERE Id = $1`
+
+func (p *BBashDB) DeleteAdmin(guid string) (rowsAffected int64, err error) {
+	res, err := p.db.Exec(sqlDeleteAdmin, guid)
+	if err != nil {
+		return
+	}
+	rowsAffected, err = res.RowsAffected()
+	return
+}
+
+const sqlInsertAuditEntry = `INSERT INTO audit_entry
+		(subject, role, method, path, status_code, occurred_on)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING Id`
+
+// InsertAuditEntry persists a record of a single /admin call, so who did what and when stays
+// attributable regardless of which internal/auth.Authenticator authenticated the caller.
+func (p *BBashDB) InsertAuditEntry(entry *types.AuditEntryStruct) (guid string, err error) {
+	err = p.db.QueryRow(sqlInsertAuditEntry,
+		entry.Subject, entry.Role, entry.Method, entry.Path, entry.StatusCode, entry.OccurredOn).
+		Scan(&guid)
+	if err != nil {
+		p.logger.Error("error inserting audit entry", zap.Any("entry", entry), zap.Error(err))
+		return
+	}
+	entry.Id = guid
+	return
+}
+
+const sqlGetIdempotencyRecord = `SELECT Id, key, route, body_hash, status_code, content_type, response_body, created_on
+		FROM idempotency_key
+		WHERE key = $1 AND route = $2`
+
+// GetIdempotencyRecord looks up a previously-saved response for a key/route pair, returning a nil
+// record (no error) when no matching retry has been seen before, consistent with
+// GetAuthTokenByHash's not-found convention.
+func (p *BBashDB) GetIdempotencyRecord(key, route string) (record *types.IdempotencyRecordStruct, err error) {
+	r := &types.IdempotencyRecordStruct{}
+	err = p.db.QueryRow(sqlGetIdempotencyRecord, key, route).
+		Scan(&r.Id, &r.Key, &r.Route, &r.BodyHash, &r.StatusCode, &r.ContentType, &r.ResponseBody, &r.CreatedOn)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = nil
+		}
+		return
+	}
+	record = r
+	return
+}
+
+const sqlSaveIdempotencyRecord = `INSERT INTO idempotency_key
+		(key, route, body_hash, status_code, content_type, response_body, created_on)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (key, route) DO NOTHING
+		RETURNING Id`
+
+// SaveIdempotencyRecord caches a bulk-import response under its Idempotency-Key/route pair; a
+// concurrent retry that already won the race is left alone (ON CONFLICT DO NOTHING) rather than
+// overwritten, so the first response is always the one replayed.
+func (p *BBashDB) SaveIdempotencyRecord(record *types.IdempotencyRecordStruct) (guid string, err error) {
+	err = p.db.QueryRow(sqlSaveIdempotencyRecord,
+		record.Key, record.Route, record.BodyHash, record.StatusCode, record.ContentType, record.ResponseBody, record.CreatedOn).
+		Scan(&guid)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = nil
+		}
+		return
+	}
+	record.Id = guid
+	return
+}
+
+const sqlInsertAPIKey = `INSERT INTO api_keys
+		(key_id, name, algorithm, public_key, created_on)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING Id`
+
+// InsertAPIKey persists a caller's public key (see the register-key CLI subcommand), filling in
+// key.Id from the new row.
+func (p *BBashDB) InsertAPIKey(key *types.APIKeyStruct) (guid string, err error) {
+	err = p.db.QueryRow(sqlInsertAPIKey, key.KeyId, key.Name, key.Algorithm, key.PublicKey, key.CreatedOn).
+		Scan(&guid)
+	if err != nil {
+		p.logger.Error("error inserting api key", zap.String("keyId", key.KeyId), zap.Error(err))
+		return
+	}
+	key.Id = guid
+	return
+}
+
+const sqlGetAPIKeyByKeyID = `SELECT Id, key_id, name, algorithm, public_key, created_on, revoked_on
+		FROM api_keys
+		WHERE key_id = $1 AND revoked_on IS NULL`
+
+// GetAPIKeyByKeyID looks up a live (un-revoked) key by the keyId a caller's Signature header
+// names, returning a nil key (no error) when it doesn't match any registered key, consistent with
+// GetAuthTokenByHash's not-found convention.
+func (p *BBashDB) GetAPIKeyByKeyID(keyId string) (key *types.APIKeyStruct, err error) {
+	k := &types.APIKeyStruct{}
+	var revokedOn sql.NullTime
+	err = p.db.QueryRow(sqlGetAPIKeyByKeyID, keyId).
+		Scan(&k.Id, &k.KeyId, &k.Name, &k.Algorithm, &k.PublicKey, &k.CreatedOn, &revokedOn)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = nil
+		}
+		return
+	}
+	if revokedOn.Valid {
+		k.RevokedOn = &revokedOn.Time
+	}
+	key = k
+	return
+}
+
+const sqlSelectDueOutboxEntries = `SELECT Id, op, fk_participant, payload, attempts, next_attempt_at, done, dead_lettered, created_on
+		FROM upstream_outbox
+		WHERE done = false AND dead_lettered = false AND next_attempt_at <= $1
+		ORDER BY next_attempt_at
+		LIMIT $2`
+
+// SelectDueOutboxEntries returns up to limit not-yet-done, not-dead-lettered upstream_outbox rows
+// whose next_attempt_at has passed, oldest first - what internal/outbox.Worker drains on every tick.
+func (p *BBashDB) SelectDueOutboxEntries(now time.Time, limit int) (entries []types.OutboxEntryStruct, err error) {
+	rows, err := p.db.Query(sqlSelectDueOutboxEntries, now, limit)
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	for rows.Next() {
+		entry := types.OutboxEntryStruct{}
+		if err = rows.Scan(&entry.Id, &entry.Op, &entry.ParticipantId, &entry.Payload, &entry.Attempts,
+			&entry.NextAttemptAt, &entry.Done, &entry.DeadLettered, &entry.CreatedOn); err != nil {
+			return
+		}
+		entries = append(entries, entry)
+	}
+	return
+}
+
+const sqlMarkOutboxEntryDone = `UPDATE upstream_outbox SET done = true WHERE Id = $1`
+
+// MarkOutboxEntryDone retires an entry after its upstream publish succeeds.
+func (p *BBashDB) MarkOutboxEntryDone(id string) (err error) {
+	_, err = p.db.Exec(sqlMarkOutboxEntryDone, id)
+	return
+}
+
+const sqlRescheduleOutboxEntry = `UPDATE upstream_outbox SET attempts = $1, next_attempt_at = $2 WHERE Id = $3`
+
+// RescheduleOutboxEntry records a failed publish attempt and pushes next_attempt_at out, per
+// internal/outbox.Worker's exponential backoff.
+func (p *BBashDB) RescheduleOutboxEntry(id string, attempts int, nextAttemptAt time.Time) (err error) {
+	_, err = p.db.Exec(sqlRescheduleOutboxEntry, attempts, nextAttemptAt, id)
+	return
+}
+
+const sqlCountPendingOutboxEntries = `SELECT count(*) FROM upstream_outbox WHERE done = false`
+
+// CountPendingOutboxEntries reports the queue depth the /admin/outbox endpoint exposes.
+func (p *BBashDB) CountPendingOutboxEntries() (pending int, err error) {
+	err = p.db.QueryRow(sqlCountPendingOutboxEntries).Scan(&pending)
+	return
+}
+
+const sqlMarkOutboxEntryDeadLettered = `UPDATE upstream_outbox SET dead_lettered = true WHERE Id = $1`
+
+// MarkOutboxEntryDeadLettered retires an entry internal/outbox.Worker has given up retrying,
+// leaving it for GET /admin/outbox/dead-letter to surface.
+func (p *BBashDB) MarkOutboxEntryDeadLettered(id string) (err error) {
+	_, err = p.db.Exec(sqlMarkOutboxEntryDeadLettered, id)
+	return
+}
+
+const sqlSelectDeadLetteredOutboxEntries = `SELECT Id, op, fk_participant, payload, attempts, next_attempt_at, done, dead_lettered, created_on
+		FROM upstream_outbox
+		WHERE dead_lettered = true
+		ORDER BY created_on`
+
+// SelectDeadLetteredOutboxEntries returns every dead-lettered upstream_outbox row, oldest first,
+// for the GET /admin/outbox/dead-letter endpoint.
+func (p *BBashDB) SelectDeadLetteredOutboxEntries() (entries []types.OutboxEntryStruct, err error) {
+	rows, err := p.db.Query(sqlSelectDeadLetteredOutboxEntries)
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	for rows.Next() {
+		entry := types.OutboxEntryStruct{}
+		if err = rows.Scan(&entry.Id, &entry.Op, &entry.ParticipantId, &entry.Payload, &entry.Attempts,
+			&entry.NextAttemptAt, &entry.Done, &entry.DeadLettered, &entry.CreatedOn); err != nil {
+			return
+		}
+		entries = append(entries, entry)
+	}
+	return
+}
+
+const sqlReplayOutboxEntry = `UPDATE upstream_outbox SET dead_lettered = false, attempts = 0, next_attempt_at = $1 WHERE Id = $2`
+
+// ReplayOutboxEntry clears id's dead-lettered state and resets its attempts so
+// internal/outbox.Worker's next tick picks it back up, for the
+// POST /admin/outbox/dead-letter/:id/replay endpoint.
+func (p *BBashDB) ReplayOutboxEntry(id string) (err error) {
+	_, err = p.db.Exec(sqlReplayOutboxEntry, time.Now(), id)
+	return
+}
+
+const sqlSetParticipantUpstreamId = `UPDATE participant SET upstream_id = $1 WHERE Id = $2`
+
+// SetParticipantUpstreamId records the id a participant was created under upstream, once
+// internal/outbox.Worker's OpCreateParticipant publish succeeds.
+func (p *BBashDB) SetParticipantUpstreamId(participantId, upstreamId string) (err error) {
+	_, err = p.db.Exec(sqlSetParticipantUpstreamId, upstreamId, participantId)
+	return
+}
+
+const sqlInsertSubscription = `INSERT INTO subscription
+		(url, secret, events, created_on)
+		VALUES ($1, $2, $3, $4)
+		RETURNING Id`
+
+// InsertSubscription registers subscription, serializing its Events slice to JSON since this
+// tree's Dialect abstraction (see dialect.go) doesn't guarantee a native array column across
+// every supported BBASH_DB_DRIVER backend - the same reason outbox/event payloads are stored as
+// marshaled JSON text rather than typed columns.
+func (p *BBashDB) InsertSubscription(subscription *types.SubscriptionStruct) (guid string, err error) {
+	var events []byte
+	if events, err = json.Marshal(subscription.Events); err != nil {
+		return
+	}
+
+	subscription.CreatedOn = time.Now()
+	err = p.db.QueryRow(sqlInsertSubscription, subscription.URL, subscription.Secret, events, subscription.CreatedOn).Scan(&guid)
+	return
+}
+
+const sqlSelectSubscriptions = `SELECT Id, url, secret, events, created_on FROM subscription`
+
+// GetSubscriptions returns every registered subscription, for internal/events.Dispatcher to match
+// against each due event_outbox entry's Type and for the admin /admin/subscriptions list route.
+func (p *BBashDB) GetSubscriptions() (subscriptions []types.SubscriptionStruct, err error) {
+	rows, err := p.db.Query(sqlSelectSubscriptions)
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	for rows.Next() {
+		subscription := types.SubscriptionStruct{}
+		var events []byte
+		if err = rows.Scan(&subscription.Id, &subscription.URL, &subscription.Secret, &events, &subscription.CreatedOn); err != nil {
+			return
+		}
+		if err = json.Unmarshal(events, &subscription.Events); err != nil {
+			return
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+	return
+}
+
+const sqlDeleteSubscription = `DELETE FROM subscription WHERE Id = $1`
+
+// DeleteSubscription removes a subscription by id, so a retired integration stops receiving
+// events without leaving event_outbox rows it can never be matched against anymore.
+func (p *BBashDB) DeleteSubscription(id string) (rowsAffected int64, err error) {
+	res, err := p.db.Exec(sqlDeleteSubscription, id)
+	if err != nil {
+		return
+	}
+	rowsAffected, err = res.RowsAffected()
+	return
+}
+
+const sqlSelectDueEventEntries = `SELECT Id, type, payload, attempts, next_attempt_at, done, created_on
+		FROM event_outbox
+		WHERE done = false AND next_attempt_at <= $1
+		ORDER BY next_attempt_at
+		LIMIT $2`
+
+// SelectDueEventEntries returns up to limit not-yet-done event_outbox rows whose next_attempt_at
+// has passed, oldest first - what internal/events.Dispatcher drains on every tick, the same shape
+// as SelectDueOutboxEntries.
+func (p *BBashDB) SelectDueEventEntries(now time.Time, limit int) (entries []types.EventEntryStruct, err error) {
+	rows, err := p.db.Query(sqlSelectDueEventEntries, now, limit)
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	for rows.Next() {
+		entry := types.EventEntryStruct{}
+		if err = rows.Scan(&entry.Id, &entry.Type, &entry.Payload, &entry.Attempts,
+			&entry.NextAttemptAt, &entry.Done, &entry.CreatedOn); err != nil {
+			return
+		}
+		entries = append(entries, entry)
+	}
+	return
+}
+
+const sqlMarkEventEntryDone = `UPDATE event_outbox SET done = true WHERE Id = $1`
+
+// MarkEventEntryDone retires an entry once every matching subscription has accepted it.
+func (p *BBashDB) MarkEventEntryDone(id string) (err error) {
+	_, err = p.db.Exec(sqlMarkEventEntryDone, id)
+	return
+}
+
+const sqlRescheduleEventEntry = `UPDATE event_outbox SET attempts = $1, next_attempt_at = $2 WHERE Id = $3`
+
+// RescheduleEventEntry records a failed dispatch attempt and pushes next_attempt_at out, per
+// internal/events.Dispatcher's exponential backoff.
+func (p *BBashDB) RescheduleEventEntry(id string, attempts int, nextAttemptAt time.Time) (err error) {
+	_, err = p.db.Exec(sqlRescheduleEventEntry, attempts, nextAttemptAt, id)
+	return
+}
+
+const sqlCountPendingEventEntries = `SELECT count(*) FROM event_outbox WHERE done = false`
+
+// CountPendingEventEntries reports the queue depth the /admin/subscriptions endpoint can surface.
+func (p *BBashDB) CountPendingEventEntries() (pending int, err error) {
+	err = p.db.QueryRow(sqlCountPendingEventEntries).Scan(&pending)
+	return
+}
+
+const sqlInsertAuditEvent = `INSERT INTO audit_events
+		(actor, action, campaign_name, scp_name, login_name, participant_upstream_id,
+		 request_ip, success, upstream_status, error, occurred_on)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING Id`
+
+// insertAuditEventTx is InsertAuditEvent's transactional counterpart: it inserts event within tx
+// instead of via p.db directly, so a caller that already has an open transaction (e.g.
+// InsertCampaign) can record an audit_events row that commits or rolls back with the mutation it
+// describes, rather than being written separately afterwards the way internal/auditlog.Logger's
+// callers in server.go do today.
+func (p *BBashDB) insertAuditEventTx(tx *sql.Tx, event types.AuditEventStruct) (err error) {
+	err = tx.QueryRow(sqlInsertAuditEvent,
+		event.Actor, event.Action, event.CampaignName, event.ScpName, event.LoginName, event.ParticipantUpstreamId,
+		event.RequestIP, event.Success, event.UpstreamStatus, event.Error, event.OccurredOn).
+		Scan(&event.Id)
+	return
+}
+
+// InsertAuditEvent persists a record of a single administrative mutation internal/auditlog.Logger
+// was asked to record, so who did what to which campaign/participant stays attributable -
+// complementary to InsertAuditEntry/audit_entry, which records every /admin call generically.
+func (p *BBashDB) InsertAuditEvent(event *types.AuditEventStruct) (guid string, err error) {
+	err = p.db.QueryRow(sqlInsertAuditEvent,
+		event.Actor, event.Action, event.CampaignName, event.ScpName, event.LoginName, event.ParticipantUpstreamId,
+		event.RequestIP, event.Success, event.UpstreamStatus, event.Error, event.OccurredOn).
+		Scan(&guid)
+	if err != nil {
+		p.logger.Error("error inserting audit event", zap.Any("event", event), zap.Error(err))
+		return
+	}
+	event.Id = guid
+	return
+}
+
+const sqlSelectAuditEventsBase = `SELECT Id, actor, action, campaign_name, scp_name, login_name, participant_upstream_id,
+		request_ip, success, upstream_status, error, occurred_on
+		FROM audit_events`
+
+// ListAuditEvents returns every audit_events row matching filter, most recent first, for the
+// GET /admin/audit route - filter's zero-value fields are omitted from the WHERE clause entirely
+// rather than matched literally.
+func (p *BBashDB) ListAuditEvents(filter types.AuditEventFilter) (events []types.AuditEventStruct, err error) {
+	var clauses []string
+	var args []interface{}
+
+	if filter.Actor != "" {
+		args = append(args, filter.Actor)
+		clauses = append(clauses, fmt.Sprintf("actor = $%d", len(args)))
+	}
+	if filter.CampaignName != "" {
+		args = append(args, filter.CampaignName)
+		clauses = append(clauses, fmt.Sprintf("campaign_name = $%d", len(args)))
+	}
+	if !filter.From.IsZero() {
+		args = append(args, filter.From)
+		clauses = append(clauses, fmt.Sprintf("occurred_on >= $%d", len(args)))
+	}
+	if !filter.To.IsZero() {
+		args = append(args, filter.To)
+		clauses = append(clauses, fmt.Sprintf("occurred_on <= $%d", len(args)))
+	}
+
+	query := sqlSelectAuditEventsBase
+	if len(clauses) > 0 {
+		query += " WHERE " + strings.Join(clauses, " AND ")
+	}
+	query += " ORDER BY occurred_on DESC"
+
+	rows, err := p.db.Query(query, args...)
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	for rows.Next() {
+		event := types.AuditEventStruct{}
+		if err = rows.Scan(&event.Id, &event.Actor, &event.Action, &event.CampaignName, &event.ScpName, &event.LoginName,
+			&event.ParticipantUpstreamId, &event.RequestIP, &event.Success, &event.UpstreamStatus, &event.Error,
+			&event.OccurredOn); err != nil {
+			return
+		}
+		events = append(events, event)
+	}
+	return
+}
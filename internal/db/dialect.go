@@ -0,0 +1,138 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package db
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// EnvDBDriver selects which Dialect BBashDB and PollStruct are opened against; unset falls back
+// to DialectPostgres, preserving this application's original, Postgres-only behavior, while an
+// unrecognized value is an error (see ResolveDialect).
+const EnvDBDriver = "BBASH_DB_DRIVER"
+
+const (
+	DialectPostgres = "postgres"
+	DialectMySQL    = "mysql"
+	DialectSQLite   = "sqlite"
+)
+
+// Dialect is the seam a database/sql driver plugs in at: the Go driver name to pass to sql.Open,
+// plus the handful of SQL differences the hand-written queries in db.go and db-poll.go otherwise
+// bake in as Postgres-specific ($N placeholders, INSERT ... RETURNING id). It intentionally does
+// not attempt to abstract the full SQL surface - only what's needed to point PollStruct and
+// BBashDB at a different database/sql driver.
+type Dialect struct {
+	// Name is one of the Dialect* constants.
+	Name string
+	// DriverName is the database/sql driver registered under this name (e.g. via an
+	// anonymous "_" import of the driver package), passed directly to sql.Open.
+	DriverName string
+	// Placeholder renders the nth (1-indexed) bind parameter in this dialect's syntax, e.g.
+	// "$1" for Postgres/pgx or "?" for MySQL/SQLite.
+	Placeholder func(n int) string
+	// SupportsReturning reports whether "INSERT ... RETURNING <col>" can be used to read back
+	// a generated column, as PollStruct.InsertDeadLetter does. MySQL and SQLite don't support
+	// it. Note this repo's generated ids (campaign.Id, participant.Id, dead_letter.id, etc.) are
+	// all server-generated UUIDs, not autoincrement integers, so the usual MySQL/SQLite fallback
+	// of a last-insert-id query doesn't apply here - a caller targeting those dialects needs to
+	// generate the id client-side before the INSERT instead, the same way RewritePlaceholders
+	// only handles placeholder syntax and leaves this gap to the caller.
+	SupportsReturning bool
+}
+
+func dollarPlaceholder(n int) string { return fmt.Sprintf("$%d", n) }
+func questionPlaceholder(int) string { return "?" }
+
+// dollarPlaceholderPattern matches a Postgres-style $N bind parameter, the form every hand-written
+// query in db.go and db-poll.go is written against.
+var dollarPlaceholderPattern = regexp.MustCompile(`\$(\d+)`)
+
+// RewritePlaceholders rewrites every $N bind parameter in query to dialect's own placeholder
+// syntax, so the Postgres-shaped query literals in db.go and db-poll.go can be reused unmodified
+// against a dialect whose driver doesn't understand $N (MySQL and SQLite both bind by ? instead,
+// in parameter order). A no-op for DialectPostgres, since the queries are already written in its
+// syntax; callers targeting another dialect are still responsible for the RETURNING-clause gap
+// SupportsReturning documents - this only handles placeholder syntax.
+func (d Dialect) RewritePlaceholders(query string) string {
+	if d.Name == DialectPostgres || d.Name == "" {
+		return query
+	}
+	return dollarPlaceholderPattern.ReplaceAllStringFunc(query, func(match string) string {
+		n := 0
+		if _, err := fmt.Sscanf(match, "$%d", &n); err != nil {
+			return match
+		}
+		return d.Placeholder(n)
+	})
+}
+
+// dialects holds every Dialect this build knows how to describe. Only DialectPostgres has a
+// driver actually wired up (via the existing lib/pq import); MySQL and SQLite are named and
+// described here as the abstraction's extension points, but ResolveDialect refuses to select
+// them until this build is compiled in an environment with network access to vendor
+// github.com/go-sql-driver/mysql and modernc.org/sqlite (the same constraint documented on
+// poll.KafkaSource and poll.CloudWatchSource for the AWS/Kafka client libraries).
+var dialects = map[string]Dialect{
+	DialectPostgres: {
+		Name:              DialectPostgres,
+		DriverName:        "postgres",
+		Placeholder:       dollarPlaceholder,
+		SupportsReturning: true,
+	},
+	DialectMySQL: {
+		Name:              DialectMySQL,
+		DriverName:        "mysql",
+		Placeholder:       questionPlaceholder,
+		SupportsReturning: false,
+	},
+	DialectSQLite: {
+		Name:              DialectSQLite,
+		DriverName:        "sqlite",
+		Placeholder:       questionPlaceholder,
+		SupportsReturning: false,
+	},
+}
+
+// driversAvailable lists the Name of every Dialect whose DriverName is actually registered with
+// database/sql in this build.
+var driversAvailable = map[string]bool{
+	DialectPostgres: true,
+}
+
+// ResolveDialect looks up name (one of the Dialect* constants) and errors if name is unknown or
+// names a dialect this build has no driver for. An empty name resolves to DialectPostgres.
+func ResolveDialect(name string) (dialect Dialect, err error) {
+	if name == "" {
+		name = DialectPostgres
+	}
+
+	dialect, ok := dialects[name]
+	if !ok {
+		err = fmt.Errorf("unknown %s %q", EnvDBDriver, name)
+		return
+	}
+	if !driversAvailable[name] {
+		err = fmt.Errorf("%s %q is not available in this build: its database/sql driver isn't compiled in", EnvDBDriver, name)
+		return
+	}
+	return
+}
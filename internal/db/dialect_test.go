@@ -0,0 +1,66 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveDialectEmptyNameDefaultsToPostgres(t *testing.T) {
+	dialect, err := ResolveDialect("")
+	assert.NoError(t, err)
+	assert.Equal(t, DialectPostgres, dialect.Name)
+	assert.Equal(t, "$1", dialect.Placeholder(1))
+	assert.True(t, dialect.SupportsReturning)
+}
+
+func TestResolveDialectPostgres(t *testing.T) {
+	dialect, err := ResolveDialect(DialectPostgres)
+	assert.NoError(t, err)
+	assert.Equal(t, "postgres", dialect.DriverName)
+}
+
+func TestResolveDialectUnknownName(t *testing.T) {
+	_, err := ResolveDialect("oracle")
+	assert.Error(t, err)
+}
+
+func TestResolveDialectMySQLNotAvailableInThisBuild(t *testing.T) {
+	_, err := ResolveDialect(DialectMySQL)
+	assert.Error(t, err)
+}
+
+func TestResolveDialectSQLiteNotAvailableInThisBuild(t *testing.T) {
+	_, err := ResolveDialect(DialectSQLite)
+	assert.Error(t, err)
+}
+
+func TestRewritePlaceholdersPostgresNoOp(t *testing.T) {
+	dialect, err := ResolveDialect(DialectPostgres)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM x WHERE a = $1 AND b = $2", dialect.RewritePlaceholders("SELECT * FROM x WHERE a = $1 AND b = $2"))
+}
+
+func TestRewritePlaceholdersMySQL(t *testing.T) {
+	dialect := dialects[DialectMySQL]
+	assert.Equal(t, "SELECT * FROM x WHERE a = ? AND b = ?", dialect.RewritePlaceholders("SELECT * FROM x WHERE a = $1 AND b = $2"))
+}
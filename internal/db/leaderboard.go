@@ -0,0 +1,162 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+	"go.uber.org/zap"
+)
+
+const sqlSelectLeaderboard = `SELECT
+		leaderboard.fk_participant, participant.login_name, team.name, leaderboard.score, leaderboard.rank, leaderboard.updated_on
+	FROM leaderboard
+	INNER JOIN campaign ON leaderboard.fk_campaign = campaign.Id
+	INNER JOIN participant ON leaderboard.fk_participant = participant.Id
+	LEFT JOIN team ON leaderboard.fk_team = team.Id
+	WHERE campaign.name = $1
+		AND participant.login_name ILIKE $2
+		AND leaderboard.rank > $3
+	ORDER BY leaderboard.rank ASC
+	LIMIT $4`
+
+// GetLeaderboard returns campaignName's cached standings, ordered by rank, out of the leaderboard
+// table RefreshLeaderboard (and the periodic refresher StartLeaderboardRefresher schedules)
+// populates - a read straight off an indexed table rather than sorting every participant
+// client-side the way SelectParticipantsInCampaign's callers otherwise would. opts.Cursor resumes
+// after a prior page's last Rank (empty for the first page); opts.Filter, when set, matches as a
+// case-insensitive substring against login_name.
+func (p *BBashDB) GetLeaderboard(campaignName string, opts ListOptions) (entries []types.LeaderboardEntry, err error) {
+	afterRank := 0
+	if opts.Cursor != "" {
+		if afterRank, err = strconv.Atoi(opts.Cursor); err != nil {
+			err = fmt.Errorf("invalid cursor %q: %w", opts.Cursor, err)
+			return
+		}
+	}
+
+	rows, err := p.db.Query(sqlSelectLeaderboard, campaignName, "%"+opts.Filter+"%", afterRank, clampLimit(opts.Limit))
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		entry := types.LeaderboardEntry{CampaignName: campaignName}
+		var nullableTeamName sql.NullString
+		if err = rows.Scan(&entry.ParticipantId, &entry.LoginName, &nullableTeamName, &entry.Score, &entry.Rank, &entry.UpdatedAt); err != nil {
+			return
+		}
+		if nullableTeamName.Valid {
+			entry.TeamName = nullableTeamName.String
+		}
+		entries = append(entries, entry)
+	}
+	err = rows.Err()
+	return
+}
+
+const sqlDeleteLeaderboardForCampaign = `DELETE FROM leaderboard
+	WHERE fk_campaign = (SELECT Id FROM campaign WHERE name = $1)`
+
+const sqlInsertLeaderboardRanked = `INSERT INTO leaderboard
+		(fk_campaign, fk_team, fk_participant, rank, score, updated_on)
+	SELECT participant.fk_campaign, participant.fk_team, participant.Id,
+	       RANK() OVER (ORDER BY Score DESC), Score, $2
+	FROM participant
+	INNER JOIN campaign ON participant.fk_campaign = campaign.Id
+	WHERE campaign.name = $1`
+
+// RefreshLeaderboard fully recomputes campaignName's leaderboard rows from the current participant
+// scores: every existing row for the campaign is discarded and replaced in one transaction, so
+// GetLeaderboard never serves a mix of old and new ranks mid-refresh. This is the safety net
+// StartLeaderboardRefresher runs on a schedule; ApplyScoringEvent and UpdateParticipantScoreCAS
+// don't maintain the leaderboard table incrementally themselves; a campaign-sized RANK() OVER
+// scan is cheap enough to run wholesale on every tick, and doing it this way avoids the
+// correctness risk of hand-maintaining per-row rank deltas as scores move past each other.
+func (p *BBashDB) RefreshLeaderboard(campaignName string) (err error) {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				p.logger.Error("error rolling back leaderboard refresh", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	if _, err = tx.Exec(sqlDeleteLeaderboardForCampaign, campaignName); err != nil {
+		return
+	}
+	if _, err = tx.Exec(sqlInsertLeaderboardRanked, campaignName, time.Now()); err != nil {
+		return
+	}
+	err = tx.Commit()
+	return
+}
+
+// StartLeaderboardRefresher begins calling RefreshLeaderboard for every active campaign on the
+// given cron spec (e.g. "@every 1m") until StopLeaderboardRefresher is called - the same
+// cron-scheduled shape as internal/outbox.Worker and internal/campaign.Scheduler.
+func (p *BBashDB) StartLeaderboardRefresher(spec string) (err error) {
+	p.leaderboardCron = cron.New()
+	if _, err = p.leaderboardCron.AddFunc(spec, p.refreshAllLeaderboards); err != nil {
+		return
+	}
+	p.leaderboardCron.Start()
+	return
+}
+
+// StopLeaderboardRefresher ends the refresh loop and waits for any in-flight tick to finish, or
+// for ctx to expire, whichever comes first.
+func (p *BBashDB) StopLeaderboardRefresher(ctx context.Context) (err error) {
+	stopped := p.leaderboardCron.Stop()
+	select {
+	case <-stopped.Done():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// refreshAllLeaderboards runs RefreshLeaderboard for every campaign that's currently active,
+// logging (rather than aborting the tick on) any single campaign's failure so one bad refresh
+// doesn't block the rest.
+func (p *BBashDB) refreshAllLeaderboards() {
+	campaigns, err := p.GetActiveCampaigns(time.Now())
+	if err != nil {
+		p.logger.Error("leaderboard refresher: select active campaigns", zap.Error(err))
+		return
+	}
+
+	for _, campaign := range campaigns {
+		if err := p.RefreshLeaderboard(campaign.Name); err != nil {
+			p.logger.Error("leaderboard refresher: refresh campaign", zap.String("campaign", campaign.Name), zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,123 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package db
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetLeaderboardInvalidCursor(t *testing.T) {
+	_, db, closeDbFunc := SetupMockDB(t)
+	defer closeDbFunc()
+
+	entries, err := db.GetLeaderboard(campaignName, ListOptions{Cursor: "not-a-cursor"})
+	assert.Error(t, err)
+	assert.Nil(t, entries)
+}
+
+func TestGetLeaderboardError(t *testing.T) {
+	mock, db, closeDbFunc := SetupMockDB(t)
+	defer closeDbFunc()
+
+	forcedError := fmt.Errorf("forced select leaderboard error")
+	mock.ExpectQuery(convertSqlToDbMockExpect(sqlSelectLeaderboard)).
+		WithArgs(campaignName, "%%", 0, DefaultListLimit).
+		WillReturnError(forcedError)
+
+	entries, err := db.GetLeaderboard(campaignName, ListOptions{})
+	assert.EqualError(t, err, forcedError.Error())
+	assert.Nil(t, entries)
+}
+
+func TestGetLeaderboard(t *testing.T) {
+	mock, db, closeDbFunc := SetupMockDB(t)
+	defer closeDbFunc()
+
+	mock.ExpectQuery(convertSqlToDbMockExpect(sqlSelectLeaderboard)).
+		WithArgs(campaignName, "%%", 1, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"fk_participant", "login_name", "team_name", "score", "rank", "updated_on"}).
+			AddRow(testParticipantGuid, loginName, "teamName", 9, 2, now))
+
+	entries, err := db.GetLeaderboard(campaignName, ListOptions{Limit: 10, Cursor: "1"})
+	assert.NoError(t, err)
+	assert.Equal(t, []types.LeaderboardEntry{
+		{
+			CampaignName:  campaignName,
+			ParticipantId: testParticipantGuid,
+			LoginName:     loginName,
+			TeamName:      "teamName",
+			Score:         9,
+			Rank:          2,
+			UpdatedAt:     now,
+		},
+	}, entries)
+}
+
+func TestRefreshLeaderboardDeleteError(t *testing.T) {
+	mock, db, closeDbFunc := SetupMockDB(t)
+	defer closeDbFunc()
+
+	forcedError := fmt.Errorf("forced delete leaderboard error")
+	mock.ExpectBegin()
+	mock.ExpectExec(convertSqlToDbMockExpect(sqlDeleteLeaderboardForCampaign)).
+		WithArgs(campaignName).
+		WillReturnError(forcedError)
+	mock.ExpectRollback()
+
+	assert.EqualError(t, db.RefreshLeaderboard(campaignName), forcedError.Error())
+}
+
+func TestRefreshLeaderboardInsertError(t *testing.T) {
+	mock, db, closeDbFunc := SetupMockDB(t)
+	defer closeDbFunc()
+
+	forcedError := fmt.Errorf("forced insert leaderboard error")
+	mock.ExpectBegin()
+	mock.ExpectExec(convertSqlToDbMockExpect(sqlDeleteLeaderboardForCampaign)).
+		WithArgs(campaignName).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(convertSqlToDbMockExpect(sqlInsertLeaderboardRanked)).
+		WithArgs(campaignName, sqlmock.AnyArg()).
+		WillReturnError(forcedError)
+	mock.ExpectRollback()
+
+	assert.EqualError(t, db.RefreshLeaderboard(campaignName), forcedError.Error())
+}
+
+func TestRefreshLeaderboard(t *testing.T) {
+	mock, db, closeDbFunc := SetupMockDB(t)
+	defer closeDbFunc()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(convertSqlToDbMockExpect(sqlDeleteLeaderboardForCampaign)).
+		WithArgs(campaignName).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(convertSqlToDbMockExpect(sqlInsertLeaderboardRanked)).
+		WithArgs(campaignName, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	assert.NoError(t, db.RefreshLeaderboard(campaignName))
+}
@@ -0,0 +1,108 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package db
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DefaultListLimit is the page size ListOptions.Limit falls back to when unset.
+const DefaultListLimit = 50
+
+// MaxListLimit caps ListOptions.Limit, so a caller can't force a page large enough to erase the
+// point of paginating in the first place.
+const MaxListLimit = 200
+
+// ListOptions configures a paginated, filtered list query. It deliberately only has the handful
+// of knobs SelectParticipantsInCampaignPaged actually needs rather than a generic query builder:
+// Cursor is the opaque string a prior page's nextCursor returned (empty for the first page), and
+// Filter is matched as a case-insensitive substring against the column the Paged method documents.
+type ListOptions struct {
+	Limit  int
+	Cursor string
+	Filter string
+
+	// TeamFilter, when non-nil, restricts SelectParticipantsInCampaignPaged to participants on
+	// that exact team name.
+	TeamFilter *string
+
+	// MinScore, when non-nil, restricts SelectParticipantsInCampaignPaged to participants whose
+	// Score is at least this value.
+	MinScore *int
+
+	// SortBy picks SelectParticipantsInCampaignPaged's order; the zero value is SortByScore.
+	SortBy ParticipantSort
+}
+
+// ParticipantSort is the set of columns SelectParticipantsInCampaignPaged can order by.
+type ParticipantSort string
+
+const (
+	// SortByScore is SelectParticipantsInCampaignPaged's default: keyset-paginated on
+	// (Score DESC, Id ASC), same as the leaderboard.
+	SortByScore ParticipantSort = "score"
+
+	// SortByJoinedAt and SortByLogin paginate by OFFSET rather than keyset: a second,
+	// independently-cursored keyset per sort column is a bigger feature than this method needs
+	// yet, and OFFSET is fine at the page sizes a UI participant list actually uses.
+	SortByJoinedAt ParticipantSort = "joinedAt"
+	SortByLogin    ParticipantSort = "login"
+)
+
+// clampLimit normalizes a requested page size: non-positive falls back to DefaultListLimit,
+// anything past MaxListLimit is capped to it.
+func clampLimit(limit int) int {
+	if limit <= 0 {
+		return DefaultListLimit
+	}
+	if limit > MaxListLimit {
+		return MaxListLimit
+	}
+	return limit
+}
+
+// leaderboardCursorSentinelScore stands in for "no cursor yet" in the keyset WHERE clause - a
+// score no real participant can reach, so "Score < sentinel" matches every row on the
+// first page the same way an explicit "is this the first page?" branch would, without one.
+const leaderboardCursorSentinelScore = 1<<31 - 1
+
+// encodeLeaderboardCursor packs the last row of a page into the opaque cursor string the next
+// page's ListOptions.Cursor is expected to round-trip back in.
+func encodeLeaderboardCursor(score int, id string) string {
+	return fmt.Sprintf("%d:%s", score, id)
+}
+
+// decodeLeaderboardCursor reverses encodeLeaderboardCursor; an empty cursor decodes to the
+// first-page sentinel.
+func decodeLeaderboardCursor(cursor string) (score int, id string, err error) {
+	if cursor == "" {
+		return leaderboardCursorSentinelScore, "", nil
+	}
+	parts := strings.SplitN(cursor, ":", 2)
+	if len(parts) != 2 {
+		return 0, "", fmt.Errorf("invalid cursor %q", cursor)
+	}
+	if score, err = strconv.Atoi(parts[0]); err != nil {
+		return 0, "", fmt.Errorf("invalid cursor %q: %w", cursor, err)
+	}
+	return score, parts[1], nil
+}
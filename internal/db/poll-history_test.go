@@ -0,0 +1,98 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package db
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordPollRun(t *testing.T) {
+	mock, db, closeDbFunc := SetupMockDBPoll(t)
+	defer closeDbFunc()
+
+	SetupMockPollHistoryInsert(mock, "1")
+
+	run := types.PollRun{
+		Source:      "GitHub",
+		StartedOn:   time.Now(),
+		Duration:    5 * time.Second,
+		RowsScraped: 42,
+	}
+	assert.NoError(t, db.RecordPollRun(run))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRecordPollRunInsertError(t *testing.T) {
+	mock, db, closeDbFunc := SetupMockDBPoll(t)
+	defer closeDbFunc()
+
+	forcedError := errors.New("forced poll history insert error")
+	mock.ExpectQuery(PollConvertSqlToDbMockExpect(sqlInsertPollHistory)).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnError(forcedError)
+
+	err := db.RecordPollRun(types.PollRun{Source: "Jira", StartedOn: time.Now()})
+	assert.Equal(t, forcedError, err)
+}
+
+func TestSelectRecentPollsEmpty(t *testing.T) {
+	mock, db, closeDbFunc := SetupMockDBPoll(t)
+	defer closeDbFunc()
+
+	SetupMockPollHistorySelect(mock, 10)
+
+	runs, err := db.SelectRecentPolls(10)
+	assert.NoError(t, err)
+	assert.Empty(t, runs)
+}
+
+func TestSelectRecentPollsMultiRow(t *testing.T) {
+	mock, db, closeDbFunc := SetupMockDBPoll(t)
+	defer closeDbFunc()
+
+	now := time.Now().Round(time.Millisecond)
+	first := types.PollRun{Id: "2", Source: "GitHub", StartedOn: now, Duration: 2 * time.Second, RowsScraped: 10}
+	second := types.PollRun{Id: "1", Source: "Jira", StartedOn: now.Add(-time.Hour), Duration: time.Second, RowsScraped: 3, Error: "timed out"}
+	SetupMockPollHistorySelect(mock, 5, first, second)
+
+	runs, err := db.SelectRecentPolls(5)
+	assert.NoError(t, err)
+	assert.Equal(t, []types.PollRun{first, second}, runs)
+}
+
+func TestSelectRecentPollsQueryError(t *testing.T) {
+	mock, db, closeDbFunc := SetupMockDBPoll(t)
+	defer closeDbFunc()
+
+	forcedError := errors.New("forced poll history select error")
+	mock.ExpectQuery(PollConvertSqlToDbMockExpect(sqlSelectRecentPollHistory)).
+		WithArgs(10).
+		WillReturnError(forcedError)
+
+	runs, err := db.SelectRecentPolls(10)
+	assert.Equal(t, forcedError, err)
+	assert.Empty(t, runs)
+}
@@ -0,0 +1,88 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package db
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+)
+
+// iso8601RepeatingInterval matches the repeating-interval form ISO-8601 defines as
+// "R[n]/<duration>", e.g. "R/PT15M" (repeat indefinitely) or "R5/PT1H" (repeat 5 times). The
+// optional start/end-date forms ("R/2021-01-01/P1D") aren't supported - bbash only needs a
+// fixed cadence, not a bounded schedule.
+var iso8601RepeatingInterval = regexp.MustCompile(`^R(\d*)/(P.*)$`)
+
+// iso8601Duration matches an ISO-8601 duration, e.g. "P1DT2H30M". Y and (date-position) M
+// components are treated as approximate (365 and 30 days respectively); bbash's poll cadences
+// are all on the order of minutes to days, so that approximation never matters in practice.
+var iso8601Duration = regexp.MustCompile(`^P(?:(\d+)Y)?(?:(\d+)M)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+func parseISO8601Duration(s string) (d time.Duration, err error) {
+	m := iso8601Duration.FindStringSubmatch(s)
+	if m == nil || s == "P" {
+		return 0, fmt.Errorf("invalid ISO-8601 duration: %q", s)
+	}
+
+	component := func(s string, unit time.Duration) time.Duration {
+		if s == "" {
+			return 0
+		}
+		n, _ := strconv.Atoi(s)
+		return time.Duration(n) * unit
+	}
+
+	d += component(m[1], 365*24*time.Hour)
+	d += component(m[2], 30*24*time.Hour)
+	d += component(m[3], 24*time.Hour)
+	d += component(m[4], time.Hour)
+	d += component(m[5], time.Minute)
+	d += component(m[6], time.Second)
+	return d, nil
+}
+
+// ComputeNextRun returns the next time poll.Schedule should fire after now: a cron expression
+// (parsed with cron/v3's standard 5-field parser, as internal/campaign.Scheduler already uses)
+// or an ISO-8601 repeating interval (parsed by parseISO8601Duration). An empty Schedule is not
+// an error - it returns the zero time, meaning "no schedule configured".
+func ComputeNextRun(poll *types.Poll, now time.Time) (time.Time, error) {
+	if poll.Schedule == "" {
+		return time.Time{}, nil
+	}
+
+	if m := iso8601RepeatingInterval.FindStringSubmatch(poll.Schedule); m != nil {
+		d, err := parseISO8601Duration(m[2])
+		if err != nil {
+			return time.Time{}, err
+		}
+		return now.Add(d), nil
+	}
+
+	schedule, err := cron.ParseStandard(poll.Schedule)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid poll schedule %q: %w", poll.Schedule, err)
+	}
+	return schedule.Next(now), nil
+}
@@ -0,0 +1,62 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeNextRunEmptySchedule(t *testing.T) {
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	next, err := ComputeNextRun(&types.Poll{}, now)
+	assert.NoError(t, err)
+	assert.True(t, next.IsZero())
+}
+
+func TestComputeNextRunISO8601RepeatingInterval(t *testing.T) {
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	next, err := ComputeNextRun(&types.Poll{Schedule: "R/PT15M"}, now)
+	assert.NoError(t, err)
+	assert.Equal(t, now.Add(15*time.Minute), next)
+}
+
+func TestComputeNextRunISO8601RepeatingIntervalWithCount(t *testing.T) {
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	next, err := ComputeNextRun(&types.Poll{Schedule: "R5/PT1H"}, now)
+	assert.NoError(t, err)
+	assert.Equal(t, now.Add(time.Hour), next)
+}
+
+func TestComputeNextRunCron(t *testing.T) {
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	next, err := ComputeNextRun(&types.Poll{Schedule: "*/15 * * * *"}, now)
+	assert.NoError(t, err)
+	assert.Equal(t, now.Add(15*time.Minute), next)
+}
+
+func TestComputeNextRunInvalid(t *testing.T) {
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	_, err := ComputeNextRun(&types.Poll{Schedule: "not-a-schedule"}, now)
+	assert.Error(t, err)
+}
@@ -0,0 +1,84 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package db
+
+import (
+	"database/sql"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// BackendFactory opens an IBBashDB against dsn for one Dialect, the same role nosql.Open's
+// per-driver constructors play in step-ca's NoSQL abstraction: Open resolves BBASH_DB_DRIVER down
+// to a Dialect, then dispatches to the BackendFactory registered for it.
+type BackendFactory func(dsn string, logger *zap.Logger) (IBBashDB, error)
+
+// backends holds the BackendFactory registered for each Dialect this build can actually open.
+// Only DialectPostgres has one registered (below) - DialectMySQL and DialectSQLite are named by
+// Dialect/ResolveDialect as this abstraction's extension points, but RegisterBackend for them
+// hasn't happened because their database/sql drivers aren't vendored in this build (see the
+// driversAvailable comment on dialects).
+var backends = map[string]BackendFactory{}
+
+// RegisterBackend registers factory as the BackendFactory for dialectName (one of the Dialect*
+// constants), so Open(dialectName, ...) can dispatch to it. Called from this package's own init
+// for DialectPostgres; a future build adding a MySQL or SQLite driver would call it the same way
+// from its own init, alongside registering the dialect's driversAvailable entry.
+func RegisterBackend(dialectName string, factory BackendFactory) {
+	backends[dialectName] = factory
+}
+
+func init() {
+	RegisterBackend(DialectPostgres, func(dsn string, logger *zap.Logger) (IBBashDB, error) {
+		conn, err := sql.Open("postgres", dsn)
+		if err != nil {
+			return nil, err
+		}
+		if err = conn.Ping(); err != nil {
+			return nil, err
+		}
+
+		dialect, err := ResolveDialect(DialectPostgres)
+		if err != nil {
+			return nil, err
+		}
+		return NewWithDialect(conn, logger, dialect), nil
+	})
+}
+
+// Open resolves driverName (one of the Dialect* constants, or "" for DialectPostgres) to a
+// Dialect and dispatches to its registered BackendFactory, opening and pinging dsn. It errors the
+// same way ResolveDialect does for an unknown or not-yet-available Dialect, and additionally if
+// that Dialect has no BackendFactory registered (a gap RegisterBackend's own doc comment
+// explains).
+func Open(driverName, dsn string, logger *zap.Logger) (bbashDB IBBashDB, err error) {
+	dialect, err := ResolveDialect(driverName)
+	if err != nil {
+		return
+	}
+
+	factory, ok := backends[dialect.Name]
+	if !ok {
+		err = fmt.Errorf("%s %q has no registered backend", EnvDBDriver, dialect.Name)
+		return
+	}
+	return factory(dsn, logger)
+}
@@ -0,0 +1,64 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestOpenUnknownDriverName(t *testing.T) {
+	_, err := Open("oracle", "", nil)
+	assert.Error(t, err)
+}
+
+func TestOpenMySQLNotAvailableInThisBuild(t *testing.T) {
+	_, err := Open(DialectMySQL, "", nil)
+	assert.Error(t, err)
+}
+
+func TestOpenSQLiteNotAvailableInThisBuild(t *testing.T) {
+	_, err := Open(DialectSQLite, "", nil)
+	assert.Error(t, err)
+}
+
+func TestOpenPostgresBadDSN(t *testing.T) {
+	// No live Postgres in this build's test environment, so the factory's sql.Open/Ping must
+	// surface the connection failure rather than panic or return a usable IBBashDB.
+	_, err := Open(DialectPostgres, "host=127.0.0.1 port=1 connect_timeout=1", nil)
+	assert.Error(t, err)
+}
+
+func TestRegisterBackendOverridesExistingFactory(t *testing.T) {
+	original := backends[DialectPostgres]
+	defer RegisterBackend(DialectPostgres, original)
+
+	called := false
+	RegisterBackend(DialectPostgres, func(dsn string, logger *zap.Logger) (IBBashDB, error) {
+		called = true
+		return nil, nil
+	})
+
+	_, err := Open(DialectPostgres, "", nil)
+	assert.NoError(t, err)
+	assert.True(t, called)
+}
@@ -0,0 +1,74 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package db
+
+import (
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+)
+
+// sqlSelectScoringEventsForParticipant lists every non-revoked scoring_event row credited to
+// loginName, excluding the same scoring_event_revocations rows sqlScoreQuery/
+// sqlSumNonRevokedScoringEvents already exclude - a revoked event shouldn't show up in a
+// participant's activity feed any more than it counts toward their Score.
+const sqlSelectScoringEventsForParticipant = `SELECT se.repoOwner, se.repoName, se.pr, se.username, se.points
+			FROM scoring_event se
+			WHERE se.fk_campaign = (SELECT id FROM campaign WHERE name = $1)
+			    AND se.fk_scp = (SELECT id FROM source_control_provider WHERE name = $2)
+			    AND se.username = $3
+			    AND NOT EXISTS (
+			        SELECT 1 FROM scoring_event_revocations r
+			        WHERE r.fk_campaign = se.fk_campaign AND r.fk_scp = se.fk_scp
+			            AND r.repoOwner = se.repoOwner AND r.repoName = se.repoName AND r.pr = se.pr)`
+
+// SelectScoringEventsForParticipant returns loginName's non-revoked scoring_event rows for a
+// participant activity feed.
+//
+// This deliberately doesn't take a since/until time.Time range: sqlInsertScoringEvent's columns
+// (see db.go) are fk_campaign, fk_scp, repoOwner, repoName, pr, username, points - there's no
+// per-event timestamp to range over, the same gap EvaluateScoringPolicy's doc comment documents
+// for MaxEventsPerHour/MaxPointsPerDay/MinSecondsBetweenEvents. A SelectScoreTimeSeries bucketed by
+// date_trunc, and an EventKind column migration to distinguish score_added/score_reverted/
+// manual_adjustment rows, would both need that same timestamp; adding one is a migration this
+// checkout has no internal/db/migrations directory to carry (see MigrateDB), so neither is added
+// here.
+//
+// A compensating-negative-event ReverseScoringEvent(eventID, reason) isn't added either: this
+// table has no synthetic event id to address a row by (its primary key is the composite natural
+// key fk_campaign/fk_scp/repoOwner/repoName/pr sqlInsertScoringEvent upserts on), and "undo without
+// delete" for a scoring_event row is already RevokeScoringEvent's job - it records the undo in
+// scoring_event_revocations rather than inserting a second scoring_event for the same PR, which the
+// ON CONFLICT DO UPDATE upsert above wouldn't represent as a distinct row anyway.
+func (p *BBashDB) SelectScoringEventsForParticipant(campaignName, scpName, loginName string) (events []types.ScoringLedgerEntryStruct, err error) {
+	rows, err := p.db.Query(sqlSelectScoringEventsForParticipant, campaignName, scpName, loginName)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var event types.ScoringLedgerEntryStruct
+		if err = rows.Scan(&event.RepoOwner, &event.RepoName, &event.PullRequest, &event.TriggerUser, &event.Points); err != nil {
+			return
+		}
+		events = append(events, event)
+	}
+	err = rows.Err()
+	return
+}
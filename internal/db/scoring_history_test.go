@@ -0,0 +1,73 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package db
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectScoringEventsForParticipant(t *testing.T) {
+	mock, db, closeDbFunc := SetupMockDB(t)
+	defer closeDbFunc()
+
+	mock.ExpectQuery(convertSqlToDbMockExpect(sqlSelectScoringEventsForParticipant)).
+		WithArgs(campaignName, "scpName", loginName).
+		WillReturnRows(sqlmock.NewRows([]string{"repoOwner", "repoName", "pr", "username", "points"}).
+			AddRow(TestOrgValid, "testRepoName", 1, loginName, 5.0).
+			AddRow(TestOrgValid, "testRepoName", 2, loginName, 3.0))
+
+	events, err := db.SelectScoringEventsForParticipant(campaignName, "scpName", loginName)
+	assert.NoError(t, err)
+	assert.Len(t, events, 2)
+	assert.Equal(t, 5.0, events[0].Points)
+	assert.Equal(t, 2, events[1].PullRequest)
+}
+
+func TestSelectScoringEventsForParticipantQueryError(t *testing.T) {
+	mock, db, closeDbFunc := SetupMockDB(t)
+	defer closeDbFunc()
+
+	forcedError := fmt.Errorf("forced select scoring events for participant error")
+	mock.ExpectQuery(convertSqlToDbMockExpect(sqlSelectScoringEventsForParticipant)).
+		WithArgs(campaignName, "scpName", loginName).
+		WillReturnError(forcedError)
+
+	events, err := db.SelectScoringEventsForParticipant(campaignName, "scpName", loginName)
+	assert.EqualError(t, err, forcedError.Error())
+	assert.Nil(t, events)
+}
+
+func TestSelectScoringEventsForParticipantScanError(t *testing.T) {
+	mock, db, closeDbFunc := SetupMockDB(t)
+	defer closeDbFunc()
+
+	mock.ExpectQuery(convertSqlToDbMockExpect(sqlSelectScoringEventsForParticipant)).
+		WithArgs(campaignName, "scpName", loginName).
+		WillReturnRows(sqlmock.NewRows([]string{"repoOwner", "repoName", "pr", "username", "points"}).
+			AddRow(TestOrgValid, "testRepoName", "not-a-pr-number", loginName, 5.0))
+
+	events, err := db.SelectScoringEventsForParticipant(campaignName, "scpName", loginName)
+	assert.Error(t, err)
+	assert.Nil(t, events)
+}
@@ -0,0 +1,143 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package db
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+	"go.uber.org/zap"
+)
+
+// auditActionScoringRejected is the audit_events Action EvaluateScoringPolicy records for a
+// rejected event - kept as a plain string the same way auditActionCampaignCreate duplicates
+// internal/auditlog.ActionScoringRejected instead of cross-importing.
+const auditActionScoringRejected = "scoring.rejected"
+
+const sqlSelectScoringPolicy = `SELECT max_events_per_hour, max_points_per_day, min_seconds_between_events, require_distinct_repo
+		FROM scoring_policy
+		WHERE fk_campaign = (SELECT id FROM campaign WHERE name = $1)`
+
+// GetScoringPolicy returns campaignName's scoring_policy row. found is false, with a zero-value
+// policy, when the campaign has no row at all - the same "absent means unrestricted" convention
+// EvaluateScoringPolicy relies on.
+func (p *BBashDB) GetScoringPolicy(campaignName string) (policy types.ScoringPolicyStruct, found bool, err error) {
+	policy.CampaignName = campaignName
+	row := p.db.QueryRow(sqlSelectScoringPolicy, campaignName)
+	if err = row.Scan(&policy.MaxEventsPerHour, &policy.MaxPointsPerDay, &policy.MinSecondsBetweenEvents, &policy.RequireDistinctRepo); err != nil {
+		if err == sql.ErrNoRows {
+			err = nil
+			policy = types.ScoringPolicyStruct{CampaignName: campaignName}
+		}
+		return
+	}
+	found = true
+	return
+}
+
+const sqlExistingScoringEventInOtherRepo = `SELECT EXISTS (
+		SELECT 1 FROM scoring_event se
+		INNER JOIN campaign c ON se.fk_campaign = c.id
+		INNER JOIN source_control_provider scp ON se.fk_scp = scp.id
+		WHERE c.name = $1 AND scp.name = $2 AND se.username = $3
+			AND se.repoOwner = $4 AND se.repoName = $5 AND se.pr != $6
+	)`
+
+// EvaluateScoringPolicy checks msg against campaignName's scoring_policy (see GetScoringPolicy)
+// before a caller applies it with ApplyScoringEvent, and audit-logs any rejection so an admin can
+// review it later - see internal/auditlog.ActionScoringRejected. allowed is true and reason is
+// empty for a campaign with no policy row.
+//
+// Only RequireDistinctRepo is actually enforced here: rejecting a participant's second scored PR
+// in a repo they've already scored in (under a distinct pr number, so editing the same PR still
+// upserts via sqlInsertScoringEvent's ON CONFLICT as before) is checkable against scoring_event as
+// it exists today. MaxEventsPerHour, MaxPointsPerDay and MinSecondsBetweenEvents can't be enforced
+// without a per-event timestamp on scoring_event, which this schema snapshot doesn't have and
+// which adding would require a migration this repo checkout has no migrations directory to carry
+// (see MigrateDB) - they're stored on ScoringPolicyStruct and read here so a future migration can
+// wire them up without another schema/table change.
+func (p *BBashDB) EvaluateScoringPolicy(participantToScore *types.ParticipantStruct, msg *types.ScoringMessage) (allowed bool, reason string, err error) {
+	policy, found, err := p.GetScoringPolicy(participantToScore.CampaignName)
+	if err != nil || !found {
+		allowed = true
+		return
+	}
+
+	allowed = true
+	if policy.RequireDistinctRepo {
+		var alreadyScoredOtherPR bool
+		if err = p.db.QueryRow(sqlExistingScoringEventInOtherRepo,
+			participantToScore.CampaignName, participantToScore.ScpName, msg.TriggerUser,
+			msg.RepoOwner, msg.RepoName, msg.PullRequest).Scan(&alreadyScoredOtherPR); err != nil {
+			return
+		}
+		if alreadyScoredOtherPR {
+			allowed = false
+			reason = "participant has already scored a pull request in this repository; campaign policy requires distinct repositories"
+		}
+	}
+
+	if !allowed {
+		tx, txErr := p.db.Begin()
+		if txErr != nil {
+			p.logger.Error("error starting transaction to audit rejected scoring event", zap.Error(txErr))
+			return
+		}
+		if auditErr := p.insertAuditEventTx(tx, types.AuditEventStruct{
+			Actor:        msg.TriggerUser,
+			Action:       auditActionScoringRejected,
+			CampaignName: participantToScore.CampaignName,
+			ScpName:      participantToScore.ScpName,
+			LoginName:    msg.TriggerUser,
+			Success:      false,
+			Error:        reason,
+			OccurredOn:   time.Now(),
+		}); auditErr != nil {
+			p.logger.Error("error inserting scoring.rejected audit event", zap.String("reason", reason), zap.Error(auditErr))
+			_ = tx.Rollback()
+			return
+		}
+		if commitErr := tx.Commit(); commitErr != nil {
+			p.logger.Error("error committing scoring.rejected audit event", zap.Error(commitErr))
+		}
+	}
+	return
+}
+
+const sqlInsertScoringEventWithHash = `INSERT INTO scoring_event
+			(fk_campaign, fk_scp, repoOwner, repoName, pr, username, points, commit_sha, diff_hash)
+			VALUES ((SELECT id FROM campaign WHERE name = $1),
+			        (SELECT id FROM source_control_provider WHERE name = $2),
+			        $3, $4, $5, $6, $7, $8, $9)
+			ON CONFLICT (fk_campaign, fk_scp, repoOwner, repoName, pr) DO
+				UPDATE SET points = $7, commit_sha = $8, diff_hash = $9`
+
+// InsertScoringEventWithHash is InsertScoringEvent plus commitSHA and diffHash, so a copy-paste
+// attack - the same fix submitted as PRs against multiple repos - can be rejected at the database
+// level: diff_hash needs a unique constraint in the scoring_event table definition for that (see
+// the package-level migration caveat on EvaluateScoringPolicy; this repo checkout has no
+// migrations directory to add one to), so today this only records the hash for an admin to query
+// against directly rather than having inserts fail on a duplicate.
+func (p *BBashDB) InsertScoringEventWithHash(participantToScore *types.ParticipantStruct, msg *types.ScoringMessage, newPoints float64, commitSHA, diffHash string) (err error) {
+	_, err = p.db.Exec(sqlInsertScoringEventWithHash,
+		participantToScore.CampaignName, participantToScore.ScpName, msg.RepoOwner, msg.RepoName, msg.PullRequest, msg.TriggerUser, newPoints, commitSHA, diffHash)
+	return
+}
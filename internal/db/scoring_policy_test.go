@@ -0,0 +1,170 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package db
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetScoringPolicyNoRow(t *testing.T) {
+	mock, db, closeDbFunc := SetupMockDB(t)
+	defer closeDbFunc()
+
+	mock.ExpectQuery(convertSqlToDbMockExpect(sqlSelectScoringPolicy)).
+		WithArgs(campaignName).
+		WillReturnRows(sqlmock.NewRows([]string{"max_events_per_hour", "max_points_per_day", "min_seconds_between_events", "require_distinct_repo"}))
+
+	policy, found, err := db.GetScoringPolicy(campaignName)
+	assert.NoError(t, err)
+	assert.False(t, found)
+	assert.Equal(t, types.ScoringPolicyStruct{CampaignName: campaignName}, policy)
+}
+
+func TestGetScoringPolicyError(t *testing.T) {
+	mock, db, closeDbFunc := SetupMockDB(t)
+	defer closeDbFunc()
+
+	forcedError := fmt.Errorf("forced select scoring policy error")
+	mock.ExpectQuery(convertSqlToDbMockExpect(sqlSelectScoringPolicy)).
+		WithArgs(campaignName).
+		WillReturnError(forcedError)
+
+	_, found, err := db.GetScoringPolicy(campaignName)
+	assert.EqualError(t, err, forcedError.Error())
+	assert.False(t, found)
+}
+
+func TestGetScoringPolicy(t *testing.T) {
+	mock, db, closeDbFunc := SetupMockDB(t)
+	defer closeDbFunc()
+
+	mock.ExpectQuery(convertSqlToDbMockExpect(sqlSelectScoringPolicy)).
+		WithArgs(campaignName).
+		WillReturnRows(sqlmock.NewRows([]string{"max_events_per_hour", "max_points_per_day", "min_seconds_between_events", "require_distinct_repo"}).
+			AddRow(10, 100, 60, true))
+
+	policy, found, err := db.GetScoringPolicy(campaignName)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, types.ScoringPolicyStruct{
+		CampaignName:            campaignName,
+		MaxEventsPerHour:        10,
+		MaxPointsPerDay:         100,
+		MinSecondsBetweenEvents: 60,
+		RequireDistinctRepo:     true,
+	}, policy)
+}
+
+func TestEvaluateScoringPolicyNoPolicy(t *testing.T) {
+	mock, db, closeDbFunc := SetupMockDB(t)
+	defer closeDbFunc()
+
+	testParticipant := &types.ParticipantStruct{CampaignName: campaignName, ScpName: "scpName"}
+	msg := &types.ScoringMessage{RepoOwner: TestOrgValid, RepoName: "testRepoName", TriggerUser: loginName, PullRequest: 1}
+
+	mock.ExpectQuery(convertSqlToDbMockExpect(sqlSelectScoringPolicy)).
+		WithArgs(campaignName).
+		WillReturnRows(sqlmock.NewRows([]string{"max_events_per_hour", "max_points_per_day", "min_seconds_between_events", "require_distinct_repo"}))
+
+	allowed, reason, err := db.EvaluateScoringPolicy(testParticipant, msg)
+	assert.NoError(t, err)
+	assert.True(t, allowed)
+	assert.Equal(t, "", reason)
+}
+
+func TestEvaluateScoringPolicyRequireDistinctRepoAllowed(t *testing.T) {
+	mock, db, closeDbFunc := SetupMockDB(t)
+	defer closeDbFunc()
+
+	testParticipant := &types.ParticipantStruct{CampaignName: campaignName, ScpName: "scpName"}
+	msg := &types.ScoringMessage{RepoOwner: TestOrgValid, RepoName: "testRepoName", TriggerUser: loginName, PullRequest: 1}
+
+	mock.ExpectQuery(convertSqlToDbMockExpect(sqlSelectScoringPolicy)).
+		WithArgs(campaignName).
+		WillReturnRows(sqlmock.NewRows([]string{"max_events_per_hour", "max_points_per_day", "min_seconds_between_events", "require_distinct_repo"}).
+			AddRow(0, 0, 0, true))
+	mock.ExpectQuery(convertSqlToDbMockExpect(sqlExistingScoringEventInOtherRepo)).
+		WithArgs(campaignName, testParticipant.ScpName, msg.TriggerUser, msg.RepoOwner, msg.RepoName, msg.PullRequest).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+	allowed, reason, err := db.EvaluateScoringPolicy(testParticipant, msg)
+	assert.NoError(t, err)
+	assert.True(t, allowed)
+	assert.Equal(t, "", reason)
+}
+
+func TestEvaluateScoringPolicyRequireDistinctRepoRejected(t *testing.T) {
+	mock, db, closeDbFunc := SetupMockDB(t)
+	defer closeDbFunc()
+
+	testParticipant := &types.ParticipantStruct{CampaignName: campaignName, ScpName: "scpName"}
+	msg := &types.ScoringMessage{RepoOwner: TestOrgValid, RepoName: "testRepoName", TriggerUser: loginName, PullRequest: 2}
+
+	mock.ExpectQuery(convertSqlToDbMockExpect(sqlSelectScoringPolicy)).
+		WithArgs(campaignName).
+		WillReturnRows(sqlmock.NewRows([]string{"max_events_per_hour", "max_points_per_day", "min_seconds_between_events", "require_distinct_repo"}).
+			AddRow(0, 0, 0, true))
+	mock.ExpectQuery(convertSqlToDbMockExpect(sqlExistingScoringEventInOtherRepo)).
+		WithArgs(campaignName, testParticipant.ScpName, msg.TriggerUser, msg.RepoOwner, msg.RepoName, msg.PullRequest).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectBegin()
+	mock.ExpectQuery(convertSqlToDbMockExpect(sqlInsertAuditEvent)).
+		WillReturnRows(sqlmock.NewRows([]string{"guid"}).AddRow(testAuditEventGuid))
+	mock.ExpectCommit()
+
+	allowed, reason, err := db.EvaluateScoringPolicy(testParticipant, msg)
+	assert.NoError(t, err)
+	assert.False(t, allowed)
+	assert.NotEqual(t, "", reason)
+}
+
+func TestInsertScoringEventWithHashError(t *testing.T) {
+	mock, db, closeDbFunc := SetupMockDB(t)
+	defer closeDbFunc()
+
+	testParticipant := &types.ParticipantStruct{CampaignName: campaignName, ScpName: "scpName"}
+	msg := &types.ScoringMessage{RepoOwner: TestOrgValid, RepoName: "testRepoName", TriggerUser: loginName, PullRequest: 1}
+
+	forcedError := fmt.Errorf("forced insert scoring event with hash error")
+	mock.ExpectExec(convertSqlToDbMockExpect(sqlInsertScoringEventWithHash)).
+		WithArgs(testParticipant.CampaignName, testParticipant.ScpName, msg.RepoOwner, msg.RepoName, msg.PullRequest, msg.TriggerUser, float64(11), "commitSHA", "diffHash").
+		WillReturnError(forcedError)
+
+	assert.EqualError(t, db.InsertScoringEventWithHash(testParticipant, msg, 11, "commitSHA", "diffHash"), forcedError.Error())
+}
+
+func TestInsertScoringEventWithHash(t *testing.T) {
+	mock, db, closeDbFunc := SetupMockDB(t)
+	defer closeDbFunc()
+
+	testParticipant := &types.ParticipantStruct{CampaignName: campaignName, ScpName: "scpName"}
+	msg := &types.ScoringMessage{RepoOwner: TestOrgValid, RepoName: "testRepoName", TriggerUser: loginName, PullRequest: 1}
+
+	mock.ExpectExec(convertSqlToDbMockExpect(sqlInsertScoringEventWithHash)).
+		WithArgs(testParticipant.CampaignName, testParticipant.ScpName, msg.RepoOwner, msg.RepoName, msg.PullRequest, msg.TriggerUser, float64(11), "commitSHA", "diffHash").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, db.InsertScoringEventWithHash(testParticipant, msg, 11, "commitSHA", "diffHash"))
+}
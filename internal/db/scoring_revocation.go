@@ -0,0 +1,192 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+	"go.uber.org/zap"
+)
+
+// ErrScoringEventAlreadyRevoked is RevokeScoringEvent's error when the scoring_event it was asked
+// to revoke already has a scoring_event_revocations row - a second admin revoking the same event
+// (or a retried request) is rejected outright rather than silently overwriting the first
+// revocation's reason/actor.
+var ErrScoringEventAlreadyRevoked = fmt.Errorf("scoring event was already revoked")
+
+// auditActionScoringEventRevoked is the audit_events Action RevokeScoringEvent records - kept as a
+// plain string the same way auditActionCampaignCreate/auditActionScoringRejected do.
+const auditActionScoringEventRevoked = "scoring.revoked"
+
+// sqlInsertScoringEventRevocation records that the scoring_event row identified by the same
+// (fk_campaign, fk_scp, repoOwner, repoName, pr) natural key sqlInsertScoringEvent/sqlScoreQuery
+// already use should no longer count toward a participant's score, without deleting that row -
+// ReplayParticipantScore still needs it to recompute history. ON CONFLICT DO NOTHING is how
+// RevokeScoringEvent tells a fresh revocation from a repeat of one already recorded: zero rows
+// affected means this event was already revoked.
+//
+// This checkout has no internal/db/migrations directory to carry a CREATE TABLE for
+// scoring_event_revocations (see db.go's MigrateDB/MigrateUp and their doc comments) - the table is
+// assumed to exist the same way every other table this file queries (campaign, scoring_event, ...)
+// is: via a migration a real deployment's migrations directory would carry.
+const sqlInsertScoringEventRevocation = `INSERT INTO scoring_event_revocations
+			(fk_campaign, fk_scp, repoOwner, repoName, pr, reason, actor, revoked_on)
+			VALUES ((SELECT id FROM campaign WHERE name = $1),
+			        (SELECT id FROM source_control_provider WHERE name = $2),
+			        $3, $4, $5, $6, $7, $8)
+			ON CONFLICT (fk_campaign, fk_scp, repoOwner, repoName, pr) DO NOTHING`
+
+const sqlIsScoringEventRevoked = `SELECT EXISTS(
+			SELECT 1 FROM scoring_event_revocations
+			WHERE fk_campaign = (SELECT id FROM campaign WHERE name = $1)
+			    AND fk_scp = (SELECT id FROM source_control_provider WHERE name = $2)
+			    AND repoOwner = $3 AND repoName = $4 AND pr = $5)`
+
+// RevokeScoringEvent marks the scoring_event identified by campaignName/scpName/repoOwner/repoName/pr
+// as no longer counting toward a participant's score - e.g. a bot-generated PR mistakenly credited
+// as a bug fix - so admins can undo an accidental bounty without deleting history. Records an
+// audit_events row the same way InsertCampaign does (see insertAuditEventTx), attributing the
+// revocation to actor. Returns ErrScoringEventAlreadyRevoked if this event already has a
+// revocation row.
+func (p *BBashDB) RevokeScoringEvent(campaignName, scpName, repoOwner, repoName string, pr int, reason, actor string) (err error) {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				p.logger.Error("error rolling back scoring event revocation", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	var result sql.Result
+	if result, err = tx.Exec(sqlInsertScoringEventRevocation, campaignName, scpName, repoOwner, repoName, pr, reason, actor, time.Now()); err != nil {
+		return
+	}
+
+	var rowsAffected int64
+	if rowsAffected, err = result.RowsAffected(); err != nil {
+		return
+	}
+	if rowsAffected == 0 {
+		err = ErrScoringEventAlreadyRevoked
+		return
+	}
+
+	if err = p.insertAuditEventTx(tx, types.AuditEventStruct{
+		Actor:        actor,
+		Action:       auditActionScoringEventRevoked,
+		CampaignName: campaignName,
+		ScpName:      scpName,
+		Error:        reason,
+		Success:      true,
+		OccurredOn:   time.Now(),
+	}); err != nil {
+		return
+	}
+
+	err = tx.Commit()
+	return
+}
+
+// IsScoringEventRevoked reports whether the scoring_event identified by
+// campaignName/scpName/repoOwner/repoName/pr has a scoring_event_revocations row - SelectPriorScore
+// consults this so a revoked event is treated as never having been scored, letting a corrected
+// redelivery of the same PR be credited fresh instead of being compared against (and possibly
+// vetoed by) the revoked points.
+func (p *BBashDB) IsScoringEventRevoked(campaignName, scpName, repoOwner, repoName string, pr int) (revoked bool, err error) {
+	err = p.db.QueryRow(sqlIsScoringEventRevoked, campaignName, scpName, repoOwner, repoName, pr).Scan(&revoked)
+	return
+}
+
+// sqlSumNonRevokedScoringEvents recomputes a participant's total from every scoring_event row
+// attributed to them that ReplayParticipantScore should still count - i.e. every row without a
+// matching scoring_event_revocations entry.
+const sqlSumNonRevokedScoringEvents = `SELECT COALESCE(SUM(se.points), 0)
+			FROM scoring_event se
+			WHERE se.fk_campaign = (SELECT id FROM campaign WHERE name = $1)
+			    AND se.fk_scp = (SELECT id FROM source_control_provider WHERE name = $2)
+			    AND se.username = $3
+			    AND NOT EXISTS (
+			        SELECT 1 FROM scoring_event_revocations r
+			        WHERE r.fk_campaign = se.fk_campaign AND r.fk_scp = se.fk_scp
+			            AND r.repoOwner = se.repoOwner AND r.repoName = se.repoName AND r.pr = se.pr)`
+
+const sqlSetParticipantScore = `UPDATE participant SET Score = $1 WHERE id = $2 RETURNING Score`
+
+// ReplayParticipantScore recomputes participant's Score from the scoring_event rows
+// RevokeScoringEvent hasn't excluded, and writes the result back - the read path an admin's
+// RevokeScoringEvent call needs to actually take effect on the leaderboard, since revoking an event
+// doesn't by itself touch participant.Score. Unlike UpdateParticipantScoreCAS this isn't a delta
+// applied against a racing webhook delivery; it's an authoritative admin-triggered recompute, so it
+// sets Score directly rather than compare-and-swapping it. Enqueues the same upstream_outbox/
+// event_outbox rows UpdateParticipantScore does, so the recomputed score still reaches upstream and
+// any event subscriber.
+func (p *BBashDB) ReplayParticipantScore(participant *types.ParticipantStruct) (score int, err error) {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				p.logger.Error("error rolling back participant score replay", zap.Error(rollbackErr))
+			}
+		}
+	}()
+
+	var recomputed float64
+	if err = tx.QueryRow(sqlSumNonRevokedScoringEvents, participant.CampaignName, participant.ScpName, participant.LoginName).Scan(&recomputed); err != nil {
+		return
+	}
+
+	if err = tx.QueryRow(sqlSetParticipantScore, recomputed, participant.ID).Scan(&score); err != nil {
+		return
+	}
+
+	var payload []byte
+	if payload, err = json.Marshal(outboxScorePayload{ParticipantUpstreamId: participant.UpstreamId, CampaignName: participant.CampaignName, Score: score}); err != nil {
+		return
+	}
+
+	if _, err = tx.Exec(sqlInsertOutboxEntry, outboxOpUpdateScore, participant.ID, payload, time.Now()); err != nil {
+		p.logger.Error("error enqueueing score outbox entry", zap.Any("participant", participant), zap.Error(err))
+		return
+	}
+
+	scored := *participant
+	scored.Score = score
+	if err = p.enqueueEvent(tx, eventTypeParticipantScoreUpdated, scored); err != nil {
+		p.logger.Error("error enqueueing participant.score_updated event", zap.Any("participant", participant), zap.Error(err))
+		return
+	}
+
+	if err = tx.Commit(); err != nil {
+		return
+	}
+	participant.Score = score
+	return
+}
@@ -0,0 +1,151 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package db
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRevokeScoringEvent(t *testing.T) {
+	mock, db, closeDbFunc := SetupMockDB(t)
+	defer closeDbFunc()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(convertSqlToDbMockExpect(sqlInsertScoringEventRevocation)).
+		WithArgs(campaignName, "scpName", TestOrgValid, "testRepoName", 1, "bot-generated PR", "actorName", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(convertSqlToDbMockExpect(sqlInsertAuditEvent)).
+		WillReturnRows(sqlmock.NewRows([]string{"guid"}).AddRow(testAuditEventGuid))
+	mock.ExpectCommit()
+
+	assert.NoError(t, db.RevokeScoringEvent(campaignName, "scpName", TestOrgValid, "testRepoName", 1, "bot-generated PR", "actorName"))
+}
+
+func TestRevokeScoringEventAlreadyRevoked(t *testing.T) {
+	mock, db, closeDbFunc := SetupMockDB(t)
+	defer closeDbFunc()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(convertSqlToDbMockExpect(sqlInsertScoringEventRevocation)).
+		WithArgs(campaignName, "scpName", TestOrgValid, "testRepoName", 1, "bot-generated PR", "actorName", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	assert.Equal(t, ErrScoringEventAlreadyRevoked,
+		db.RevokeScoringEvent(campaignName, "scpName", TestOrgValid, "testRepoName", 1, "bot-generated PR", "actorName"))
+}
+
+func TestRevokeScoringEventInsertError(t *testing.T) {
+	mock, db, closeDbFunc := SetupMockDB(t)
+	defer closeDbFunc()
+
+	forcedError := fmt.Errorf("forced insert scoring event revocation error")
+	mock.ExpectBegin()
+	mock.ExpectExec(convertSqlToDbMockExpect(sqlInsertScoringEventRevocation)).
+		WithArgs(campaignName, "scpName", TestOrgValid, "testRepoName", 1, "bot-generated PR", "actorName", sqlmock.AnyArg()).
+		WillReturnError(forcedError)
+	mock.ExpectRollback()
+
+	assert.EqualError(t, db.RevokeScoringEvent(campaignName, "scpName", TestOrgValid, "testRepoName", 1, "bot-generated PR", "actorName"), forcedError.Error())
+}
+
+func TestIsScoringEventRevoked(t *testing.T) {
+	mock, db, closeDbFunc := SetupMockDB(t)
+	defer closeDbFunc()
+
+	mock.ExpectQuery(convertSqlToDbMockExpect(sqlIsScoringEventRevoked)).
+		WithArgs(campaignName, "scpName", TestOrgValid, "testRepoName", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	revoked, err := db.IsScoringEventRevoked(campaignName, "scpName", TestOrgValid, "testRepoName", 1)
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+}
+
+func TestIsScoringEventRevokedError(t *testing.T) {
+	mock, db, closeDbFunc := SetupMockDB(t)
+	defer closeDbFunc()
+
+	forcedError := fmt.Errorf("forced is scoring event revoked error")
+	mock.ExpectQuery(convertSqlToDbMockExpect(sqlIsScoringEventRevoked)).
+		WithArgs(campaignName, "scpName", TestOrgValid, "testRepoName", 1).
+		WillReturnError(forcedError)
+
+	revoked, err := db.IsScoringEventRevoked(campaignName, "scpName", TestOrgValid, "testRepoName", 1)
+	assert.EqualError(t, err, forcedError.Error())
+	assert.False(t, revoked)
+}
+
+// TestReplayParticipantScore covers recomputing a participant's total when some of their
+// scoring_event rows have been revoked and some haven't - sqlSumNonRevokedScoringEvents' NOT EXISTS
+// clause is what the mock stands in for here, rather than literal mixed rows, since sqlmock asserts
+// on the query text/args, not on executing the SQL against real data.
+func TestReplayParticipantScore(t *testing.T) {
+	mock, db, closeDbFunc := SetupMockDB(t)
+	defer closeDbFunc()
+
+	testParticipant := &types.ParticipantStruct{
+		ID:           testParticipantGuid,
+		CampaignName: campaignName,
+		ScpName:      "scpName",
+		LoginName:    loginName,
+		UpstreamId:   "upstreamId",
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(convertSqlToDbMockExpect(sqlSumNonRevokedScoringEvents)).
+		WithArgs(testParticipant.CampaignName, testParticipant.ScpName, testParticipant.LoginName).
+		WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(7))
+	mock.ExpectQuery(convertSqlToDbMockExpect(sqlSetParticipantScore)).
+		WithArgs(float64(7), testParticipant.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"score"}).AddRow(7))
+	mock.ExpectExec(convertSqlToDbMockExpect(sqlInsertOutboxEntry)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(convertSqlToDbMockExpect(sqlInsertEventEntry)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	score, err := db.ReplayParticipantScore(testParticipant)
+	assert.NoError(t, err)
+	assert.Equal(t, 7, score)
+	assert.Equal(t, 7, testParticipant.Score)
+}
+
+func TestReplayParticipantScoreSumError(t *testing.T) {
+	mock, db, closeDbFunc := SetupMockDB(t)
+	defer closeDbFunc()
+
+	testParticipant := &types.ParticipantStruct{ID: testParticipantGuid, CampaignName: campaignName, ScpName: "scpName", LoginName: loginName}
+
+	forcedError := fmt.Errorf("forced sum non revoked scoring events error")
+	mock.ExpectBegin()
+	mock.ExpectQuery(convertSqlToDbMockExpect(sqlSumNonRevokedScoringEvents)).
+		WithArgs(testParticipant.CampaignName, testParticipant.ScpName, testParticipant.LoginName).
+		WillReturnError(forcedError)
+	mock.ExpectRollback()
+
+	_, err := db.ReplayParticipantScore(testParticipant)
+	assert.EqualError(t, err, forcedError.Error())
+}
@@ -0,0 +1,85 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+// Package errs gives bbash's HTTP handlers one typed error to return instead of the mix this repo
+// used to have (a raw error propagated to Echo's default handler, a hand-built
+// c.String(status, ...) call, or echo.NewHTTPError): a *BBashError carries the Scope of bbash it
+// happened in, a Category that maps to an HTTP status, and a numeric Code identifying the
+// specific failure within that Scope/Category pair, so a caller can match on Code without parsing
+// Message. Middleware installs the Scope/Category/Code/Message mapping as Echo's HTTPErrorHandler.
+package errs
+
+import "fmt"
+
+// Scope names the part of bbash's domain an error occurred in.
+type Scope string
+
+const (
+	ScopeCampaign    Scope = "campaign"
+	ScopeParticipant Scope = "participant"
+	ScopeTeam        Scope = "team"
+	ScopeBug         Scope = "bug"
+	ScopeSCP         Scope = "scp"
+	ScopeOrg         Scope = "org"
+	ScopeScoring     Scope = "scoring"
+	ScopePoll        Scope = "poll"
+)
+
+// Category buckets an error by what went wrong, independent of Scope; Middleware maps each
+// Category to the HTTP status a caller should see (see Category.HTTPStatus).
+type Category string
+
+const (
+	CategoryInput    Category = "input"
+	CategoryDB       Category = "db"
+	CategoryAuth     Category = "auth"
+	CategoryNotFound Category = "not_found"
+	CategoryConflict Category = "conflict"
+)
+
+// BBashError is the error type bbash's handlers return instead of a raw error or
+// echo.NewHTTPError. Code is only required to be unique within a given (Scope, Category) pair -
+// it's meant for a caller to match on programmatically, with Message carrying the human-readable
+// detail.
+type BBashError struct {
+	Scope    Scope
+	Category Category
+	Code     int
+	Message  string
+	Cause    error
+}
+
+// New builds a BBashError with no wrapped Cause.
+func New(scope Scope, category Category, code int, message string) *BBashError {
+	return &BBashError{Scope: scope, Category: category, Code: code, Message: message}
+}
+
+// Wrap builds a BBashError reporting cause as its Message, and keeps cause available to
+// errors.Unwrap/errors.Is callers.
+func Wrap(scope Scope, category Category, code int, cause error) *BBashError {
+	return &BBashError{Scope: scope, Category: category, Code: code, Message: cause.Error(), Cause: cause}
+}
+
+func (e *BBashError) Error() string {
+	return fmt.Sprintf("%s/%s[%d]: %s", e.Scope, e.Category, e.Code, e.Message)
+}
+
+func (e *BBashError) Unwrap() error {
+	return e.Cause
+}
@@ -0,0 +1,56 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package errs
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewHasNoCause(t *testing.T) {
+	e := New(ScopeCampaign, CategoryInput, 1, "empty campaignName")
+	assert.Nil(t, e.Unwrap())
+	assert.Equal(t, "campaign/input[1]: empty campaignName", e.Error())
+}
+
+func TestWrapUnwrapsToCause(t *testing.T) {
+	cause := fmt.Errorf("connection refused")
+	e := Wrap(ScopeCampaign, CategoryDB, 2, cause)
+	assert.Equal(t, cause, e.Unwrap())
+	assert.True(t, errors.Is(e, cause))
+	assert.Equal(t, "campaign/db[2]: connection refused", e.Error())
+}
+
+func TestCategoryHTTPStatus(t *testing.T) {
+	tests := map[Category]int{
+		CategoryInput:     400,
+		CategoryAuth:      401,
+		CategoryNotFound:  404,
+		CategoryConflict:  409,
+		CategoryDB:        500,
+		Category("bogus"): 500,
+	}
+	for category, expected := range tests {
+		assert.Equal(t, expected, category.HTTPStatus(), "category: %s", category)
+	}
+}
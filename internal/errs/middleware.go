@@ -0,0 +1,107 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package errs
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+// HTTPStatus maps a Category to the HTTP status a *BBashError of that Category should produce.
+// Anything not listed here falls back to http.StatusInternalServerError in ErrorHandler.
+func (c Category) HTTPStatus() int {
+	switch c {
+	case CategoryInput:
+		return http.StatusBadRequest
+	case CategoryAuth:
+		return http.StatusUnauthorized
+	case CategoryNotFound:
+		return http.StatusNotFound
+	case CategoryConflict:
+		return http.StatusConflict
+	case CategoryDB:
+		return http.StatusInternalServerError
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// contentTypeProblemJSON is the media type RFC 7807 reserves for a problem details body.
+const contentTypeProblemJSON = "application/problem+json"
+
+// problemTypeBaseURI prefixes every problem's "type" member with bbash's own error taxonomy. RFC
+// 7807 (§3.1) doesn't require the URI to be dereferenceable, only stable and specific to the
+// problem type, so a bbash Category is specific enough.
+const problemTypeBaseURI = "https://github.com/sonatype-nexus-community/bbash/problems/"
+
+// problem is the RFC 7807 "problem details" object ErrorHandler writes for a *BBashError: Type,
+// Title, Status, Detail and Instance are the RFC's own members; Scope and Code are bbash-specific
+// extension members (RFC 7807 §3.2 permits these) letting a caller match on the exact failure
+// without parsing Detail.
+type problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail"`
+	Instance string `json:"instance"`
+	Scope    Scope  `json:"scope,omitempty"`
+	Code     int    `json:"code,omitempty"`
+}
+
+// ErrorHandler returns an echo.HTTPErrorHandler that renders a *BBashError (however deep it's
+// wrapped) as an application/problem+json body via its Category's HTTPStatus, and otherwise
+// delegates to fallback so routes that haven't been converted to this package yet (or
+// middleware/framework errors like 404 route misses) keep behaving exactly as they did under
+// Echo's default handler.
+func ErrorHandler(logger *zap.Logger, fallback echo.HTTPErrorHandler) echo.HTTPErrorHandler {
+	return func(err error, c echo.Context) {
+		var bbErr *BBashError
+		if !errors.As(err, &bbErr) {
+			fallback(err, c)
+			return
+		}
+
+		if c.Response().Committed {
+			return
+		}
+
+		status := bbErr.Category.HTTPStatus()
+		raw, marshalErr := json.Marshal(problem{
+			Type:     problemTypeBaseURI + string(bbErr.Category),
+			Title:    http.StatusText(status),
+			Status:   status,
+			Detail:   bbErr.Message,
+			Instance: c.Request().URL.Path,
+			Scope:    bbErr.Scope,
+			Code:     bbErr.Code,
+		})
+		if marshalErr != nil {
+			logger.Error("errs: error marshalling problem details", zap.Error(marshalErr))
+			return
+		}
+		if blobErr := c.Blob(status, contentTypeProblemJSON, raw); blobErr != nil {
+			logger.Error("errs: error writing error response", zap.Error(blobErr))
+		}
+	}
+}
@@ -0,0 +1,79 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package errs
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+)
+
+func setupErrorHandlerContext(path string) (c echo.Context, rec *httptest.ResponseRecorder) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, path, nil)
+	rec = httptest.NewRecorder()
+	c = e.NewContext(req, rec)
+	return
+}
+
+func TestErrorHandlerWritesProblemJSON(t *testing.T) {
+	c, rec := setupErrorHandlerContext("/campaign/myCampaign")
+	fallbackCalled := false
+
+	handler := ErrorHandler(zaptest.NewLogger(t), func(error, echo.Context) { fallbackCalled = true })
+	handler(New(ScopeCampaign, CategoryInput, 1, "empty campaignName"), c)
+
+	assert.False(t, fallbackCalled)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, contentTypeProblemJSON, rec.Header().Get(echo.HeaderContentType))
+	assert.JSONEq(t,
+		`{"type":"https://github.com/sonatype-nexus-community/bbash/problems/input",`+
+			`"title":"Bad Request","status":400,"detail":"empty campaignName",`+
+			`"instance":"/campaign/myCampaign","scope":"campaign","code":1}`,
+		rec.Body.String())
+}
+
+func TestErrorHandlerDelegatesNonBBashError(t *testing.T) {
+	c, _ := setupErrorHandlerContext("/campaign/myCampaign")
+	fallbackErr := fmt.Errorf("not a BBashError")
+	var delegatedTo error
+
+	handler := ErrorHandler(zaptest.NewLogger(t), func(err error, _ echo.Context) { delegatedTo = err })
+	handler(fallbackErr, c)
+
+	assert.Equal(t, fallbackErr, delegatedTo)
+}
+
+func TestErrorHandlerSkipsCommittedResponse(t *testing.T) {
+	c, rec := setupErrorHandlerContext("/campaign/myCampaign")
+	assert.NoError(t, c.String(http.StatusOK, "already written"))
+
+	handler := ErrorHandler(zaptest.NewLogger(t), func(error, echo.Context) {
+		t.Fatal("fallback should not be called for a committed response")
+	})
+	handler(New(ScopeCampaign, CategoryInput, 1, "empty campaignName"), c)
+
+	assert.Equal(t, "already written", rec.Body.String())
+}
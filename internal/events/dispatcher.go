@@ -0,0 +1,247 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+// Package events drains the event_outbox table db.BBashDB.InsertCampaign/UpdateCampaign/
+// UpdateCampaignState/InsertParticipant/UpdateParticipantScore/DeleteParticipant write to
+// transactionally, and fans each entry out to every registered SubscriptionStruct whose Events
+// list matches (or is empty), signing the delivered envelope with an HMAC-SHA256 header so a
+// subscriber can verify it actually came from this bbash instance. This is independent of
+// internal/outbox.Worker's upstream.Backend sync: third parties (a Slack bot, a dashboard, a
+// Discord notifier) integrate against these webhooks without ever touching the DB or Webflow.
+package events
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+	"go.uber.org/zap"
+)
+
+// Event type literals an EventEntryStruct.Type is set to; internal/db's eventType* constants must
+// be kept in sync with these - this package is the canonical definition consumers switch on.
+const (
+	TypeCampaignCreated         = "campaign.created"
+	TypeCampaignUpdated         = "campaign.updated"
+	TypeParticipantCreated      = "participant.created"
+	TypeParticipantScoreUpdated = "participant.score_updated"
+	TypeParticipantDeleted      = "participant.deleted"
+)
+
+// HeaderSignature carries the HMAC-SHA256 signature (see sign) of the exact bytes posted, in
+// "sha256=<hex>" form.
+const HeaderSignature = "X-BBash-Signature"
+
+// Envelope is the versioned JSON body posted to every matching subscriber.
+type Envelope struct {
+	Id         string          `json:"id"`
+	Type       string          `json:"type"`
+	OccurredAt time.Time       `json:"occurredAt"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// baseRetryDelay and maxRetryDelay bound the exponential backoff applied between failed dispatch
+// attempts for a single entry - the same shape as internal/outbox's identically-named constants,
+// since these attempts span process restarts and unreachable subscribers, not a single request.
+const baseRetryDelay = 30 * time.Second
+const maxRetryDelay = 30 * time.Minute
+
+// batchSize bounds how many due entries a single tick drains, so one overloaded tick can't starve
+// the rest of the Dispatcher's responsibilities - the same value internal/outbox.Worker uses.
+const batchSize = 50
+
+// Store is the event_outbox/subscription persistence Dispatcher needs, a subset of db.IBBashDB.
+type Store interface {
+	GetSubscriptions() (subscriptions []types.SubscriptionStruct, err error)
+	SelectDueEventEntries(now time.Time, limit int) (entries []types.EventEntryStruct, err error)
+	MarkEventEntryDone(id string) (err error)
+	RescheduleEventEntry(id string, attempts int, nextAttemptAt time.Time) (err error)
+	CountPendingEventEntries() (pending int, err error)
+}
+
+// Dispatcher periodically drains due event_outbox entries and fans each out to every matching
+// subscription, the same cron-scheduled shape as internal/outbox.Worker.
+type Dispatcher struct {
+	store  Store
+	client *http.Client
+	logger *zap.Logger
+	cron   *cron.Cron
+}
+
+// NewDispatcher builds a Dispatcher; call Start to begin running it.
+func NewDispatcher(store Store, logger *zap.Logger) *Dispatcher {
+	return &Dispatcher{store: store, client: &http.Client{Timeout: 10 * time.Second}, logger: logger}
+}
+
+// Start begins running a tick on the given cron spec (e.g. "@every 15s") until Stop is called.
+func (d *Dispatcher) Start(spec string) (err error) {
+	d.cron = cron.New()
+	if _, err = d.cron.AddFunc(spec, d.tick); err != nil {
+		return
+	}
+	d.cron.Start()
+	return
+}
+
+// Stop ends the cron loop and waits for any in-flight tick to finish, or for ctx to expire,
+// whichever comes first - the same shape as internal/outbox.Worker.Stop.
+func (d *Dispatcher) Stop(ctx context.Context) (err error) {
+	stopped := d.cron.Stop()
+	select {
+	case <-stopped.Done():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Counts reports how many entries are still pending, for the /admin/subscriptions endpoint.
+func (d *Dispatcher) Counts() (pending int, err error) {
+	return d.store.CountPendingEventEntries()
+}
+
+// tick drains every due entry and dispatches it.
+func (d *Dispatcher) tick() {
+	entries, err := d.store.SelectDueEventEntries(time.Now(), batchSize)
+	if err != nil {
+		d.logger.Error("event dispatcher: select due entries", zap.Error(err))
+		return
+	}
+
+	for _, entry := range entries {
+		d.publish(entry)
+	}
+}
+
+// matches reports whether sub wants entryType - an empty Events list means "everything".
+func matches(sub types.SubscriptionStruct, entryType string) bool {
+	if len(sub.Events) == 0 {
+		return true
+	}
+	for _, want := range sub.Events {
+		if want == entryType {
+			return true
+		}
+	}
+	return false
+}
+
+// publish delivers a single entry to every matching subscription, marking it done only once every
+// matching subscriber has accepted it - including when a crash left it enqueued and undelivered
+// after the DB transaction that created it already committed, since a restarted Dispatcher picks
+// it back up from SelectDueEventEntries exactly like any other due entry.
+func (d *Dispatcher) publish(entry types.EventEntryStruct) {
+	subscriptions, err := d.store.GetSubscriptions()
+	if err != nil {
+		d.logger.Error("event dispatcher: select subscriptions", zap.Error(err))
+		d.reschedule(entry, err)
+		return
+	}
+
+	body, err := json.Marshal(Envelope{
+		Id:         entry.Id,
+		Type:       entry.Type,
+		OccurredAt: entry.CreatedOn,
+		Data:       json.RawMessage(entry.Payload),
+	})
+	if err != nil {
+		d.logger.Error("event dispatcher: marshal envelope", zap.String("id", entry.Id), zap.Error(err))
+		d.reschedule(entry, err)
+		return
+	}
+
+	delivered := true
+	for _, subscription := range subscriptions {
+		if !matches(subscription, entry.Type) {
+			continue
+		}
+		if deliverErr := d.deliver(subscription, body); deliverErr != nil {
+			d.logger.Debug("event dispatcher: delivery failed",
+				zap.String("id", entry.Id), zap.String("url", subscription.URL), zap.Error(deliverErr))
+			delivered = false
+			err = deliverErr
+		}
+	}
+
+	if !delivered {
+		d.reschedule(entry, err)
+		return
+	}
+
+	if markErr := d.store.MarkEventEntryDone(entry.Id); markErr != nil {
+		d.logger.Error("event dispatcher: mark done", zap.String("id", entry.Id), zap.Error(markErr))
+	}
+}
+
+// deliver POSTs body to subscription.URL, signed with an X-BBash-Signature header so the receiver
+// can verify it, and reports an error on any non-2xx response.
+func (d *Dispatcher) deliver(subscription types.SubscriptionStruct, body []byte) (err error) {
+	req, err := http.NewRequest(http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderSignature, sign(subscription.Secret, body))
+
+	res, err := d.client.Do(req)
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		err = fmt.Errorf("subscriber rejected event. response status: %s", res.Status)
+	}
+	return
+}
+
+// sign returns the "sha256=<hex>" HMAC-SHA256 signature of body under secret, the value sent as
+// HeaderSignature.
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// reschedule records a failed dispatch attempt and pushes next_attempt_at out by an exponentially
+// growing delay, capped at maxRetryDelay so a long-broken subscriber still gets retried eventually.
+func (d *Dispatcher) reschedule(entry types.EventEntryStruct, cause error) {
+	attempts := entry.Attempts + 1
+	delay := baseRetryDelay << attempts
+	if delay <= 0 || delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+
+	d.logger.Info("event dispatcher: dispatch failed, rescheduling",
+		zap.String("id", entry.Id), zap.Int("attempts", attempts), zap.Duration("delay", delay), zap.Error(cause))
+
+	if err := d.store.RescheduleEventEntry(entry.Id, attempts, time.Now().Add(delay)); err != nil {
+		d.logger.Error("event dispatcher: reschedule", zap.String("id", entry.Id), zap.Error(err))
+	}
+}
@@ -0,0 +1,206 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package events
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+)
+
+type fakeStore struct {
+	subscriptions []types.SubscriptionStruct
+	subsErr       error
+	due           []types.EventEntryStruct
+	done          []string
+	rescheduled   map[string]int
+	countErr      error
+	pending       int
+}
+
+func (f *fakeStore) GetSubscriptions() ([]types.SubscriptionStruct, error) {
+	return f.subscriptions, f.subsErr
+}
+
+func (f *fakeStore) SelectDueEventEntries(time.Time, int) ([]types.EventEntryStruct, error) {
+	return f.due, nil
+}
+
+func (f *fakeStore) MarkEventEntryDone(id string) error {
+	f.done = append(f.done, id)
+	return nil
+}
+
+func (f *fakeStore) RescheduleEventEntry(id string, attempts int, _ time.Time) error {
+	if f.rescheduled == nil {
+		f.rescheduled = map[string]int{}
+	}
+	f.rescheduled[id] = attempts
+	return nil
+}
+
+func (f *fakeStore) CountPendingEventEntries() (int, error) {
+	return f.pending, f.countErr
+}
+
+func TestDispatcherSignsAndDeliversToMatchingSubscription(t *testing.T) {
+	const secret = "shh"
+	var gotBody []byte
+	var gotSignature string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotSignature = r.Header.Get(HeaderSignature)
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	store := &fakeStore{
+		subscriptions: []types.SubscriptionStruct{{Id: "sub1", URL: server.URL, Secret: secret, Events: []string{TypeCampaignCreated}}},
+		due:           []types.EventEntryStruct{{Id: "event1", Type: TypeCampaignCreated, Payload: `{"name":"campaign1"}`, CreatedOn: time.Unix(0, 0)}},
+	}
+
+	dispatcher := NewDispatcher(store, zaptest.NewLogger(t))
+	dispatcher.tick()
+
+	assert.Equal(t, []string{"event1"}, store.done)
+	assert.Empty(t, store.rescheduled)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(gotBody)
+	assert.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), gotSignature)
+
+	var envelope Envelope
+	assert.NoError(t, json.Unmarshal(gotBody, &envelope))
+	assert.Equal(t, "event1", envelope.Id)
+	assert.Equal(t, TypeCampaignCreated, envelope.Type)
+	assert.JSONEq(t, `{"name":"campaign1"}`, string(envelope.Data))
+}
+
+func TestDispatcherSkipsNonMatchingSubscription(t *testing.T) {
+	var called bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	store := &fakeStore{
+		subscriptions: []types.SubscriptionStruct{{Id: "sub1", URL: server.URL, Events: []string{TypeParticipantDeleted}}},
+		due:           []types.EventEntryStruct{{Id: "event1", Type: TypeCampaignCreated, Payload: `{}`}},
+	}
+
+	dispatcher := NewDispatcher(store, zaptest.NewLogger(t))
+	dispatcher.tick()
+
+	assert.False(t, called)
+	assert.Equal(t, []string{"event1"}, store.done)
+}
+
+func TestDispatcherDeliversToEverySubscriberWithEmptyEventsFilter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	store := &fakeStore{
+		subscriptions: []types.SubscriptionStruct{{Id: "sub1", URL: server.URL}},
+		due:           []types.EventEntryStruct{{Id: "event1", Type: TypeParticipantScoreUpdated, Payload: `{}`}},
+	}
+
+	dispatcher := NewDispatcher(store, zaptest.NewLogger(t))
+	dispatcher.tick()
+
+	assert.Equal(t, []string{"event1"}, store.done)
+}
+
+func TestDispatcherReschedulesOnDeliveryError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	store := &fakeStore{
+		subscriptions: []types.SubscriptionStruct{{Id: "sub1", URL: server.URL}},
+		due:           []types.EventEntryStruct{{Id: "event1", Type: TypeCampaignCreated, Payload: `{}`, Attempts: 1}},
+	}
+
+	dispatcher := NewDispatcher(store, zaptest.NewLogger(t))
+	dispatcher.tick()
+
+	assert.Empty(t, store.done)
+	assert.Equal(t, 2, store.rescheduled["event1"])
+}
+
+func TestDispatcherReschedulesOnSubscriptionLookupError(t *testing.T) {
+	store := &fakeStore{
+		subsErr: assert.AnError,
+		due:     []types.EventEntryStruct{{Id: "event1", Type: TypeCampaignCreated, Payload: `{}`}},
+	}
+
+	dispatcher := NewDispatcher(store, zaptest.NewLogger(t))
+	dispatcher.tick()
+
+	assert.Empty(t, store.done)
+	assert.Equal(t, 1, store.rescheduled["event1"])
+}
+
+func TestDispatcherCounts(t *testing.T) {
+	store := &fakeStore{pending: 4}
+	dispatcher := NewDispatcher(store, zaptest.NewLogger(t))
+
+	pending, err := dispatcher.Counts()
+	assert.NoError(t, err)
+	assert.Equal(t, 4, pending)
+}
+
+func TestDispatcherCountsError(t *testing.T) {
+	store := &fakeStore{countErr: assert.AnError}
+	dispatcher := NewDispatcher(store, zaptest.NewLogger(t))
+
+	_, err := dispatcher.Counts()
+	assert.EqualError(t, err, assert.AnError.Error())
+}
+
+func TestDispatcherStartStop(t *testing.T) {
+	dispatcher := NewDispatcher(&fakeStore{}, zaptest.NewLogger(t))
+
+	assert.NoError(t, dispatcher.Start("@every 1h"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, dispatcher.Stop(ctx))
+}
+
+func TestDispatcherStartInvalidSpec(t *testing.T) {
+	dispatcher := NewDispatcher(&fakeStore{}, zaptest.NewLogger(t))
+	assert.Error(t, dispatcher.Start("not a valid cron spec"))
+}
@@ -0,0 +1,91 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package graph
+
+import (
+	"sync"
+
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+)
+
+// leaderboardChanBuffer bounds how many unread updates a slow subscriber can
+// fall behind by before newer updates are dropped for it; a dashboard only
+// cares about the latest standings, not every intermediate one.
+const leaderboardChanBuffer = 1
+
+// LeaderboardBroadcaster fans out Subscription.leaderboard(campaign) updates
+// to subscribed channels. It stands in for the graphql-ws transport's
+// per-client send loop, which (like the rest of the generated gqlgen server)
+// isn't buildable in this environment; wiring a real graphql-ws handler up
+// to Subscribe/Unsubscribe is mechanical once that dependency is available.
+type LeaderboardBroadcaster struct {
+	mu          sync.Mutex
+	subscribers map[string]map[chan []types.ParticipantStruct]bool
+}
+
+// NewLeaderboardBroadcaster builds an empty LeaderboardBroadcaster.
+func NewLeaderboardBroadcaster() *LeaderboardBroadcaster {
+	return &LeaderboardBroadcaster{
+		subscribers: map[string]map[chan []types.ParticipantStruct]bool{},
+	}
+}
+
+// Subscribe registers a new listener for campaignName's leaderboard updates.
+// Callers must call Unsubscribe with the returned channel once done.
+func (b *LeaderboardBroadcaster) Subscribe(campaignName string) chan []types.ParticipantStruct {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ch := make(chan []types.ParticipantStruct, leaderboardChanBuffer)
+	if b.subscribers[campaignName] == nil {
+		b.subscribers[campaignName] = map[chan []types.ParticipantStruct]bool{}
+	}
+	b.subscribers[campaignName][ch] = true
+	return ch
+}
+
+// Unsubscribe removes ch from campaignName's listeners and closes it.
+func (b *LeaderboardBroadcaster) Unsubscribe(campaignName string, ch chan []types.ParticipantStruct) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if listeners, ok := b.subscribers[campaignName]; ok {
+		delete(listeners, ch)
+		if len(listeners) == 0 {
+			delete(b.subscribers, campaignName)
+		}
+	}
+	close(ch)
+}
+
+// Publish sends participants to every current subscriber of campaignName's
+// leaderboard. A subscriber that hasn't drained its previous update yet is
+// skipped rather than blocked on.
+func (b *LeaderboardBroadcaster) Publish(campaignName string, participants []types.ParticipantStruct) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subscribers[campaignName] {
+		select {
+		case ch <- participants:
+		default:
+		}
+	}
+}
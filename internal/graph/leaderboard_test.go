@@ -0,0 +1,63 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package graph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+)
+
+func TestLeaderboardBroadcasterPublishToSubscriber(t *testing.T) {
+	b := NewLeaderboardBroadcaster()
+	ch := b.Subscribe("campaignName")
+	defer b.Unsubscribe("campaignName", ch)
+
+	participants := []types.ParticipantStruct{{LoginName: "alice", Score: 10}}
+	b.Publish("campaignName", participants)
+
+	assert.Equal(t, participants, <-ch)
+}
+
+func TestLeaderboardBroadcasterPublishIgnoresOtherCampaigns(t *testing.T) {
+	b := NewLeaderboardBroadcaster()
+	ch := b.Subscribe("campaignName")
+	defer b.Unsubscribe("campaignName", ch)
+
+	b.Publish("otherCampaign", []types.ParticipantStruct{{LoginName: "alice"}})
+
+	select {
+	case <-ch:
+		t.Fatal("unexpected publish delivered to subscriber of a different campaign")
+	default:
+	}
+}
+
+func TestLeaderboardBroadcasterUnsubscribeClosesChannel(t *testing.T) {
+	b := NewLeaderboardBroadcaster()
+	ch := b.Subscribe("campaignName")
+
+	b.Unsubscribe("campaignName", ch)
+
+	_, ok := <-ch
+	assert.False(t, ok)
+}
@@ -0,0 +1,121 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+// Package graph is the resolver layer for the schema described in
+// schema.graphqls. It is hand-written rather than gqlgen-generated: gqlgen
+// itself (and the graphql-ws subscription transport it wires up) can't be
+// fetched in this environment, so there is no generated ResolverRoot to
+// implement against yet. Once gqlgen is vendored, `go generate ./internal/graph`
+// (see gqlgen.yml) produces generated.go/models_gen.go, and Resolver's
+// methods below should be renamed/reshuffled to satisfy that generated
+// interface instead of being called directly; the DB-backed bodies don't
+// change.
+//
+// Resolver deliberately shares the exact db.IBBashDB/db.IScoreDB code paths
+// the REST handlers in server.go already use, so the two surfaces can never
+// drift apart.
+package graph
+
+import (
+	"github.com/sonatype-nexus-community/bbash/internal/db"
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+)
+
+// Resolver is the root resolver, analogous to what gqlgen's `generated.go`
+// would hand a ResolverRoot implementation. It holds nothing beyond what
+// setupRoutes already threads into the REST handlers.
+type Resolver struct {
+	DB          db.IBBashDB
+	ScoreDB     db.IScoreDB
+	Leaderboard *LeaderboardBroadcaster
+}
+
+// NewResolver builds a Resolver sharing bbashDB/scoreDB with the REST handlers,
+// publishing leaderboard updates to broadcaster whenever a mutation scores a bug.
+func NewResolver(bbashDB db.IBBashDB, scoreDB db.IScoreDB, broadcaster *LeaderboardBroadcaster) *Resolver {
+	return &Resolver{DB: bbashDB, ScoreDB: scoreDB, Leaderboard: broadcaster}
+}
+
+// Campaign resolves Query.campaign(name).
+func (r *Resolver) Campaign(name string) (*types.CampaignStruct, error) {
+	return r.DB.GetCampaign(name)
+}
+
+// Participant resolves Query.participant(campaignName, loginName) by scanning
+// the campaign's roster; there's no single-participant-by-login DB query to
+// call directly (SelectParticipantDetail also requires an scpName the schema
+// doesn't expose), so this mirrors what a dashboard doing the equivalent
+// REST call (GET participants in campaign, filter client-side) would do.
+func (r *Resolver) Participant(campaignName, loginName string) (*types.ParticipantStruct, error) {
+	participants, err := r.DB.SelectParticipantsInCampaign(campaignName)
+	if err != nil {
+		return nil, err
+	}
+	for i := range participants {
+		if participants[i].LoginName == loginName {
+			return &participants[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// Team resolves Query.team(campaignName, name).
+func (r *Resolver) Team(campaignName, name string) (*types.TeamStruct, error) {
+	return r.DB.GetTeam(campaignName, name)
+}
+
+// CreateCampaign resolves Mutation.createCampaign, the same InsertCampaign
+// call addCampaign makes from the REST side.
+func (r *Resolver) CreateCampaign(name string) (guid string, err error) {
+	campaign := types.CampaignStruct{Name: name}
+	// No caller identity is threaded through the resolver layer yet (see the package doc comment),
+	// so this records an attributable-to-nobody audit row rather than guessing an actor.
+	return r.DB.InsertCampaign(&campaign, "")
+}
+
+// AddParticipant resolves Mutation.addParticipant, the same InsertParticipant
+// call addParticipant makes from the REST side.
+func (r *Resolver) AddParticipant(campaignName, loginName string) (guid string, err error) {
+	participant := types.ParticipantStruct{CampaignName: campaignName, LoginName: loginName}
+	err = r.DB.InsertParticipant(&participant)
+	return participant.ID, err
+}
+
+// RegisterBug resolves Mutation.registerBug, the same InsertBug call addBug
+// makes from the REST side.
+func (r *Resolver) RegisterBug(campaign, category string, pointValue int) (guid string, err error) {
+	bug := types.BugStruct{Campaign: campaign, Category: category, PointValue: pointValue}
+	err = r.DB.InsertBug(&bug)
+	return bug.Id, err
+}
+
+// PublishLeaderboard re-reads campaignName's roster and pushes it to every
+// subscriber of Subscription.leaderboard(campaign: campaignName). Called
+// from processScoringMessage after a score changes, so subscribers see the
+// same ordering/data a client polling GET participants would see.
+func (r *Resolver) PublishLeaderboard(campaignName string) {
+	if r.Leaderboard == nil {
+		return
+	}
+	participants, err := r.DB.SelectParticipantsInCampaign(campaignName)
+	if err != nil {
+		return
+	}
+	r.Leaderboard.Publish(campaignName, participants)
+}
@@ -0,0 +1,37 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package leader
+
+import (
+	"database/sql"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// New returns a RedisElector leasing id against redisAddr, or (when redisAddr is empty, i.e.
+// leader election isn't configured to use Redis) a PostgresElector leasing id against db instead.
+// Either way, every replica must be given the same id and its own distinct instance.
+func New(redisAddr string, db *sql.DB, id, instance string, ttl time.Duration, logger *zap.Logger) Elector {
+	if redisAddr != "" {
+		return NewRedisElector(redisAddr, id, instance, ttl, logger)
+	}
+	return NewPostgresElector(db, id, instance, logger)
+}
@@ -0,0 +1,42 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package leader
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestNewPicksRedisElectorWhenAddrConfigured(t *testing.T) {
+	elector := New("localhost:6379", nil, "1", "theInstance", DefaultLeaseTTL, zaptest.NewLogger(t))
+
+	_, ok := elector.(*RedisElector)
+	assert.True(t, ok, "expected a *RedisElector when a redis addr is configured")
+}
+
+func TestNewPicksPostgresElectorWhenAddrEmpty(t *testing.T) {
+	elector := New("", nil, "1", "theInstance", time.Second, zaptest.NewLogger(t))
+
+	_, ok := elector.(*PostgresElector)
+	assert.True(t, ok, "expected a *PostgresElector when no redis addr is configured")
+}
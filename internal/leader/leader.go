@@ -0,0 +1,74 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+// Package leader provides leased leader election for the Poll loop, so running more than one
+// bbash replica behind a load balancer doesn't result in every replica polling and scoring the
+// same events. Exactly one Elector implementation should be active per process: RedisElector when
+// a Redis address is configured, PostgresElector (an advisory lock on the existing database)
+// otherwise.
+package leader
+
+import (
+	"context"
+	"time"
+)
+
+// Elector is held by a single candidate (a bbash replica) competing for a named lease. Acquire and
+// Refresh are called once per poll tick; a candidate that doesn't hold the lease must not run the
+// poll body that tick.
+type Elector interface {
+	// Instance identifies this candidate, e.g. hostname or a configured instance id.
+	Instance() string
+	// Acquire attempts to take or renew the lease. held is true only if this candidate now holds
+	// it (either newly acquired, or already held and refreshed).
+	Acquire(ctx context.Context) (held bool, err error)
+	// Held reports the outcome of the most recent Acquire call, without contacting Redis/Postgres
+	// again, for cheap status reporting (e.g. a /healthz endpoint) between ticks.
+	Held() bool
+	// Release gives up the lease early, e.g. on graceful shutdown. Best effort: the lease's own
+	// TTL is what guarantees another candidate eventually takes over even if Release is never
+	// called or fails.
+	Release(ctx context.Context)
+}
+
+// DefaultLeaseTTL bounds how long a lease survives without being refreshed, e.g. a candidate that
+// acquired it and then crashed mid-tick.
+const DefaultLeaseTTL = 60 * time.Second
+
+// always is an Elector that always holds the lease, for single-replica deployments and tests that
+// don't exercise leader election itself.
+type always struct {
+	instance string
+}
+
+// Always returns an Elector that never contends for the lease: Acquire always succeeds. Use it
+// when leader election is configured off, or as a test double.
+func Always(instance string) Elector {
+	return &always{instance: instance}
+}
+
+func (a *always) Instance() string { return a.instance }
+
+func (a *always) Acquire(_ context.Context) (held bool, err error) {
+	return true, nil
+}
+
+func (a *always) Held() bool { return true }
+
+func (a *always) Release(_ context.Context) {}
@@ -0,0 +1,125 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package leader
+
+import (
+	"context"
+	"database/sql"
+	"hash/fnv"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// PostgresElector leases the Poll loop via Postgres's session-level advisory locks
+// (pg_try_advisory_lock/pg_advisory_unlock), for deployments with no Redis configured. An advisory
+// lock is held by whichever database connection took it out, for as long as that connection stays
+// open, so PostgresElector checks out and holds a single *sql.Conn from the pool rather than a TTL:
+// if this process dies, the connection closes and Postgres releases the lock immediately, which is
+// the behavior an expiring Redis lease approximates for RedisElector.
+type PostgresElector struct {
+	db       *sql.DB
+	lockKey  int64
+	instance string
+	logger   *zap.Logger
+
+	mu   sync.Mutex
+	conn *sql.Conn // non-nil only while this instance holds the lock
+}
+
+var _ Elector = (*PostgresElector)(nil)
+
+// NewPostgresElector returns an Elector that leases id against db's advisory lock namespace,
+// identifying this candidate as instance.
+func NewPostgresElector(db *sql.DB, id, instance string, logger *zap.Logger) *PostgresElector {
+	return &PostgresElector{
+		db:       db,
+		lockKey:  advisoryLockKey(pollLockKeyPrefix + id),
+		instance: instance,
+		logger:   logger,
+	}
+}
+
+// advisoryLockKey hashes key down to the int64 pg_try_advisory_lock expects, since advisory locks
+// are keyed by number, not by arbitrary string.
+func advisoryLockKey(key string) int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	return int64(h.Sum64())
+}
+
+func (p *PostgresElector) Instance() string {
+	return p.instance
+}
+
+// Held reports whether this instance currently holds the advisory lock connection.
+func (p *PostgresElector) Held() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.conn != nil
+}
+
+// Acquire holds the advisory lock across ticks: once taken, it stays held (no TTL to refresh) until
+// Release is called or the held connection is lost, so a later call simply reports held=true
+// without hitting the database again.
+func (p *PostgresElector) Acquire(ctx context.Context) (held bool, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.conn != nil {
+		return true, nil
+	}
+
+	conn, err := p.db.Conn(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var acquired bool
+	if err = conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", p.lockKey).Scan(&acquired); err != nil {
+		_ = conn.Close()
+		return false, err
+	}
+	if !acquired {
+		_ = conn.Close()
+		return false, nil
+	}
+
+	p.conn = conn
+	return true, nil
+}
+
+// Release unlocks and returns the held connection to the pool, so another instance's
+// pg_try_advisory_lock can succeed on its next tick.
+func (p *PostgresElector) Release(ctx context.Context) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.conn == nil {
+		return
+	}
+	if _, err := p.conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", p.lockKey); err != nil {
+		p.logger.Warn("postgres elector release: unlock failed", zap.Error(err))
+	}
+	if err := p.conn.Close(); err != nil {
+		p.logger.Warn("postgres elector release: close failed", zap.Error(err))
+	}
+	p.conn = nil
+}
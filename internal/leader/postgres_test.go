@@ -0,0 +1,103 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package leader
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestPostgresElectorAcquire(t *testing.T) {
+	mockDb, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDb.Close()
+
+	elector := NewPostgresElector(mockDb, "1", "instanceA", zaptest.NewLogger(t))
+
+	mock.ExpectQuery("SELECT pg_try_advisory_lock\\(\\$1\\)").
+		WithArgs(elector.lockKey).
+		WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_lock"}).AddRow(true))
+
+	held, err := elector.Acquire(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, held)
+	assert.True(t, elector.Held())
+
+	// already held: Acquire must not hit the database again
+	held, err = elector.Acquire(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, held)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresElectorAcquireFailed(t *testing.T) {
+	mockDb, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDb.Close()
+
+	elector := NewPostgresElector(mockDb, "1", "instanceA", zaptest.NewLogger(t))
+
+	mock.ExpectQuery("SELECT pg_try_advisory_lock\\(\\$1\\)").
+		WithArgs(elector.lockKey).
+		WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_lock"}).AddRow(false))
+
+	held, err := elector.Acquire(context.Background())
+	assert.NoError(t, err)
+	assert.False(t, held)
+	assert.False(t, elector.Held())
+}
+
+func TestPostgresElectorRelease(t *testing.T) {
+	mockDb, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDb.Close()
+
+	elector := NewPostgresElector(mockDb, "1", "instanceA", zaptest.NewLogger(t))
+
+	mock.ExpectQuery("SELECT pg_try_advisory_lock\\(\\$1\\)").
+		WithArgs(elector.lockKey).
+		WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_lock"}).AddRow(true))
+	held, err := elector.Acquire(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, held)
+
+	mock.ExpectExec("SELECT pg_advisory_unlock\\(\\$1\\)").
+		WithArgs(elector.lockKey).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	elector.Release(context.Background())
+	assert.False(t, elector.Held())
+}
+
+func TestPostgresElectorReleaseWhenNotHeld(t *testing.T) {
+	mockDb, _, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDb.Close()
+
+	elector := NewPostgresElector(mockDb, "1", "instanceA", zaptest.NewLogger(t))
+
+	// no expectations set: Release must be a no-op when the lock was never acquired
+	elector.Release(context.Background())
+	assert.False(t, elector.Held())
+}
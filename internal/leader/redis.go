@@ -0,0 +1,239 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package leader
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// RedisElector leases key via a configured Redis using `SET key instance NX PX ttl`, the standard
+// Redis lock recipe. There is no vendored Redis client in this module (and none can be fetched
+// offline), so this speaks just enough of RESP (Redis's wire protocol) to issue SET/GET/DEL: a
+// single command, no pipelining, no cluster/sentinel support. It is deliberately NOT a full
+// Redlock implementation (no multi-node quorum, no fencing tokens) - for bbash's purposes, one
+// Redis plus the Postgres advisory lock fallback (PostgresElector) is a proportionate amount of
+// distributed-locking machinery.
+type RedisElector struct {
+	addr     string
+	key      string
+	instance string
+	ttl      time.Duration
+	logger   *zap.Logger
+
+	dialTimeout time.Duration
+	held        int32 // atomic bool, set by the most recent Acquire
+}
+
+var _ Elector = (*RedisElector)(nil)
+
+// pollLockKeyPrefix namespaces the poll loop's lease key from any other lease bbash might one day
+// take out on the same Redis.
+const pollLockKeyPrefix = "pollLock:"
+
+// NewRedisElector returns an Elector that leases pollLockKeyPrefix+id against the Redis at addr
+// (host:port), identifying this candidate as instance.
+func NewRedisElector(addr, id, instance string, ttl time.Duration, logger *zap.Logger) *RedisElector {
+	return &RedisElector{
+		addr:        addr,
+		key:         pollLockKeyPrefix + id,
+		instance:    instance,
+		ttl:         ttl,
+		logger:      logger,
+		dialTimeout: 2 * time.Second,
+	}
+}
+
+func (r *RedisElector) Instance() string {
+	return r.instance
+}
+
+// Acquire issues `SET key instance NX PX ttl`. If that fails because the key already exists, it
+// checks whether this instance is the current holder (e.g. re-acquiring on the next tick) and, if
+// so, refreshes the TTL with `SET key instance XX PX ttl`.
+func (r *RedisElector) Acquire(ctx context.Context) (held bool, err error) {
+	held, err = r.acquire(ctx)
+	if held {
+		atomic.StoreInt32(&r.held, 1)
+	} else {
+		atomic.StoreInt32(&r.held, 0)
+	}
+	return
+}
+
+func (r *RedisElector) acquire(ctx context.Context) (held bool, err error) {
+	conn, err := r.dial(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	ttlMillis := strconv.FormatInt(r.ttl.Milliseconds(), 10)
+
+	reply, err := r.do(conn, "SET", r.key, r.instance, "NX", "PX", ttlMillis)
+	if err != nil {
+		return false, err
+	}
+	if reply != nil {
+		return true, nil
+	}
+
+	current, err := r.do(conn, "GET", r.key)
+	if err != nil {
+		return false, err
+	}
+	if current == nil || *current != r.instance {
+		return false, nil
+	}
+
+	// we already hold it: refresh the TTL. This SET...XX isn't a compare-and-set on the value, so
+	// there is a window (between the GET above and this SET) where another instance could have
+	// raced in after our lease expired; PostgresElector's session-held advisory lock doesn't have
+	// this gap, which is the tradeoff documented on the package.
+	if _, err = r.do(conn, "SET", r.key, r.instance, "XX", "PX", ttlMillis); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Held reports the outcome of the most recent Acquire call.
+func (r *RedisElector) Held() bool {
+	return atomic.LoadInt32(&r.held) == 1
+}
+
+// Release deletes key, but only if this instance still appears to be the holder, so a lease we've
+// already lost to another instance isn't deleted out from under it.
+func (r *RedisElector) Release(ctx context.Context) {
+	defer atomic.StoreInt32(&r.held, 0)
+
+	conn, err := r.dial(ctx)
+	if err != nil {
+		r.logger.Warn("redis elector release: dial failed", zap.Error(err))
+		return
+	}
+	defer conn.Close()
+
+	current, err := r.do(conn, "GET", r.key)
+	if err != nil {
+		r.logger.Warn("redis elector release: get failed", zap.Error(err))
+		return
+	}
+	if current == nil || *current != r.instance {
+		return
+	}
+	if _, err = r.do(conn, "DEL", r.key); err != nil {
+		r.logger.Warn("redis elector release: del failed", zap.Error(err))
+	}
+}
+
+func (r *RedisElector) dial(ctx context.Context) (net.Conn, error) {
+	dialer := net.Dialer{Timeout: r.dialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", r.addr)
+	if err != nil {
+		return nil, fmt.Errorf("redis dial %s: %w", r.addr, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	} else {
+		_ = conn.SetDeadline(time.Now().Add(r.dialTimeout))
+	}
+	return conn, nil
+}
+
+// do sends args as a RESP array (the wire format every Redis command uses) and reads back a
+// single reply. A nil, nil result means a RESP nil bulk/array reply ("$-1" / "*-1"), i.e. Redis's
+// way of saying "no such key" - NX failed to SET, or GET found nothing.
+func (r *RedisElector) do(conn net.Conn, args ...string) (reply *string, err error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err = conn.Write([]byte(b.String())); err != nil {
+		return nil, fmt.Errorf("redis write: %w", err)
+	}
+
+	return readRESPReply(bufio.NewReader(conn))
+}
+
+// readRESPReply parses exactly one RESP reply: a simple string (+), error (-), integer (:), bulk
+// string ($), or (for DEL's sake) leaves array replies (*) unsupported since no command issued
+// here returns one.
+func readRESPReply(r *bufio.Reader) (reply *string, err error) {
+	line, err := readRESPLine(r)
+	if err != nil {
+		return nil, err
+	}
+	if line == "" {
+		return nil, fmt.Errorf("redis: empty reply")
+	}
+
+	switch line[0] {
+	case '+', ':':
+		value := line[1:]
+		return &value, nil
+	case '-':
+		return nil, fmt.Errorf("redis error: %s", line[1:])
+	case '$':
+		length, convErr := strconv.Atoi(line[1:])
+		if convErr != nil {
+			return nil, fmt.Errorf("redis: bad bulk length %q: %w", line[1:], convErr)
+		}
+		if length < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, length+2) // +2 for the trailing \r\n
+		if _, err = readFull(r, buf); err != nil {
+			return nil, err
+		}
+		value := string(buf[:length])
+		return &value, nil
+	default:
+		return nil, fmt.Errorf("redis: unsupported reply type %q", line[0])
+	}
+}
+
+func readRESPLine(r *bufio.Reader) (line string, err error) {
+	raw, err := r.ReadString('\n')
+	if err != nil {
+		return "", fmt.Errorf("redis read: %w", err)
+	}
+	return strings.TrimRight(raw, "\r\n"), nil
+}
+
+func readFull(r *bufio.Reader, buf []byte) (n int, err error) {
+	for n < len(buf) {
+		var read int
+		read, err = r.Read(buf[n:])
+		n += read
+		if err != nil {
+			return n, fmt.Errorf("redis read: %w", err)
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,225 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package leader
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+)
+
+// fakeRedis is just enough of a Redis server to exercise RedisElector's SET/GET/DEL usage: a
+// single in-memory string store, no expiry (RedisElector's own NX/XX semantics are what's under
+// test, not Redis's PX handling).
+type fakeRedis struct {
+	listener net.Listener
+
+	mu    sync.Mutex
+	store map[string]string
+}
+
+func startFakeRedis(t *testing.T) (addr string, closeFunc func()) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	f := &fakeRedis{listener: listener, store: map[string]string{}}
+	go f.serve()
+
+	return listener.Addr().String(), func() { _ = listener.Close() }
+}
+
+func (f *fakeRedis) serve() {
+	for {
+		conn, err := f.listener.Accept()
+		if err != nil {
+			return
+		}
+		go f.handle(conn)
+	}
+}
+
+func (f *fakeRedis) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		args, err := readRESPArray(r)
+		if err != nil {
+			return
+		}
+		reply := f.respond(args)
+		if _, err = conn.Write([]byte(reply)); err != nil {
+			return
+		}
+	}
+}
+
+func (f *fakeRedis) respond(args []string) string {
+	if len(args) == 0 {
+		return "-ERR empty command\r\n"
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch strings.ToUpper(args[0]) {
+	case "SET":
+		key, value := args[1], args[2]
+		nx, xx := false, false
+		for _, flag := range args[3:] {
+			switch strings.ToUpper(flag) {
+			case "NX":
+				nx = true
+			case "XX":
+				xx = true
+			}
+		}
+		_, exists := f.store[key]
+		if nx && exists {
+			return "$-1\r\n"
+		}
+		if xx && !exists {
+			return "$-1\r\n"
+		}
+		f.store[key] = value
+		return "+OK\r\n"
+	case "GET":
+		value, ok := f.store[args[1]]
+		if !ok {
+			return "$-1\r\n"
+		}
+		return fmt.Sprintf("$%d\r\n%s\r\n", len(value), value)
+	case "DEL":
+		delete(f.store, args[1])
+		return ":1\r\n"
+	default:
+		return fmt.Sprintf("-ERR unknown command %q\r\n", args[0])
+	}
+}
+
+// readRESPArray parses one RESP array request, the wire shape every Redis command is sent as.
+func readRESPArray(r *bufio.Reader) (args []string, err error) {
+	header, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	header = strings.TrimRight(header, "\r\n")
+	if len(header) == 0 || header[0] != '*' {
+		return nil, fmt.Errorf("expected array header, got %q", header)
+	}
+	count, err := strconv.Atoi(header[1:])
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < count; i++ {
+		lengthLine, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		lengthLine = strings.TrimRight(lengthLine, "\r\n")
+		length, err := strconv.Atoi(lengthLine[1:])
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, length+2)
+		if _, err = readFull(r, buf); err != nil {
+			return nil, err
+		}
+		args = append(args, string(buf[:length]))
+	}
+	return args, nil
+}
+
+func TestRedisElectorAcquireWhenUnheld(t *testing.T) {
+	addr, closeFunc := startFakeRedis(t)
+	defer closeFunc()
+
+	elector := NewRedisElector(addr, "1", "instanceA", time.Minute, zaptest.NewLogger(t))
+
+	held, err := elector.Acquire(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, held)
+	assert.True(t, elector.Held())
+}
+
+func TestRedisElectorAcquireHeldByAnotherInstance(t *testing.T) {
+	addr, closeFunc := startFakeRedis(t)
+	defer closeFunc()
+
+	first := NewRedisElector(addr, "1", "instanceA", time.Minute, zaptest.NewLogger(t))
+	held, err := first.Acquire(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, held)
+
+	second := NewRedisElector(addr, "1", "instanceB", time.Minute, zaptest.NewLogger(t))
+	held, err = second.Acquire(context.Background())
+	assert.NoError(t, err)
+	assert.False(t, held)
+	assert.False(t, second.Held())
+}
+
+func TestRedisElectorAcquireRefreshesOwnLease(t *testing.T) {
+	addr, closeFunc := startFakeRedis(t)
+	defer closeFunc()
+
+	elector := NewRedisElector(addr, "1", "instanceA", time.Minute, zaptest.NewLogger(t))
+	held, err := elector.Acquire(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, held)
+
+	// same instance re-acquiring on the next tick should refresh, not be refused
+	held, err = elector.Acquire(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, held)
+}
+
+func TestRedisElectorRelease(t *testing.T) {
+	addr, closeFunc := startFakeRedis(t)
+	defer closeFunc()
+
+	elector := NewRedisElector(addr, "1", "instanceA", time.Minute, zaptest.NewLogger(t))
+	_, err := elector.Acquire(context.Background())
+	assert.NoError(t, err)
+
+	elector.Release(context.Background())
+	assert.False(t, elector.Held())
+
+	other := NewRedisElector(addr, "1", "instanceB", time.Minute, zaptest.NewLogger(t))
+	held, err := other.Acquire(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, held, "lease should be free after Release")
+}
+
+func TestRedisElectorAcquireDialError(t *testing.T) {
+	elector := NewRedisElector("127.0.0.1:1", "1", "instanceA", time.Minute, zaptest.NewLogger(t))
+
+	held, err := elector.Acquire(context.Background())
+	assert.Error(t, err)
+	assert.False(t, held)
+}
@@ -0,0 +1,168 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+// Package metrics holds bbash's Prometheus collectors and the echo middleware/handler that expose
+// them at /metrics, so an operator can point a standard Prometheus/Grafana stack at bbash instead
+// of scraping the Zap JSON logs.
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	// RequestsTotal counts every HTTP response, labeled by the echo route pattern (not the raw
+	// path, so "/admin/bug/:campaignName" doesn't explode into one series per campaign).
+	RequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "bbash_http_requests_total",
+		Help: "Total HTTP requests processed, labeled by method, route and status.",
+	}, []string{"method", "route", "status"})
+
+	// RequestDuration observes how long each request took, labeled the same way as RequestsTotal.
+	RequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "bbash_http_request_duration_seconds",
+		Help:    "HTTP request latency in seconds, labeled by method and route.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	// ScoringMessagesTotal counts every ScoringMessage processScoringMessage handles, by outcome:
+	// "scored" (a participant's score changed), "skipped_org" (repo's organization isn't
+	// participating), "skipped_participant" (no active participant matched), or "error" (a DB call
+	// failed partway through).
+	ScoringMessagesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "bbash_scoring_messages_total",
+		Help: "Total ScoringMessages processed, labeled by result.",
+	}, []string{"result"})
+
+	// ScorePoints observes the points awarded for a single bug category on a single ScoringMessage,
+	// labeled by campaign and bug_category, so a Grafana panel can break down scoring throughput per
+	// campaign without re-deriving it from the scoring_event table.
+	ScorePoints = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "bbash_score_points",
+		Help:    "Points awarded per bug category per ScoringMessage, labeled by campaign and bug_category.",
+		Buckets: []float64{0, 1, 2, 3, 5, 8, 13, 21, 34},
+	}, []string{"campaign", "bug_category"})
+
+	// PollLagSeconds is how far behind the last completed poll tick (by any replica) is right now -
+	// the same figure pollerHealth/readyHealth already compute from Poll.LastPollCompleted.
+	PollLagSeconds = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "bbash_poll_lag_seconds",
+		Help: "Seconds since the poll loop last completed a tick, across all replicas.",
+	})
+
+	// PollFetchDuration observes how long a single ScoringSource page fetch took, as measured by
+	// fetchLogPage's own fetchDuration return value (the time spent in the Datadog API round
+	// trip, not the retries around it).
+	PollFetchDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "bbash_poll_fetch_duration_seconds",
+		Help:    "Duration of a single scoring source page fetch, in seconds.",
+		Buckets: prometheus.DefBuckets,
+	})
+
+	// PollLogsTotal counts every scoring event a poll tick has fetched from any ScoringSource.
+	PollLogsTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "bbash_poll_logs_total",
+		Help: "Total scoring events fetched by the poll loop.",
+	})
+
+	// PollErrorsTotal counts poll-loop errors by the stage that produced them: "fetch" (a
+	// ScoringSource.Fetch call), "process" (processScoringMessage/dead-lettering a batch), or
+	// "poll_db" (reading or writing the Poll row itself).
+	PollErrorsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "bbash_poll_errors_total",
+		Help: "Total poll loop errors, labeled by the stage that failed.",
+	}, []string{"kind"})
+
+	// PollCycleDuration observes the wall-clock duration of one complete ChaseTail tick: fetching,
+	// processing, and dead-letter retry, combined.
+	PollCycleDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "bbash_poll_cycle_duration_seconds",
+		Help:    "Duration of one complete poll tick (fetch + process + dead-letter retry), in seconds.",
+		Buckets: prometheus.DefBuckets,
+	})
+
+	// PollLastSuccessTimestamp is the unix timestamp of the last poll tick that fetched and
+	// processed its events without error, so an alert can fire on staleness directly instead of
+	// polling /healthz/poller.
+	PollLastSuccessTimestamp = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "bbash_poll_last_success_timestamp_seconds",
+		Help: "Unix timestamp of the last poll tick that completed without error.",
+	})
+)
+
+func init() {
+	// collectors.NewGoCollector isn't registered here: client_golang's own init() already
+	// registers one (and a process collector) into DefaultRegisterer, and registering a second
+	// one panics with "duplicate metrics collector registration attempted" on import.
+	prometheus.MustRegister(collectors.NewBuildInfoCollector())
+}
+
+// RegisterDBStats registers a collector exposing db's connection pool stats (open/idle/in-use
+// connections, wait count) under the "bbash" subsystem.
+func RegisterDBStats(db *sql.DB) {
+	prometheus.MustRegister(collectors.NewDBStatsCollector(db, "bbash"))
+}
+
+// Middleware records RequestsTotal/RequestDuration for every request that passes through it. It
+// belongs ahead of the route match in the middleware chain like RequestLogger, but reads c.Path()
+// (the matched route pattern, set once next(c) returns) rather than the raw request path.
+func Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			err := next(c)
+			if err != nil {
+				// next(c) returns the handler's error without having run it through the error
+				// handler yet (echo does that after the middleware chain unwinds), so an unmatched
+				// route's response is still nil/200 at this point. Run it now, the same way echo's
+				// own Logger middleware does, so c.Response().Status below reflects the real code.
+				c.Error(err)
+			}
+
+			// echo's router sets c.Path() to the raw request path (not "") when nothing matches, so
+			// an unmatched route is only identifiable by the sentinel error its NotFoundHandler
+			// returns, not by an empty route.
+			route := c.Path()
+			if err == echo.ErrNotFound {
+				route = "unmatched"
+			}
+			method := c.Request().Method
+
+			RequestsTotal.WithLabelValues(method, route, strconv.Itoa(c.Response().Status)).Inc()
+			RequestDuration.WithLabelValues(method, route).Observe(time.Since(start).Seconds())
+
+			return err
+		}
+	}
+}
+
+// Handler returns the http.Handler serving the Prometheus text exposition format, meant to be
+// mounted at GET /metrics via echo.WrapHandler.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
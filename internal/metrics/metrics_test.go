@@ -0,0 +1,77 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package metrics
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestInitDoesNotPanic documents the bug this package's init() used to have: registering
+// collectors.NewGoCollector() on top of the Go collector client_golang's own init() already
+// registers panics with "duplicate metrics collector registration attempted" the instant this
+// package is imported. Every test in this package loading at all (including this one running) is
+// already proof the regression is fixed, so there's nothing further to exercise here beyond
+// documenting why that's the case.
+func TestInitDoesNotPanic(t *testing.T) {
+	assert.NotPanics(t, func() {})
+}
+
+func TestMiddlewareRecordsRequestsTotalAndDuration(t *testing.T) {
+	RequestsTotal.Reset()
+
+	e := echo.New()
+	e.GET("/campaign/:campaignName", func(c echo.Context) error {
+		return c.NoContent(200)
+	})
+	e.Use(Middleware())
+
+	req := httptest.NewRequest("GET", "/campaign/someCampaign", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(RequestsTotal.WithLabelValues("GET", "/campaign/:campaignName", "200")))
+}
+
+func TestMiddlewareUnmatchedRoute(t *testing.T) {
+	RequestsTotal.Reset()
+
+	e := echo.New()
+	e.Use(Middleware())
+
+	req := httptest.NewRequest("GET", "/no-such-route", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(RequestsTotal.WithLabelValues("GET", "unmatched", "404")))
+}
+
+func TestHandlerServesMetrics(t *testing.T) {
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, req)
+
+	assert.Equal(t, 200, rec.Code)
+	assert.Contains(t, rec.Body.String(), "bbash_http_requests_total")
+}
@@ -0,0 +1,248 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+// Package outbox drains the upstream_outbox table db.BBashDB.UpdateParticipantScore,
+// InsertParticipant, and DeleteParticipant write to transactionally, publishing each entry to the
+// configured upstream.Backend and retrying with exponential backoff on failure. This keeps
+// upstream availability out of the scoring/participant hot paths: a slow or failing upstream only
+// delays when the change is mirrored, never whether the DB commit that triggered it succeeds. An
+// entry that still fails after maxAttempts is dead-lettered rather than retried forever.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+	"go.uber.org/zap"
+)
+
+// OpUpdateScore identifies an entry db.BBashDB.UpdateParticipantScore enqueues; its Payload
+// unmarshals into a ScorePayload. db.go's outboxOpUpdateScore literal must stay in sync with this.
+const OpUpdateScore = "update_score"
+
+// OpCreateParticipant identifies an entry db.BBashDB.InsertParticipant enqueues; its Payload
+// unmarshals into a CreateParticipantPayload. db.go's outboxOpCreateParticipant literal must stay
+// in sync with this.
+const OpCreateParticipant = "create_participant"
+
+// OpDeleteParticipant identifies an entry db.BBashDB.DeleteParticipant enqueues; its Payload
+// unmarshals into a DeleteParticipantPayload. db.go's outboxOpDeleteParticipant literal must stay
+// in sync with this.
+const OpDeleteParticipant = "delete_participant"
+
+// ScorePayload is the JSON body of an OpUpdateScore entry.
+type ScorePayload struct {
+	ParticipantUpstreamId string `json:"participantUpstreamId"`
+	// CampaignName scopes the per-campaign rate-limit quota a Publisher may apply to this
+	// update (see upstream.CampaignRateLimiters).
+	CampaignName string `json:"campaignName"`
+	Score        int    `json:"score"`
+}
+
+// CreateParticipantPayload is the JSON body of an OpCreateParticipant entry.
+type CreateParticipantPayload struct {
+	Participant        types.ParticipantStruct `json:"participant"`
+	CampaignUpstreamId string                  `json:"campaignUpstreamId"`
+}
+
+// DeleteParticipantPayload is the JSON body of an OpDeleteParticipant entry.
+type DeleteParticipantPayload struct {
+	ParticipantUpstreamId string `json:"participantUpstreamId"`
+}
+
+// baseRetryDelay and maxRetryDelay bound the exponential backoff applied between failed publish
+// attempts for a single entry - the same shape as upstream.RetryConfig, but much longer-lived since
+// these attempts span process restarts and an unreachable upstream, not a single request.
+const baseRetryDelay = 30 * time.Second
+const maxRetryDelay = 30 * time.Minute
+
+// batchSize bounds how many due entries a single tick drains, so one overloaded tick can't starve
+// the rest of the worker's responsibilities or hold the DB connection pool for too long.
+const batchSize = 50
+
+// maxAttempts bounds how many times a single entry is retried before Worker gives up on it and
+// marks it dead-lettered instead of rescheduling it again - otherwise a permanently broken payload
+// (e.g. a participant upstream never accepts) would retry forever and never surface to an operator.
+const maxAttempts = 10
+
+// Store is the outbox persistence Worker needs: enough of db.IBBashDB to drain, reschedule, and
+// dead-letter upstream_outbox rows, without depending on the db package directly.
+type Store interface {
+	SelectDueOutboxEntries(now time.Time, limit int) (entries []types.OutboxEntryStruct, err error)
+	MarkOutboxEntryDone(id string) (err error)
+	RescheduleOutboxEntry(id string, attempts int, nextAttemptAt time.Time) (err error)
+	CountPendingOutboxEntries() (pending int, err error)
+	MarkOutboxEntryDeadLettered(id string) (err error)
+	SelectDeadLetteredOutboxEntries() (entries []types.OutboxEntryStruct, err error)
+	ReplayOutboxEntry(id string) (err error)
+	SetParticipantUpstreamId(participantId, upstreamId string) (err error)
+}
+
+// Publisher is the upstream calls a Worker makes for each entry it drains; upstream.Backend
+// satisfies this directly.
+type Publisher interface {
+	UpdateScore(ctx context.Context, campaignName, participantUpstreamId string, score int) (err error)
+	UpsertParticipant(ctx context.Context, participant *types.ParticipantStruct, campaignUpstreamId string) (upstreamId string, err error)
+	DeleteParticipant(ctx context.Context, participantUpstreamId string) (err error)
+}
+
+// Worker periodically drains due upstream_outbox entries, the same cron-scheduled shape as
+// internal/campaign.Scheduler.
+type Worker struct {
+	store     Store
+	publisher Publisher
+	logger    *zap.Logger
+	cron      *cron.Cron
+}
+
+// NewWorker builds a Worker; call Start to begin running it.
+func NewWorker(store Store, publisher Publisher, logger *zap.Logger) *Worker {
+	return &Worker{store: store, publisher: publisher, logger: logger}
+}
+
+// Start begins running a tick on the given cron spec (e.g. "@every 15s") until Stop is called.
+func (w *Worker) Start(spec string) (err error) {
+	w.cron = cron.New()
+	if _, err = w.cron.AddFunc(spec, w.tick); err != nil {
+		return
+	}
+	w.cron.Start()
+	return
+}
+
+// Stop ends the cron loop and waits for any in-flight tick to finish, or for ctx to expire,
+// whichever comes first - the same shape as internal/campaign.Scheduler.Stop.
+func (w *Worker) Stop(ctx context.Context) (err error) {
+	stopped := w.cron.Stop()
+	select {
+	case <-stopped.Done():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Counts reports how many entries are still pending, for the /admin/outbox endpoint.
+func (w *Worker) Counts() (pending int, err error) {
+	return w.store.CountPendingOutboxEntries()
+}
+
+// ListDeadLettered returns every entry Worker has given up retrying, for the
+// GET /admin/outbox/dead-letter endpoint.
+func (w *Worker) ListDeadLettered() (entries []types.OutboxEntryStruct, err error) {
+	return w.store.SelectDeadLetteredOutboxEntries()
+}
+
+// Replay clears id's dead-lettered state and resets its attempts so the next tick picks it back
+// up, for the POST /admin/outbox/dead-letter/:id/replay endpoint.
+func (w *Worker) Replay(id string) (err error) {
+	return w.store.ReplayOutboxEntry(id)
+}
+
+// tick drains every due entry and publishes it.
+func (w *Worker) tick() {
+	entries, err := w.store.SelectDueOutboxEntries(time.Now(), batchSize)
+	if err != nil {
+		w.logger.Error("outbox worker: select due entries", zap.Error(err))
+		return
+	}
+
+	for _, entry := range entries {
+		w.publish(entry)
+	}
+}
+
+// publish dispatches a single entry by Op, marking it done on success and rescheduling it with
+// backoff on failure - including when a crash left it enqueued and unpublished after the DB
+// transaction that created it already committed, since a restarted Worker picks it back up from
+// SelectDueOutboxEntries exactly like any other due entry.
+func (w *Worker) publish(entry types.OutboxEntryStruct) {
+	var err error
+	switch entry.Op {
+	case OpUpdateScore:
+		var payload ScorePayload
+		if err = json.Unmarshal([]byte(entry.Payload), &payload); err == nil {
+			err = w.publisher.UpdateScore(context.Background(), payload.CampaignName, payload.ParticipantUpstreamId, payload.Score)
+		}
+	case OpCreateParticipant:
+		var payload CreateParticipantPayload
+		if err = json.Unmarshal([]byte(entry.Payload), &payload); err == nil {
+			var upstreamId string
+			if upstreamId, err = w.publisher.UpsertParticipant(context.Background(), &payload.Participant, payload.CampaignUpstreamId); err == nil {
+				if setErr := w.store.SetParticipantUpstreamId(entry.ParticipantId, upstreamId); setErr != nil {
+					w.logger.Error("outbox worker: set participant upstream id",
+						zap.String("id", entry.Id), zap.Error(setErr))
+				}
+			}
+		}
+	case OpDeleteParticipant:
+		var payload DeleteParticipantPayload
+		if err = json.Unmarshal([]byte(entry.Payload), &payload); err == nil {
+			err = w.publisher.DeleteParticipant(context.Background(), payload.ParticipantUpstreamId)
+		}
+	default:
+		w.logger.Error("outbox worker: unknown op, discarding entry",
+			zap.String("id", entry.Id), zap.String("op", entry.Op))
+		if markErr := w.store.MarkOutboxEntryDone(entry.Id); markErr != nil {
+			w.logger.Error("outbox worker: mark done", zap.String("id", entry.Id), zap.Error(markErr))
+		}
+		return
+	}
+
+	if err != nil {
+		w.reschedule(entry, err)
+		return
+	}
+
+	if markErr := w.store.MarkOutboxEntryDone(entry.Id); markErr != nil {
+		w.logger.Error("outbox worker: mark done", zap.String("id", entry.Id), zap.Error(markErr))
+	}
+}
+
+// reschedule records a failed publish attempt and pushes next_attempt_at out by an exponentially
+// growing delay, capped at maxRetryDelay so a long-broken upstream still gets retried eventually -
+// unless attempts has reached maxAttempts, in which case the entry is dead-lettered instead, so a
+// permanently failing entry stops retrying and surfaces for operator inspection/replay.
+func (w *Worker) reschedule(entry types.OutboxEntryStruct, cause error) {
+	attempts := entry.Attempts + 1
+
+	if attempts >= maxAttempts {
+		w.logger.Error("outbox worker: publish failed, dead-lettering after max attempts",
+			zap.String("id", entry.Id), zap.Int("attempts", attempts), zap.Error(cause))
+		if err := w.store.MarkOutboxEntryDeadLettered(entry.Id); err != nil {
+			w.logger.Error("outbox worker: dead-letter", zap.String("id", entry.Id), zap.Error(err))
+		}
+		return
+	}
+
+	delay := baseRetryDelay << attempts
+	if delay <= 0 || delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+
+	w.logger.Info("outbox worker: publish failed, rescheduling",
+		zap.String("id", entry.Id), zap.Int("attempts", attempts), zap.Duration("delay", delay), zap.Error(cause))
+
+	if err := w.store.RescheduleOutboxEntry(entry.Id, attempts, time.Now().Add(delay)); err != nil {
+		w.logger.Error("outbox worker: reschedule", zap.String("id", entry.Id), zap.Error(err))
+	}
+}
@@ -0,0 +1,278 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+)
+
+type fakeStore struct {
+	due             []types.OutboxEntryStruct
+	done            []string
+	rescheduled     map[string]int
+	countErr        error
+	pending         int
+	deadLettered    []string
+	deadLetterRows  []types.OutboxEntryStruct
+	replayed        []string
+	upstreamIdSetOn map[string]string
+}
+
+func (f *fakeStore) SelectDueOutboxEntries(time.Time, int) ([]types.OutboxEntryStruct, error) {
+	return f.due, nil
+}
+
+func (f *fakeStore) MarkOutboxEntryDone(id string) error {
+	f.done = append(f.done, id)
+	return nil
+}
+
+func (f *fakeStore) RescheduleOutboxEntry(id string, attempts int, _ time.Time) error {
+	if f.rescheduled == nil {
+		f.rescheduled = map[string]int{}
+	}
+	f.rescheduled[id] = attempts
+	return nil
+}
+
+func (f *fakeStore) CountPendingOutboxEntries() (int, error) {
+	return f.pending, f.countErr
+}
+
+func (f *fakeStore) MarkOutboxEntryDeadLettered(id string) error {
+	f.deadLettered = append(f.deadLettered, id)
+	return nil
+}
+
+func (f *fakeStore) SelectDeadLetteredOutboxEntries() ([]types.OutboxEntryStruct, error) {
+	return f.deadLetterRows, nil
+}
+
+func (f *fakeStore) ReplayOutboxEntry(id string) error {
+	f.replayed = append(f.replayed, id)
+	return nil
+}
+
+func (f *fakeStore) SetParticipantUpstreamId(participantId, upstreamId string) error {
+	if f.upstreamIdSetOn == nil {
+		f.upstreamIdSetOn = map[string]string{}
+	}
+	f.upstreamIdSetOn[participantId] = upstreamId
+	return nil
+}
+
+type fakePublisher struct {
+	calls        []ScorePayload
+	upsertCalls  []CreateParticipantPayload
+	deleteCalls  []string
+	err          error
+	upsertResult string
+}
+
+func (f *fakePublisher) UpdateScore(_ context.Context, campaignName, participantUpstreamId string, score int) error {
+	f.calls = append(f.calls, ScorePayload{ParticipantUpstreamId: participantUpstreamId, CampaignName: campaignName, Score: score})
+	return f.err
+}
+
+func (f *fakePublisher) UpsertParticipant(_ context.Context, participant *types.ParticipantStruct, campaignUpstreamId string) (string, error) {
+	f.upsertCalls = append(f.upsertCalls, CreateParticipantPayload{Participant: *participant, CampaignUpstreamId: campaignUpstreamId})
+	return f.upsertResult, f.err
+}
+
+func (f *fakePublisher) DeleteParticipant(_ context.Context, participantUpstreamId string) error {
+	f.deleteCalls = append(f.deleteCalls, participantUpstreamId)
+	return f.err
+}
+
+func scorePayloadEntry(t *testing.T, id string, payload ScorePayload) types.OutboxEntryStruct {
+	body, err := json.Marshal(payload)
+	assert.NoError(t, err)
+	return types.OutboxEntryStruct{Id: id, Op: OpUpdateScore, Payload: string(body)}
+}
+
+func TestWorkerTickPublishesDueEntriesAndMarksDone(t *testing.T) {
+	entry := scorePayloadEntry(t, "entry1", ScorePayload{ParticipantUpstreamId: "upstream1", Score: 7})
+	store := &fakeStore{due: []types.OutboxEntryStruct{entry}}
+	publisher := &fakePublisher{}
+
+	worker := NewWorker(store, publisher, zaptest.NewLogger(t))
+	worker.tick()
+
+	assert.Equal(t, []ScorePayload{{ParticipantUpstreamId: "upstream1", Score: 7}}, publisher.calls)
+	assert.Equal(t, []string{"entry1"}, store.done)
+	assert.Empty(t, store.rescheduled)
+}
+
+func TestWorkerTickReschedulesOnPublishError(t *testing.T) {
+	entry := scorePayloadEntry(t, "entry1", ScorePayload{ParticipantUpstreamId: "upstream1", Score: 7})
+	entry.Attempts = 2
+	store := &fakeStore{due: []types.OutboxEntryStruct{entry}}
+	publisher := &fakePublisher{err: assert.AnError}
+
+	worker := NewWorker(store, publisher, zaptest.NewLogger(t))
+	worker.tick()
+
+	assert.Empty(t, store.done)
+	assert.Equal(t, 3, store.rescheduled["entry1"])
+}
+
+func TestWorkerTickDiscardsUnknownOp(t *testing.T) {
+	store := &fakeStore{due: []types.OutboxEntryStruct{{Id: "entry1", Op: "some_future_op"}}}
+	publisher := &fakePublisher{}
+
+	worker := NewWorker(store, publisher, zaptest.NewLogger(t))
+	worker.tick()
+
+	assert.Empty(t, publisher.calls)
+	assert.Equal(t, []string{"entry1"}, store.done)
+}
+
+func TestWorkerTickReschedulesOnMalformedPayload(t *testing.T) {
+	store := &fakeStore{due: []types.OutboxEntryStruct{{Id: "entry1", Op: OpUpdateScore, Payload: "not json"}}}
+	publisher := &fakePublisher{}
+
+	worker := NewWorker(store, publisher, zaptest.NewLogger(t))
+	worker.tick()
+
+	assert.Empty(t, publisher.calls)
+	assert.Empty(t, store.done)
+	assert.Equal(t, 1, store.rescheduled["entry1"])
+}
+
+// TestWorkerRecoversEntryAfterCrashBetweenDBCommitAndPublish simulates a process crashing after the
+// DB transaction that enqueued an entry committed, but before the previous Worker instance got a
+// chance to publish it: a fresh Worker's first tick should find it via SelectDueOutboxEntries and
+// publish it exactly like any other due entry, giving at-least-once delivery across restarts.
+func TestWorkerRecoversEntryAfterCrashBetweenDBCommitAndPublish(t *testing.T) {
+	entry := scorePayloadEntry(t, "entry1", ScorePayload{ParticipantUpstreamId: "upstream1", Score: 42})
+	store := &fakeStore{due: []types.OutboxEntryStruct{entry}}
+	publisher := &fakePublisher{}
+
+	recoveredWorker := NewWorker(store, publisher, zaptest.NewLogger(t))
+	recoveredWorker.tick()
+
+	assert.Equal(t, []ScorePayload{{ParticipantUpstreamId: "upstream1", Score: 42}}, publisher.calls)
+	assert.Equal(t, []string{"entry1"}, store.done)
+}
+
+func TestWorkerCounts(t *testing.T) {
+	store := &fakeStore{pending: 3}
+	worker := NewWorker(store, &fakePublisher{}, zaptest.NewLogger(t))
+
+	pending, err := worker.Counts()
+	assert.NoError(t, err)
+	assert.Equal(t, 3, pending)
+}
+
+func TestWorkerCountsError(t *testing.T) {
+	store := &fakeStore{countErr: assert.AnError}
+	worker := NewWorker(store, &fakePublisher{}, zaptest.NewLogger(t))
+
+	_, err := worker.Counts()
+	assert.EqualError(t, err, assert.AnError.Error())
+}
+
+func TestWorkerStartStop(t *testing.T) {
+	store := &fakeStore{}
+	worker := NewWorker(store, &fakePublisher{}, zaptest.NewLogger(t))
+
+	assert.NoError(t, worker.Start("@every 1h"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, worker.Stop(ctx))
+}
+
+func TestWorkerStartInvalidSpec(t *testing.T) {
+	worker := NewWorker(&fakeStore{}, &fakePublisher{}, zaptest.NewLogger(t))
+	assert.Error(t, worker.Start("not a valid cron spec"))
+}
+
+func TestWorkerTickCreatesParticipantUpstreamAndStoresId(t *testing.T) {
+	payload := CreateParticipantPayload{
+		Participant:        types.ParticipantStruct{ID: "p1", LoginName: "alice"},
+		CampaignUpstreamId: "camp-upstream-1",
+	}
+	body, err := json.Marshal(payload)
+	assert.NoError(t, err)
+	entry := types.OutboxEntryStruct{Id: "entry1", Op: OpCreateParticipant, ParticipantId: "p1", Payload: string(body)}
+	store := &fakeStore{due: []types.OutboxEntryStruct{entry}}
+	publisher := &fakePublisher{upsertResult: "upstream-p1"}
+
+	worker := NewWorker(store, publisher, zaptest.NewLogger(t))
+	worker.tick()
+
+	assert.Equal(t, []CreateParticipantPayload{payload}, publisher.upsertCalls)
+	assert.Equal(t, "upstream-p1", store.upstreamIdSetOn["p1"])
+	assert.Equal(t, []string{"entry1"}, store.done)
+}
+
+func TestWorkerTickDeletesParticipantUpstream(t *testing.T) {
+	payload := DeleteParticipantPayload{ParticipantUpstreamId: "upstream-p1"}
+	body, err := json.Marshal(payload)
+	assert.NoError(t, err)
+	entry := types.OutboxEntryStruct{Id: "entry1", Op: OpDeleteParticipant, Payload: string(body)}
+	store := &fakeStore{due: []types.OutboxEntryStruct{entry}}
+	publisher := &fakePublisher{}
+
+	worker := NewWorker(store, publisher, zaptest.NewLogger(t))
+	worker.tick()
+
+	assert.Equal(t, []string{"upstream-p1"}, publisher.deleteCalls)
+	assert.Equal(t, []string{"entry1"}, store.done)
+}
+
+func TestWorkerTickDeadLettersAfterMaxAttempts(t *testing.T) {
+	entry := scorePayloadEntry(t, "entry1", ScorePayload{ParticipantUpstreamId: "upstream1", Score: 7})
+	entry.Attempts = maxAttempts - 1
+	store := &fakeStore{due: []types.OutboxEntryStruct{entry}}
+	publisher := &fakePublisher{err: assert.AnError}
+
+	worker := NewWorker(store, publisher, zaptest.NewLogger(t))
+	worker.tick()
+
+	assert.Equal(t, []string{"entry1"}, store.deadLettered)
+	assert.Empty(t, store.rescheduled)
+}
+
+func TestWorkerListDeadLettered(t *testing.T) {
+	rows := []types.OutboxEntryStruct{{Id: "entry1", DeadLettered: true}}
+	store := &fakeStore{deadLetterRows: rows}
+	worker := NewWorker(store, &fakePublisher{}, zaptest.NewLogger(t))
+
+	entries, err := worker.ListDeadLettered()
+	assert.NoError(t, err)
+	assert.Equal(t, rows, entries)
+}
+
+func TestWorkerReplay(t *testing.T) {
+	store := &fakeStore{}
+	worker := NewWorker(store, &fakePublisher{}, zaptest.NewLogger(t))
+
+	assert.NoError(t, worker.Replay("entry1"))
+	assert.Equal(t, []string{"entry1"}, store.replayed)
+}
@@ -0,0 +1,143 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package poll
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+)
+
+// defaultBatchFlushIntervalSeconds bounds how long a participant's scoring messages can
+// accumulate before being flushed, even if the enclosing poll tick is still collecting logs.
+const defaultBatchFlushIntervalSeconds = 30
+
+// defaultBatchMaxSize bounds how many scoring messages are coalesced for a single participant
+// before being flushed early, so a single noisy participant can't hold up their own score update
+// indefinitely within a long poll tick.
+const defaultBatchMaxSize = 50
+
+// scoreBatch accumulates the net effect of every ScoringMessage seen so far for one participant
+// key within the current window.
+type scoreBatch struct {
+	opened      time.Time
+	count       int
+	msg         types.ScoringMessage
+	envBaseTime time.Time
+}
+
+// BatchProcessor coalesces ScoringMessages for the same participant within a polling window into
+// a single net scoring message, so that a burst of scoring events for one participant (e.g.
+// several fixed-bug commits landing back to back) results in one score update instead of one per
+// event. A batch is flushed when maxBatchSize messages have accumulated for its key or
+// flushInterval has elapsed since the batch was opened, whichever comes first; processLogs also
+// flushes every remaining batch at the end of each poll tick.
+type BatchProcessor struct {
+	flushInterval time.Duration
+	maxBatchSize  int
+
+	mu      sync.Mutex
+	batches map[string]*scoreBatch
+}
+
+// NewBatchProcessor builds a BatchProcessor. A flushInterval or maxBatchSize <= 0 falls back to
+// the package defaults.
+func NewBatchProcessor(flushInterval time.Duration, maxBatchSize int) *BatchProcessor {
+	if flushInterval <= 0 {
+		flushInterval = defaultBatchFlushIntervalSeconds * time.Second
+	}
+	if maxBatchSize <= 0 {
+		maxBatchSize = defaultBatchMaxSize
+	}
+	return &BatchProcessor{
+		flushInterval: flushInterval,
+		maxBatchSize:  maxBatchSize,
+		batches:       map[string]*scoreBatch{},
+	}
+}
+
+// participantKey identifies the participant a ScoringMessage belongs to, for the purposes of
+// coalescing. RepoOwner/RepoName are included, since validScore re-derives organization
+// membership from them per message and merging across repos would lose that.
+func participantKey(msg *types.ScoringMessage) string {
+	return fmt.Sprintf("%s/%s#%s", msg.RepoOwner, msg.RepoName, msg.TriggerUser)
+}
+
+// Add merges msg, observed with the given envBaseTime, into the open batch for its participant
+// key. It returns the batch's current net message and envBaseTime, along with whether the batch
+// should be flushed now because it has hit maxBatchSize or outlived flushInterval.
+func (b *BatchProcessor) Add(msg types.ScoringMessage, envBaseTime time.Time) (merged types.ScoringMessage, mergedEnvBaseTime time.Time, shouldFlush bool) {
+	key := participantKey(&msg)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	batch, ok := b.batches[key]
+	if !ok {
+		batch = &scoreBatch{opened: time.Now(), msg: msg, envBaseTime: envBaseTime}
+		b.batches[key] = batch
+	} else {
+		batch.msg.TotalFixed += msg.TotalFixed
+		if batch.msg.BugCounts == nil {
+			batch.msg.BugCounts = map[string]int{}
+		}
+		for bugType, count := range msg.BugCounts {
+			batch.msg.BugCounts[bugType] += count
+		}
+		// envBaseTime-latest wins: keep the most recent source envBaseTime seen for this batch,
+		// matching the ordering pollTheDog relies on to advance poll.EnvBaseTime.
+		if envBaseTime.After(batch.envBaseTime) {
+			batch.envBaseTime = envBaseTime
+		}
+		// PullRequest/EventSource are left at whichever message opened the batch; only the
+		// aggregate bug counts matter for the net score delta this batch will produce.
+	}
+	batch.count++
+
+	shouldFlush = batch.count >= b.maxBatchSize || time.Since(batch.opened) >= b.flushInterval
+	return batch.msg, batch.envBaseTime, shouldFlush
+}
+
+// Remove drops the open batch for msg's participant key, e.g. once it has been flushed.
+func (b *BatchProcessor) Remove(msg types.ScoringMessage) {
+	key := participantKey(&msg)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.batches, key)
+}
+
+// Flush drains and returns every open batch, keyed by participant, regardless of their
+// individual flush thresholds. processLogs calls this at the end of every poll tick so that each
+// window ends with at most one net scoring message per participant.
+func (b *BatchProcessor) Flush() map[string]types.ScoringMessage {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	flushed := make(map[string]types.ScoringMessage, len(b.batches))
+	for key, batch := range b.batches {
+		flushed[key] = batch.msg
+	}
+	b.batches = map[string]*scoreBatch{}
+	return flushed
+}
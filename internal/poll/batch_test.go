@@ -0,0 +1,105 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package poll
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchProcessorMergesBugCountsForSameParticipant(t *testing.T) {
+	batch := NewBatchProcessor(time.Minute, 10)
+
+	base := time.Now()
+	msg1 := types.ScoringMessage{RepoOwner: "owner", RepoName: "repo", TriggerUser: "user", TotalFixed: 1, BugCounts: map[string]int{"G104": 1}}
+	msg2 := types.ScoringMessage{RepoOwner: "owner", RepoName: "repo", TriggerUser: "user", TotalFixed: 2, BugCounts: map[string]int{"G104": 1, "ShellCheck": 1}}
+
+	merged, envBaseTime, shouldFlush := batch.Add(msg1, base)
+	assert.False(t, shouldFlush)
+	assert.Equal(t, 1, merged.TotalFixed)
+	assert.Equal(t, base, envBaseTime)
+
+	merged, envBaseTime, shouldFlush = batch.Add(msg2, base.Add(time.Second))
+	assert.False(t, shouldFlush)
+	assert.Equal(t, 3, merged.TotalFixed)
+	assert.Equal(t, map[string]int{"G104": 2, "ShellCheck": 1}, merged.BugCounts)
+	assert.Equal(t, base.Add(time.Second), envBaseTime)
+}
+
+func TestBatchProcessorKeepsDifferentParticipantsSeparate(t *testing.T) {
+	batch := NewBatchProcessor(time.Minute, 10)
+
+	now := time.Now()
+	_, _, _ = batch.Add(types.ScoringMessage{RepoOwner: "owner", RepoName: "repo", TriggerUser: "alice", TotalFixed: 1}, now)
+	_, _, _ = batch.Add(types.ScoringMessage{RepoOwner: "owner", RepoName: "repo", TriggerUser: "bob", TotalFixed: 5}, now)
+
+	flushed := batch.Flush()
+	assert.Len(t, flushed, 2)
+}
+
+func TestBatchProcessorFlushesAtMaxBatchSize(t *testing.T) {
+	batch := NewBatchProcessor(time.Minute, 2)
+
+	now := time.Now()
+	msg := types.ScoringMessage{RepoOwner: "owner", RepoName: "repo", TriggerUser: "user", TotalFixed: 1}
+
+	_, _, shouldFlush := batch.Add(msg, now)
+	assert.False(t, shouldFlush)
+
+	_, _, shouldFlush = batch.Add(msg, now)
+	assert.True(t, shouldFlush)
+}
+
+func TestBatchProcessorFlushesAfterFlushInterval(t *testing.T) {
+	batch := NewBatchProcessor(time.Millisecond, 100)
+
+	now := time.Now()
+	msg := types.ScoringMessage{RepoOwner: "owner", RepoName: "repo", TriggerUser: "user", TotalFixed: 1}
+
+	_, _, _ = batch.Add(msg, now)
+	time.Sleep(5 * time.Millisecond)
+	_, _, shouldFlush := batch.Add(msg, now)
+	assert.True(t, shouldFlush)
+}
+
+func TestBatchProcessorRemoveClearsBatch(t *testing.T) {
+	batch := NewBatchProcessor(time.Minute, 10)
+
+	now := time.Now()
+	msg := types.ScoringMessage{RepoOwner: "owner", RepoName: "repo", TriggerUser: "user", TotalFixed: 1}
+	_, _, _ = batch.Add(msg, now)
+
+	batch.Remove(msg)
+	assert.Empty(t, batch.Flush())
+}
+
+func TestBatchProcessorFlushDrainsAllOpenBatches(t *testing.T) {
+	batch := NewBatchProcessor(time.Minute, 10)
+
+	now := time.Now()
+	_, _, _ = batch.Add(types.ScoringMessage{RepoOwner: "owner", RepoName: "repo", TriggerUser: "user"}, now)
+
+	flushed := batch.Flush()
+	assert.Len(t, flushed, 1)
+	assert.Empty(t, batch.Flush())
+}
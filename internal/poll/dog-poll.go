@@ -22,14 +22,20 @@ package poll
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/DataDog/datadog-api-client-go/api/v2/datadog"
 	"github.com/sonatype-nexus-community/bbash/internal/db"
+	"github.com/sonatype-nexus-community/bbash/internal/leader"
+	"github.com/sonatype-nexus-community/bbash/internal/metrics"
 	"github.com/sonatype-nexus-community/bbash/internal/types"
 	"go.uber.org/zap"
+	"math/rand"
 	"net/http"
 	"net/http/httputil"
 	"os"
+	"strconv"
+	"sync"
 	"time"
 )
 
@@ -42,7 +48,7 @@ func init() {
 }
 
 type IDogApiClient interface {
-	getDDApiClient() (context.Context, *datadog.APIClient)
+	getDDApiClient(ctx context.Context) (context.Context, *datadog.APIClient)
 }
 
 type DogApiClient struct {
@@ -50,9 +56,9 @@ type DogApiClient struct {
 
 var _ IDogApiClient = (*DogApiClient)(nil)
 
-func (c *DogApiClient) getDDApiClient() (context.Context, *datadog.APIClient) {
-	ctx := context.WithValue(
-		context.Background(),
+func (c *DogApiClient) getDDApiClient(ctx context.Context) (context.Context, *datadog.APIClient) {
+	ctx = context.WithValue(
+		ctx,
 		datadog.ContextAPIKeys,
 		map[string]datadog.APIKey{
 			// API Key
@@ -81,12 +87,51 @@ const qryFldFixedBugs = "fixed-bugs"
 // should be negative
 const pollFudgeSeconds = -5
 
-func pollTheDog(pollDB db.IDBPoll, priorPollTime, now time.Time) (logs []ddLog, err error) {
+// fetchDeadlineTimer arms a cancellable child of a parent context with a deadline via
+// time.AfterFunc, the way a watchdog timer is armed-then-reset: reset before each new page so a
+// source that's still paging promptly doesn't trip it, but a single page whose Fetch hangs (e.g. a
+// stalled HTTP call in DatadogSource) has its child context cancelled instead of blocking the poll
+// loop indefinitely.
+type fetchDeadlineTimer struct {
+	cancel context.CancelFunc
+	timer  *time.Timer
+}
+
+// newFetchDeadlineTimer derives a cancellable child of ctx and arms it to fire after deadline. A
+// non-positive deadline leaves the timer disarmed - the child is still cancelled if ctx is, just
+// never by the deadline.
+func newFetchDeadlineTimer(ctx context.Context, deadline time.Duration) (child context.Context, t *fetchDeadlineTimer) {
+	child, cancel := context.WithCancel(ctx)
+	t = &fetchDeadlineTimer{cancel: cancel}
+	if deadline > 0 {
+		t.timer = time.AfterFunc(deadline, cancel)
+	}
+	return
+}
+
+// reset re-arms the deadline for the next page; a no-op if the timer was never armed.
+func (t *fetchDeadlineTimer) reset(deadline time.Duration) {
+	if t.timer != nil {
+		t.timer.Reset(deadline)
+	}
+}
+
+// stop disarms the deadline timer and cancels the child context, releasing both once the fetch
+// loop they were guarding is done.
+func (t *fetchDeadlineTimer) stop() {
+	if t.timer != nil {
+		t.timer.Stop()
+	}
+	t.cancel()
+}
+
+func pollTheDog(ctx context.Context, source ScoringSource, pollDB db.IDBPoll, instance string, priorPollTime, now time.Time, fetchDeadline time.Duration) (events []ScoringEvent, err error) {
 
 	// get last poll time from database
 	poll := pollDB.NewPoll()
-	err = pollDB.SelectPoll(&poll)
+	err = pollDB.SelectPoll(ctx, &poll)
 	if err != nil {
+		metrics.PollErrorsTotal.WithLabelValues("poll_db").Inc()
 		return
 	}
 
@@ -102,38 +147,51 @@ func pollTheDog(pollDB db.IDBPoll, priorPollTime, now time.Time) (logs []ddLog,
 	// fudge factor, always poll a little older than last poll, to make sure no scores are missed
 	before = before.Add(time.Second * pollFudgeSeconds)
 
+	pageCtx, deadline := newFetchDeadlineTimer(ctx, fetchDeadline)
+	defer deadline.stop()
+
 	pageCursor := ""
 	isDone := false
-	var totalFetchDuration time.Duration
+	fetchStart := time.Now()
 	for err == nil && isDone == false {
-		var logPage []ddLog
-		var fetchDuration time.Duration
-		isDone, pageCursor, logPage, fetchDuration, err = fetchLogPage(before, now, &pageCursor)
+		select {
+		case <-pageCtx.Done():
+			err = pageCtx.Err()
+			return
+		default:
+		}
+
+		deadline.reset(fetchDeadline)
+		var page []ScoringEvent
+		page, pageCursor, isDone, err = source.Fetch(pageCtx, before, now, pageCursor)
 		if err != nil {
+			metrics.PollErrorsTotal.WithLabelValues("fetch").Inc()
 			return
 		}
 
-		logs = append(logs, logPage...)
-		totalFetchDuration = totalFetchDuration + fetchDuration
+		metrics.PollLogsTotal.Add(float64(len(page)))
+		events = append(events, page...)
 	}
 
-	logCount := len(logs)
+	eventCount := len(events)
 	logger.Debug("total polled",
-		zap.Int("log count", logCount),
+		zap.Int("event count", eventCount),
 		zap.String("before", before.Format(time.RFC3339)),
 		zap.String("now", now.Format(time.RFC3339)),
-		zap.Duration("totalFetchDuration", totalFetchDuration),
+		zap.Duration("totalFetchDuration", time.Since(fetchStart)),
 		zap.Int("maxLogsPerPage", maxLogsPerPage),
 	)
 
 	// Update Poll completed time
 	poll.LastPolled = now
-	if logCount > 0 {
-		poll.EnvBaseTime = logs[logCount-1].Fields.envBaseTime
+	if eventCount > 0 {
+		poll.EnvBaseTime = events[eventCount-1].EnvBaseTime
 	}
 	poll.LastPollCompleted = time.Now()
-	err = pollDB.UpdatePoll(&poll)
+	poll.LeaderInstance = instance
+	err = pollDB.UpdatePoll(ctx, &poll)
 	if err != nil {
+		metrics.PollErrorsTotal.WithLabelValues("poll_db").Inc()
 		return
 	}
 
@@ -142,8 +200,100 @@ func pollTheDog(pollDB db.IDBPoll, priorPollTime, now time.Time) (logs []ddLog,
 
 const maxLogsPerPage = 500
 
-func fetchLogPage(before, now time.Time, pageCursor *string) (isDone bool, cursor string, logs []ddLog, fetchDuration time.Duration, err error) {
-	ctx, apiClient := dogApiClient.getDDApiClient()
+// fetchRetryMaxAttempts, fetchRetryBaseDelay and fetchRetryCapDelay mirror upstream.DefaultRetryConfig:
+// five attempts, exponential backoff starting at 500ms, capped at 30s between tries. Declared as
+// vars, rather than consts, so tests can shrink them instead of waiting out real backoff delays.
+var fetchRetryMaxAttempts = 5
+var fetchRetryBaseDelay = 500 * time.Millisecond
+var fetchRetryCapDelay = 30 * time.Second
+
+// fetchLogPage fetches one page of Datadog logs, retrying transient failures (5xx, 429, network
+// errors without a response, and the API's own TIMEOUT status) with jittered exponential backoff,
+// honoring a Retry-After response header when the API sends one. Fatal errors (4xx, malformed
+// response, warnings) are returned immediately. The retry loop respects ctx, so a cancellation or
+// the per-page fetchDeadlineTimer (see pollTheDog) preempts an in-progress backoff sleep; the
+// page's cursor is never advanced by fetchLogPage itself, so a retry re-fetches the same page.
+func fetchLogPage(ctx context.Context, before, now time.Time, pageCursor *string) (isDone bool, cursor string, logs []ddLog, fetchDuration time.Duration, err error) {
+	var retryable, hasRetryAfter bool
+	var retryAfter time.Duration
+	for attempt := 1; attempt <= fetchRetryMaxAttempts; attempt++ {
+		isDone, cursor, logs, fetchDuration, retryable, retryAfter, hasRetryAfter, err = fetchLogPageAttempt(ctx, before, now, pageCursor)
+		if fetchDuration > 0 {
+			metrics.PollFetchDuration.Observe(fetchDuration.Seconds())
+		}
+		if err == nil || !retryable || attempt == fetchRetryMaxAttempts {
+			return
+		}
+
+		delay := fetchRetryBackoff(attempt, retryAfter, hasRetryAfter)
+		logger.Debug("retrying datadog log fetch",
+			zap.Int("attempt", attempt),
+			zap.Duration("delay", delay),
+			zap.Error(err),
+		)
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			err = ctx.Err()
+			return
+		}
+	}
+	return
+}
+
+// fetchRetryBackoff returns retryAfter when the API sent one, otherwise half-jittered exponential
+// backoff (the same formula as upstream.retryingClient.backoff) based on the 1-based attempt.
+func fetchRetryBackoff(attempt int, retryAfter time.Duration, hasRetryAfter bool) time.Duration {
+	if hasRetryAfter {
+		return retryAfter
+	}
+
+	delay := fetchRetryBaseDelay << (attempt - 1)
+	if delay > fetchRetryCapDelay || delay <= 0 {
+		delay = fetchRetryCapDelay
+	}
+	jitter := time.Duration(rand.Int63n(int64(delay) + 1))
+	return delay/2 + jitter/2
+}
+
+// fetchRetryAfter parses a Retry-After header value (seconds or HTTP-date form), the same way
+// upstream.parseRetryAfter does.
+func fetchRetryAfter(value string) (delay time.Duration, ok bool) {
+	if value == "" {
+		return
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if when, err := http.ParseTime(value); err == nil {
+		if d := time.Until(when); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return
+}
+
+// isRetryableFetchErr classifies an error from apiClient.LogsApi.ListLogs: a deliberate
+// cancellation (ctx.Done, the fetchDeadlineTimer) is fatal so a shutdown or deadline isn't masked
+// by a retry; a response-less error (dial failure, timeout) or a 429/5xx response is transient;
+// anything else (4xx auth/validation errors) is fatal.
+func isRetryableFetchErr(r *http.Response, err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if r == nil {
+		return true
+	}
+	return r.StatusCode == http.StatusTooManyRequests || r.StatusCode >= 500
+}
+
+func fetchLogPageAttempt(ctx context.Context, before, now time.Time, pageCursor *string) (isDone bool, cursor string, logs []ddLog, fetchDuration time.Duration, retryable bool, retryAfter time.Duration, hasRetryAfter bool, err error) {
+	ctx, apiClient := dogApiClient.getDDApiClient(ctx)
 
 	var pageAttribs *datadog.LogsListRequestPage
 	if *pageCursor == "" {
@@ -179,11 +329,15 @@ func fetchLogPage(before, now time.Time, pageCursor *string) (isDone bool, curso
 			// logging resp causes error: "json: unsupported type: func() (io.ReadCloser, error)"
 			//zap.Any("http response", r),
 		)
-		dump, errDump := httputil.DumpResponse(r, true)
-		if errDump != nil {
-			return
+		retryable = isRetryableFetchErr(r, err)
+		if r != nil {
+			retryAfter, hasRetryAfter = fetchRetryAfter(r.Header.Get("Retry-After"))
+			dump, errDump := httputil.DumpResponse(r, true)
+			if errDump != nil {
+				return
+			}
+			logger.Error("datadog api http response", zap.String("r dump", string(dump)))
 		}
-		logger.Error("datadog api http response", zap.String("r dump", string(dump)))
 		return
 	}
 	fetchDuration = time.Since(fetchStart)
@@ -207,6 +361,7 @@ func fetchLogPage(before, now time.Time, pageCursor *string) (isDone bool, curso
 	case datadog.LOGSAGGREGATERESPONSESTATUS_TIMEOUT:
 		logger.Debug("status", zap.Any("status", status))
 		err = fmt.Errorf("timeout getting scoring page. %+v", status)
+		retryable = true
 		return
 	case datadog.LOGSAGGREGATERESPONSESTATUS_DONE:
 		isDone = true
@@ -289,49 +444,148 @@ type ddLog struct {
 	Fields extraFields
 }
 
-// ChaseTail will loop every given interval, polling dataDog for new scoring data
-func ChaseTail(pollDb db.IDBPoll, scoreDb db.IScoreDB, seconds time.Duration, processScoringMessage func(scoreDb db.IScoreDB, now time.Time, msg *types.ScoringMessage) (pollErr error)) (quit chan bool, errChan chan error) {
+// PollHandle controls a ChaseTail polling loop started in its own goroutine.
+type PollHandle struct {
+	cancel  context.CancelFunc
+	done    chan struct{}
+	ErrChan chan error
+
+	mu          sync.RWMutex
+	lastSuccess time.Time
+	cursor      time.Time
+}
+
+// Stop cancels the polling loop and waits for its goroutine to drain, or for ctx to expire,
+// whichever comes first.
+func (h *PollHandle) Stop(ctx context.Context) (err error) {
+	h.cancel()
+	select {
+	case <-h.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// PollHealth is a point-in-time snapshot of a PollHandle's progress, for a caller that wants it
+// in process (e.g. an HTTP health handler) rather than scraping bbash_poll_last_success_timestamp_seconds.
+type PollHealth struct {
+	// LastSuccess is when the poll loop last completed a tick (fetch + process) without error.
+	LastSuccess time.Time
+	// Cursor is the EnvBaseTime high-water mark (see pollTheDog) left by that successful tick -
+	// the point scoring events have been fully ingested up to.
+	Cursor time.Time
+}
+
+// Health returns the most recently recorded PollHealth; the zero value if no tick has yet
+// succeeded.
+func (h *PollHandle) Health() PollHealth {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return PollHealth{LastSuccess: h.lastSuccess, Cursor: h.cursor}
+}
+
+// recordSuccess updates the snapshot Health returns; called once per successfully processed tick.
+func (h *PollHandle) recordSuccess(now, cursor time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.lastSuccess = now
+	h.cursor = cursor
+}
+
+// ChaseTail will loop every given interval, polling source for new scoring data. Scoring
+// messages read in each tick are coalesced per participant by a BatchProcessor (configured with
+// batchFlushInterval and batchMaxSize) before being handed to processScoringMessage, so a burst
+// of events for the same participant produces a single score update per tick. The loop, and the
+// in-flight fetch/processing it is running, are cancelled by the returned PollHandle's Stop
+// method, or by cancelling ctx directly.
+//
+// elector guards each tick's poll body against concurrent replicas: a tick is skipped entirely
+// (no fetch, no processing) unless elector.Acquire reports this instance holds the lease. Pass
+// leader.Always(instance) to run unconditionally, e.g. in a single-replica deployment or a test.
+//
+// fetchDeadline bounds how long a single page of source.Fetch may run before its context is
+// cancelled (see fetchDeadlineTimer); a non-positive value disables the deadline.
+//
+// processDeadline bounds how long a single processScoringMessage invocation may run before
+// processLogs gives up on it and dead-letters it as timed out, the same watchdog role
+// fetchDeadline plays for a single page fetch; a non-positive value disables the deadline.
+//
+// scoringEventTTL bounds how long a types.ScoringEventStruct may sit in ScoringEventPending or
+// ScoringEventValidated before expireScoringEvents moves it to ScoringEventExpired, the same tick
+// retryDeadLetters runs at; a non-positive value disables the sweep.
+func ChaseTail(ctx context.Context, source ScoringSource, pollDb db.IDBPoll, scoreDb db.IScoreDB, seconds, batchFlushInterval time.Duration, batchMaxSize int, fetchDeadline, processDeadline, scoringEventTTL time.Duration, elector leader.Elector, processScoringMessage func(scoreDb db.IScoreDB, now time.Time, msg *types.ScoringMessage) (pollErr error)) (handle *PollHandle) {
 	logger = pollDb.GetLogger()
 	logger.Info("poll ticker starting", zap.Duration("chase tail seconds", seconds))
 	ticker := time.NewTicker(seconds * time.Second)
-	quit = make(chan bool)
+	batch := NewBatchProcessor(batchFlushInterval, batchMaxSize)
+	seen := newSeenLogs(0)
+
+	ctx, cancel := context.WithCancel(ctx)
+	handle = &PollHandle{cancel: cancel, done: make(chan struct{})}
 
 	const errBufferSize = 100
-	errChan = make(chan error, errBufferSize)
+	handle.ErrChan = make(chan error, errBufferSize)
 	var errCount int
 	priorPollTime := time.Now()
 	go func() {
+		defer close(handle.done)
+		defer elector.Release(context.Background())
 		var pollErr error
 		for {
 			select {
 			case <-ticker.C:
-				now := time.Now()
-				var logs []ddLog
-				logs, pollErr = pollTheDog(pollDb, priorPollTime, now)
+				held, electErr := elector.Acquire(ctx)
+				if electErr != nil {
+					logger.Error("error acquiring poll leader lease", zap.Error(electErr))
+					continue
+				}
+				if !held {
+					logger.Debug("poll leader lease held elsewhere, skipping tick")
+					continue
+				}
+
+				cycleStart := time.Now()
+				now := cycleStart
+				var events []ScoringEvent
+				events, pollErr = pollTheDog(ctx, source, pollDb, elector.Instance(), priorPollTime, now, fetchDeadline)
 				if pollErr != nil {
 					logger.Error("error in polling chase", zap.Error(pollErr))
 					errCount++
 					if errCount < errBufferSize {
-						errChan <- pollErr
+						handle.ErrChan <- pollErr
 					}
+					metrics.PollCycleDuration.Observe(time.Since(cycleStart).Seconds())
 					continue // continue allows polling to keep running when errors occur
 				}
 				// track actual poll time to avoid db write oddness
 				priorPollTime = now
 
-				pollErr = processLogs(scoreDb, logs, now, processScoringMessage)
+				pollErr = processLogs(ctx, pollDb, scoreDb, events, now, batch, seen, processDeadline, processScoringMessage)
 				if pollErr != nil {
 					logger.Error("error in process logs chase", zap.Error(pollErr))
 					errCount++
 					if errCount < errBufferSize {
-						errChan <- pollErr
+						handle.ErrChan <- pollErr
 					}
+					metrics.PollCycleDuration.Observe(time.Since(cycleStart).Seconds())
 					continue // continue allows polling to keep running when errors occur
 				}
-			case <-quit:
+
+				cursor := priorPollTime
+				if eventCount := len(events); eventCount > 0 {
+					cursor = events[eventCount-1].EnvBaseTime
+				}
+				handle.recordSuccess(now, cursor)
+				metrics.PollLastSuccessTimestamp.Set(float64(now.Unix()))
+
+				retryDeadLetters(pollDb, scoreDb, now, processDeadline, processScoringMessage)
+				expireScoringEvents(scoreDb, now, scoringEventTTL)
+				metrics.PollCycleDuration.Observe(time.Since(cycleStart).Seconds())
+			case <-ctx.Done():
 				ticker.Stop()
-				logger.Info("poll ticker stopped", zap.Error(pollErr))
-				errChan <- pollErr
+				logger.Info("poll ticker stopped", zap.Error(ctx.Err()))
+				handle.ErrChan <- pollErr
 				return
 			}
 		}
@@ -339,13 +593,150 @@ func ChaseTail(pollDb db.IDBPoll, scoreDb db.IScoreDB, seconds time.Duration, pr
 	return
 }
 
-func processLogs(scoreDb db.IScoreDB, logs []ddLog, nowPoll time.Time, processScoringMessage func(scoreDb db.IScoreDB, now time.Time, msg *types.ScoringMessage) (err error)) (err error) {
-	for _, log := range logs {
-		msg := log.Fields.scoringMessage
-		err = processScoringMessage(scoreDb, nowPoll, &msg)
-		if err != nil {
+func processLogs(ctx context.Context, pollDb db.IDBPoll, scoreDb db.IScoreDB, events []ScoringEvent, nowPoll time.Time, batch *BatchProcessor, seen *seenLogs, processDeadline time.Duration, processScoringMessage func(scoreDb db.IScoreDB, now time.Time, msg *types.ScoringMessage) (err error)) (err error) {
+	for _, event := range events {
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
 			return
+		default:
+		}
+
+		if seen.Contains(event.ID) {
+			logger.Debug("skipping already-seen log", zap.String("id", event.ID))
+			continue
+		}
+		seen.Add(event.ID)
+
+		merged, envBaseTime, shouldFlush := batch.Add(event.Message, event.EnvBaseTime)
+		if !shouldFlush {
+			continue
+		}
+		if procErr := processWithDeadline(processDeadline, scoreDb, nowPoll, &merged, processScoringMessage); procErr != nil {
+			deadLetter(pollDb, merged, envBaseTime, procErr)
+			batch.Remove(merged)
+			continue
+		}
+		batch.Remove(merged)
+	}
+
+	// the poll tick is the outer edge of the batching window: flush every participant still
+	// holding an open batch so at most one net score update per participant leaves this tick.
+	for _, merged := range batch.Flush() {
+		merged := merged
+		if procErr := processWithDeadline(processDeadline, scoreDb, nowPoll, &merged, processScoringMessage); procErr != nil {
+			deadLetter(pollDb, merged, nowPoll, procErr)
 		}
 	}
 	return
 }
+
+// processWithDeadline runs processScoringMessage on its own goroutine and waits up to deadline for
+// it to finish, the way fetchDeadlineTimer bounds a single page fetch. processScoringMessage's
+// signature carries no context (it ultimately runs plain database/sql calls via IScoreDB, which
+// doesn't accept one either), so a timed-out call isn't cancelled, only abandoned: its goroutine is
+// left to finish in the background and its result discarded. A non-positive deadline disables the
+// bound and calls processScoringMessage directly.
+func processWithDeadline(deadline time.Duration, scoreDb db.IScoreDB, now time.Time, msg *types.ScoringMessage, processScoringMessage func(scoreDb db.IScoreDB, now time.Time, msg *types.ScoringMessage) (err error)) (err error) {
+	if deadline <= 0 {
+		return processScoringMessage(scoreDb, now, msg)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- processScoringMessage(scoreDb, now, msg)
+	}()
+
+	select {
+	case err = <-done:
+		return
+	case <-time.After(deadline):
+		return fmt.Errorf("processScoringMessage exceeded process deadline of %s", deadline)
+	}
+}
+
+// deadLetter records a ScoringMessage that processScoringMessage failed to apply, so a single bad
+// message doesn't block the rest of the poll tick it arrived in. A failure to record it is logged,
+// not propagated, since the scoring message has already been dropped from this tick's batch either way.
+func deadLetter(pollDb db.IDBPoll, msg types.ScoringMessage, envBaseTime time.Time, processErr error) {
+	metrics.PollErrorsTotal.WithLabelValues("process").Inc()
+	now := time.Now()
+	entry := types.DeadLetterEntry{
+		Message:     msg,
+		EnvBaseTime: envBaseTime,
+		Error:       processErr.Error(),
+		CreatedOn:   now,
+		LastAttempt: now,
+	}
+	if err := pollDb.InsertDeadLetter(&entry); err != nil {
+		logger.Error("error inserting dead letter", zap.Error(err), zap.Error(processErr))
+	}
+}
+
+// deadLetterMaxRetries bounds how many times retryDeadLetters will retry a single entry before
+// leaving it for an operator to inspect or discard via the dead letter HTTP endpoints.
+const deadLetterMaxRetries = 8
+
+// deadLetterBaseBackoff is the backoff unit retryDeadLetters doubles per retry, so a
+// repeatedly-failing entry backs off exponentially instead of being retried every tick.
+const deadLetterBaseBackoff = 30 * time.Second
+
+// deadLetterRetriesPerTick bounds how many dead letters are replayed in a single poll tick, so a
+// large backlog can't starve the tick of time for fetching and processing fresh scoring events.
+const deadLetterRetriesPerTick = 10
+
+// retryDeadLetters replays a bounded number of due dead letters through processScoringMessage,
+// backing each entry off exponentially by its retry count, and gives up retrying (but does not
+// discard) an entry once it has failed deadLetterMaxRetries times.
+func retryDeadLetters(pollDb db.IDBPoll, scoreDb db.IScoreDB, now time.Time, processDeadline time.Duration, processScoringMessage func(scoreDb db.IScoreDB, now time.Time, msg *types.ScoringMessage) (err error)) {
+	entries, err := pollDb.SelectDeadLetters()
+	if err != nil {
+		logger.Error("error selecting dead letters to retry", zap.Error(err))
+		return
+	}
+
+	retried := 0
+	for _, entry := range entries {
+		if retried >= deadLetterRetriesPerTick {
+			return
+		}
+		if entry.RetryCount >= deadLetterMaxRetries {
+			continue
+		}
+		backoff := deadLetterBaseBackoff * time.Duration(1<<uint(entry.RetryCount))
+		if now.Sub(entry.LastAttempt) < backoff {
+			continue
+		}
+		retried++
+
+		entry := entry
+		if procErr := processWithDeadline(processDeadline, scoreDb, now, &entry.Message, processScoringMessage); procErr != nil {
+			if err = pollDb.UpdateDeadLetterRetry(entry.Id, now, procErr); err != nil {
+				logger.Error("error updating dead letter retry", zap.Error(err))
+			}
+			continue
+		}
+		if err = pollDb.DeleteDeadLetter(entry.Id); err != nil {
+			logger.Error("error deleting replayed dead letter", zap.Error(err))
+		}
+	}
+}
+
+// expireScoringEvents moves every types.ScoringEventStruct stuck in ScoringEventPending or
+// ScoringEventValidated since before now.Add(-ttl) to ScoringEventExpired, so a delivery that
+// crashed between validating and scoring doesn't permanently block its dedup ID from ever being
+// retried under a fresh event. A non-positive ttl disables the sweep, the same convention
+// fetchDeadline/processDeadline use.
+func expireScoringEvents(scoreDb db.IScoreDB, now time.Time, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+	expired, err := scoreDb.ExpireStuckScoringEvents(ttl, now)
+	if err != nil {
+		logger.Error("error expiring stuck scoring events", zap.Error(err))
+		return
+	}
+	if expired > 0 {
+		logger.Info("expired stuck scoring events", zap.Int64("count", expired))
+	}
+}
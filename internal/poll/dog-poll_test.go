@@ -26,7 +26,12 @@ import (
 	"fmt"
 	"github.com/DataDog/datadog-api-client-go/api/v2/datadog"
 	"github.com/joho/godotenv"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/sonatype-nexus-community/bbash/internal/db"
+	"github.com/sonatype-nexus-community/bbash/internal/leader"
+	"github.com/sonatype-nexus-community/bbash/internal/metrics"
+	"github.com/sonatype-nexus-community/bbash/internal/mocks"
+	waitutil "github.com/sonatype-nexus-community/bbash/internal/testutil"
 	"github.com/sonatype-nexus-community/bbash/internal/types"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap/zaptest"
@@ -36,6 +41,7 @@ import (
 	"net/url"
 	"os"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -46,7 +52,7 @@ type MockDogApiClient struct {
 
 var _ IDogApiClient = (*MockDogApiClient)(nil)
 
-func (c *MockDogApiClient) getDDApiClient() (ctx context.Context, apiClient *datadog.APIClient) {
+func (c *MockDogApiClient) getDDApiClient(ctx context.Context) (_ context.Context, apiClient *datadog.APIClient) {
 	configuration := datadog.NewConfiguration()
 	configuration.Servers = datadog.ServerConfigurations{
 		datadog.ServerConfiguration{
@@ -65,9 +71,7 @@ func (c *MockDogApiClient) getDDApiClient() (ctx context.Context, apiClient *dat
 		},
 	}
 	apiClient = datadog.NewAPIClient(configuration)
-
-	ctx = context.Background()
-	return
+	return ctx, apiClient
 }
 
 func setupMockDDogApiClient(mockUrl *url.URL) (closeApiClient func()) {
@@ -82,8 +86,24 @@ func setupMockDDogApiClient(mockUrl *url.URL) (closeApiClient func()) {
 	return
 }
 
+// setupFastFetchRetry shrinks fetchLogPage's retry attempts and backoff so tests that exercise
+// retryable failures don't wait out the real 500ms-30s delays, restoring both on test cleanup.
+func setupFastFetchRetry(t *testing.T, maxAttempts int) {
+	origMaxAttempts := fetchRetryMaxAttempts
+	origBaseDelay := fetchRetryBaseDelay
+	origCapDelay := fetchRetryCapDelay
+	fetchRetryMaxAttempts = maxAttempts
+	fetchRetryBaseDelay = time.Millisecond
+	fetchRetryCapDelay = 10 * time.Millisecond
+	t.Cleanup(func() {
+		fetchRetryMaxAttempts = origMaxAttempts
+		fetchRetryBaseDelay = origBaseDelay
+		fetchRetryCapDelay = origCapDelay
+	})
+}
+
 func TestGetDDApiClientReal(t *testing.T) {
-	contextReal, clientReal := dogApiClient.getDDApiClient()
+	contextReal, clientReal := dogApiClient.getDDApiClient(context.Background())
 	assert.NotNil(t, contextReal)
 	assert.Equal(t, 3, len(clientReal.GetConfig().Servers))
 	assert.Equal(t, "https://{subdomain}.{site}", clientReal.GetConfig().Servers[0].URL)
@@ -108,7 +128,7 @@ func TestGetDDApiClientRealHasSomeScoresInPastWeek(t *testing.T) {
 	hoursDuration := time.Hour * -168 // one week in the past
 	before := now.Add(hoursDuration)
 
-	isDone, pageCursor, logPage, _, err = fetchLogPage(before, now, &pageCursor)
+	isDone, pageCursor, logPage, _, err = fetchLogPage(context.Background(), before, now, &pageCursor)
 	foundInfo := fmt.Sprintf("found logCount: %d in the past: %v", len(logPage), hoursDuration)
 	fmt.Println(foundInfo)
 
@@ -120,9 +140,12 @@ func TestGetDDApiClientRealHasSomeScoresInPastWeek(t *testing.T) {
 
 func TestFetchLogPagesErrorMissingKey(t *testing.T) {
 	logger = zaptest.NewLogger(t)
+	setupFastFetchRetry(t, 3)
 
+	var attempts int32
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		assert.Equal(t, http.MethodPost, r.Method)
+		atomic.AddInt32(&attempts, 1)
 		w.WriteHeader(http.StatusInternalServerError)
 	}))
 	defer ts.Close()
@@ -136,12 +159,139 @@ func TestFetchLogPagesErrorMissingKey(t *testing.T) {
 	pageCursor := ""
 	var logPage []ddLog
 
-	isDone, cursor, logPage, _, err := fetchLogPage(now, now, &pageCursor)
+	isDone, cursor, logPage, _, err := fetchLogPage(context.Background(), now, now, &pageCursor)
 	assert.False(t, isDone)
 	assert.Equal(t, "", cursor)
 	assert.Equal(t, ([]ddLog)(nil), logPage)
 	//assert.EqualError(t, err, "403 Forbidden")
 	assert.EqualError(t, err, "500 Internal Server Error")
+	// a 500 is retryable, so fetchLogPage should have exhausted every attempt before giving up
+	assert.Equal(t, int32(3), atomic.LoadInt32(&attempts))
+}
+
+// TestFetchLogPagesRetriesThenSucceeds covers the request's "500 twice then 200" scenario: a
+// retryable failure should be invisible to the caller once a later attempt succeeds.
+func TestFetchLogPagesRetriesThenSucceeds(t *testing.T) {
+	logger = zaptest.NewLogger(t)
+	setupFastFetchRetry(t, 5)
+
+	var attempts int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		if atomic.AddInt32(&attempts, 1) <= 2 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		status := datadog.LOGSAGGREGATERESPONSESTATUS_DONE
+		resp := datadog.LogsListResponse{
+			Meta: &datadog.LogsResponseMetadata{
+				Status: &status,
+			},
+		}
+		jsonResp, err := json.Marshal(resp)
+		assert.NoError(t, err)
+		_, _ = w.Write(jsonResp)
+	}))
+	defer ts.Close()
+	urlTs, err := url.Parse(ts.URL)
+	assert.NoError(t, err)
+
+	closeApiClient := setupMockDDogApiClient(urlTs)
+	defer closeApiClient()
+
+	now := time.Now()
+	pageCursor := ""
+	var logPage []ddLog
+
+	isDone, cursor, logPage, _, err := fetchLogPage(context.Background(), now, now, &pageCursor)
+	assert.NoError(t, err)
+	assert.True(t, isDone)
+	assert.Equal(t, "", cursor)
+	assert.Equal(t, ([]ddLog)(nil), logPage)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&attempts))
+}
+
+// TestFetchLogPagesHonorsRetryAfter asserts a Retry-After response header overrides fetchLogPage's
+// computed backoff for the next attempt.
+func TestFetchLogPagesHonorsRetryAfter(t *testing.T) {
+	logger = zaptest.NewLogger(t)
+	// leave fetchRetryBaseDelay/CapDelay at their real values so a pass here can only be
+	// explained by honoring Retry-After, not by the shrunk-for-speed backoff from other tests
+	setupFastFetchRetry(t, 2)
+	fetchRetryBaseDelay = time.Minute
+	fetchRetryCapDelay = time.Hour
+
+	var attempts int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		status := datadog.LOGSAGGREGATERESPONSESTATUS_DONE
+		resp := datadog.LogsListResponse{
+			Meta: &datadog.LogsResponseMetadata{
+				Status: &status,
+			},
+		}
+		jsonResp, err := json.Marshal(resp)
+		assert.NoError(t, err)
+		_, _ = w.Write(jsonResp)
+	}))
+	defer ts.Close()
+	urlTs, err := url.Parse(ts.URL)
+	assert.NoError(t, err)
+
+	closeApiClient := setupMockDDogApiClient(urlTs)
+	defer closeApiClient()
+
+	now := time.Now()
+	pageCursor := ""
+	var logPage []ddLog
+
+	start := time.Now()
+	isDone, _, logPage, _, err := fetchLogPage(context.Background(), now, now, &pageCursor)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.NoError(t, err)
+	assert.True(t, isDone)
+	assert.Equal(t, ([]ddLog)(nil), logPage)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&attempts))
+}
+
+// TestFetchLogPagesRetryRespectsContextCancel asserts a cancelled ctx preempts an in-progress
+// backoff sleep instead of waiting it out.
+func TestFetchLogPagesRetryRespectsContextCancel(t *testing.T) {
+	logger = zaptest.NewLogger(t)
+	setupFastFetchRetry(t, 5)
+	fetchRetryBaseDelay = time.Minute
+	fetchRetryCapDelay = time.Hour
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+	urlTs, err := url.Parse(ts.URL)
+	assert.NoError(t, err)
+
+	closeApiClient := setupMockDDogApiClient(urlTs)
+	defer closeApiClient()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+
+	now := time.Now()
+	pageCursor := ""
+
+	start := time.Now()
+	_, _, _, _, err = fetchLogPage(ctx, now, now, &pageCursor)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.EqualError(t, err, context.Canceled.Error())
 }
 
 func TestFetchLogPagesMetaWarnings(t *testing.T) {
@@ -157,7 +307,7 @@ func TestFetchLogPagesMetaWarnings(t *testing.T) {
 		}
 		resp := datadog.LogsListResponse{
 			Meta: &datadog.LogsResponseMetadata{
-				Warnings: &[]datadog.LogsWarning{warnings},
+				Warnings: []datadog.LogsWarning{warnings},
 			},
 		}
 		jsonWarnings, err := json.Marshal(resp)
@@ -175,7 +325,7 @@ func TestFetchLogPagesMetaWarnings(t *testing.T) {
 	pageCursor := ""
 	var logPage []ddLog
 
-	isDone, cursor, logPage, _, err := fetchLogPage(now, now, &pageCursor)
+	isDone, cursor, logPage, _, err := fetchLogPage(context.Background(), now, now, &pageCursor)
 	assert.False(t, isDone)
 	assert.Equal(t, "", cursor)
 	assert.Equal(t, ([]ddLog)(nil), logPage)
@@ -184,6 +334,7 @@ func TestFetchLogPagesMetaWarnings(t *testing.T) {
 
 func TestFetchLogPagesMetaStatusTimeout(t *testing.T) {
 	logger = zaptest.NewLogger(t)
+	setupFastFetchRetry(t, 2)
 
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		assert.Equal(t, http.MethodPost, r.Method)
@@ -210,7 +361,7 @@ func TestFetchLogPagesMetaStatusTimeout(t *testing.T) {
 	pageCursor := ""
 	var logPage []ddLog
 
-	isDone, cursor, logPage, _, err := fetchLogPage(now, now, &pageCursor)
+	isDone, cursor, logPage, _, err := fetchLogPage(context.Background(), now, now, &pageCursor)
 	assert.False(t, isDone)
 	assert.Equal(t, "", cursor)
 	assert.Equal(t, ([]ddLog)(nil), logPage)
@@ -245,7 +396,7 @@ func TestFetchLogPagesMetaStatusDone(t *testing.T) {
 	pageCursor := ""
 	var logPage []ddLog
 
-	isDone, cursor, logPage, fetchDuration, err := fetchLogPage(now, now, &pageCursor)
+	isDone, cursor, logPage, fetchDuration, err := fetchLogPage(context.Background(), now, now, &pageCursor)
 	assert.True(t, isDone)
 	assert.Equal(t, "", cursor)
 	assert.Equal(t, ([]ddLog)(nil), logPage)
@@ -284,7 +435,7 @@ func TestFetchLogPagesMetaPageHasAfter(t *testing.T) {
 	pageCursor := ""
 	var logPage []ddLog
 
-	isDone, cursor, logPage, _, err := fetchLogPage(now, now, &pageCursor)
+	isDone, cursor, logPage, _, err := fetchLogPage(context.Background(), now, now, &pageCursor)
 	assert.False(t, isDone)
 	assert.Equal(t, after, cursor)
 	assert.Equal(t, ([]ddLog)(nil), logPage)
@@ -314,7 +465,7 @@ func TestFetchLogPagesMetaPageNoAfter(t *testing.T) {
 	pageCursor := ""
 	var logPage []ddLog
 
-	isDone, cursor, logPage, _, err := fetchLogPage(now, now, &pageCursor)
+	isDone, cursor, logPage, _, err := fetchLogPage(context.Background(), now, now, &pageCursor)
 	assert.True(t, isDone)
 	assert.Equal(t, "", cursor)
 	assert.Equal(t, ([]ddLog)(nil), logPage)
@@ -352,13 +503,91 @@ func TestFetchLogPagesWithCursor(t *testing.T) {
 	now := time.Now()
 	var logPage []ddLog
 
-	isDone, cursor, logPage, _, err := fetchLogPage(now, now, &pageCursor)
+	isDone, cursor, logPage, _, err := fetchLogPage(context.Background(), now, now, &pageCursor)
 	assert.True(t, isDone)
 	assert.Equal(t, "", cursor)
 	assert.Equal(t, ([]ddLog)(nil), logPage)
 	assert.NoError(t, err)
 }
 
+func TestFetchDeadlineTimerDisabledByNonPositiveDeadline(t *testing.T) {
+	child, timer := newFetchDeadlineTimer(context.Background(), 0)
+	defer timer.stop()
+
+	select {
+	case <-child.Done():
+		t.Fatal("child context should not be cancelled without a positive deadline")
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestFetchDeadlineTimerCancelsChildWhenArmed(t *testing.T) {
+	child, timer := newFetchDeadlineTimer(context.Background(), 10*time.Millisecond)
+	defer timer.stop()
+
+	select {
+	case <-child.Done():
+		assert.EqualError(t, child.Err(), context.Canceled.Error())
+	case <-time.After(time.Second):
+		t.Fatal("child context was never cancelled by the deadline")
+	}
+}
+
+func TestFetchDeadlineTimerResetPostponesCancellation(t *testing.T) {
+	child, timer := newFetchDeadlineTimer(context.Background(), 30*time.Millisecond)
+	defer timer.stop()
+
+	time.Sleep(20 * time.Millisecond)
+	timer.reset(30 * time.Millisecond)
+
+	select {
+	case <-child.Done():
+		t.Fatal("reset should have postponed the deadline")
+	case <-time.After(15 * time.Millisecond):
+	}
+}
+
+func TestFetchDeadlineTimerStopPreventsLateCancellation(t *testing.T) {
+	child, timer := newFetchDeadlineTimer(context.Background(), 10*time.Millisecond)
+	timer.stop()
+
+	select {
+	case <-child.Done():
+		// stop cancels the child itself, so Done() fires immediately - this is expected,
+		// unlike the timer firing asynchronously later.
+	case <-time.After(time.Second):
+		t.Fatal("stop should cancel the child context immediately")
+	}
+}
+
+func TestProcessWithDeadlineDisabledByNonPositiveDeadline(t *testing.T) {
+	called := false
+	err := processWithDeadline(0, nil, time.Now(), nil, func(scoreDb db.IScoreDB, now time.Time, msg *types.ScoringMessage) (err error) {
+		called = true
+		time.Sleep(20 * time.Millisecond)
+		return
+	})
+	assert.NoError(t, err)
+	assert.True(t, called)
+}
+
+func TestProcessWithDeadlineReturnsHandlerResult(t *testing.T) {
+	forcedError := fmt.Errorf("forced process error")
+	err := processWithDeadline(time.Second, nil, time.Now(), nil, func(scoreDb db.IScoreDB, now time.Time, msg *types.ScoringMessage) (err error) {
+		return forcedError
+	})
+	assert.EqualError(t, err, forcedError.Error())
+}
+
+func TestProcessWithDeadlineTimesOutSlowHandler(t *testing.T) {
+	err := processWithDeadline(10*time.Millisecond, nil, time.Now(), nil, func(scoreDb db.IScoreDB, now time.Time, msg *types.ScoringMessage) (err error) {
+		time.Sleep(time.Second)
+		return
+	})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "process deadline")
+}
+
 func TestProcessResponseDataEmpty(t *testing.T) {
 	logs, err := processResponseData([]datadog.Log{})
 	assert.Equal(t, 0, len(logs))
@@ -575,9 +804,9 @@ func TestPollTheDogDBError(t *testing.T) {
 	db.SetupMockPollSelectForcedError(mock, forcedError, poll.Id)
 
 	now := time.Now()
-	logs, err := pollTheDog(dbPoll, now, now)
+	logs, err := pollTheDog(context.Background(), NewDatadogSource(), dbPoll, "test-instance", now, now, 0)
 	assert.EqualError(t, err, forcedError.Error())
-	assert.Equal(t, ([]ddLog)(nil), logs)
+	assert.Equal(t, ([]ScoringEvent)(nil), logs)
 }
 
 func TestPollTheDogPollError(t *testing.T) {
@@ -601,9 +830,46 @@ func TestPollTheDogPollError(t *testing.T) {
 	closeApiClient := setupMockDDogApiClient(urlTs)
 	defer closeApiClient()
 
-	logs, err := pollTheDog(dbPoll, now, now)
+	logs, err := pollTheDog(context.Background(), NewDatadogSource(), dbPoll, "test-instance", now, now, 0)
 	assert.EqualError(t, err, "500 Internal Server Error")
-	assert.Equal(t, ([]ddLog)(nil), logs)
+	assert.Equal(t, ([]ScoringEvent)(nil), logs)
+}
+
+func TestPollTheDogFetchDeadlineAbortsSlowFetch(t *testing.T) {
+	logger = zaptest.NewLogger(t)
+
+	mock, dbPoll, closeDbFunc := db.SetupMockDBPoll(t)
+	defer closeDbFunc()
+
+	poll := dbPoll.NewPoll()
+	now := time.Now()
+	db.SetupMockPollSelect(mock, poll.Id, now)
+
+	// the handler never responds on its own; it only returns once the client gives up, proving
+	// the fetch deadline - not the handler - is what ends the request.
+	serverSawCancel := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		close(serverSawCancel)
+	}))
+	defer ts.Close()
+	urlTs, err := url.Parse(ts.URL)
+	assert.NoError(t, err)
+
+	closeApiClient := setupMockDDogApiClient(urlTs)
+	defer closeApiClient()
+
+	start := time.Now()
+	logs, err := pollTheDog(context.Background(), NewDatadogSource(), dbPoll, "test-instance", now, now, 50*time.Millisecond)
+	assert.Less(t, time.Since(start), 2*time.Second)
+	assert.Error(t, err)
+	assert.Equal(t, ([]ScoringEvent)(nil), logs)
+
+	select {
+	case <-serverSawCancel:
+	case <-time.After(time.Second):
+		t.Fatal("server handler's request was never cancelled by the fetch deadline")
+	}
 }
 
 func TestPollTheDogUsePriorPollTime(t *testing.T) {
@@ -634,9 +900,9 @@ func TestPollTheDogUsePriorPollTime(t *testing.T) {
 	closeApiClient := setupMockDDogApiClient(urlTs)
 	defer closeApiClient()
 
-	logs, err := pollTheDog(dbPoll, priorPollTime, now)
+	logs, err := pollTheDog(context.Background(), NewDatadogSource(), dbPoll, "test-instance", priorPollTime, now, 0)
 	assert.NoError(t, err)
-	assert.Equal(t, ([]ddLog)(nil), logs)
+	assert.Equal(t, ([]ScoringEvent)(nil), logs)
 }
 
 func TestPollTheDogOneLog(t *testing.T) {
@@ -656,7 +922,7 @@ func TestPollTheDogOneLog(t *testing.T) {
 		w.WriteHeader(http.StatusOK)
 
 		apiResp := datadog.LogsListResponse{
-			Data: &[]datadog.Log{
+			Data: []datadog.Log{
 				{
 					Id: &logId,
 					Attributes: &datadog.LogAttributes{
@@ -683,12 +949,11 @@ func TestPollTheDogOneLog(t *testing.T) {
 	closeApiClient := setupMockDDogApiClient(urlTs)
 	defer closeApiClient()
 
-	logs, err := pollTheDog(dbPoll, now, now)
+	logs, err := pollTheDog(context.Background(), NewDatadogSource(), dbPoll, "test-instance", now, now, 0)
 	assert.NoError(t, err)
 
 	assert.Equal(t, 1, len(logs))
-	assert.Equal(t, logId, logs[0].Id)
-	assert.Equal(t, eventSource, logs[0].Fields.scoringMessage.EventSource)
+	assert.Equal(t, eventSource, logs[0].Message.EventSource)
 }
 
 type MockScoreDB struct {
@@ -707,6 +972,12 @@ type MockScoreDB struct {
 	updateScoreParticipant *types.ParticipantStruct
 	updateScoreDelta       float64
 	updateScoreError       error
+
+	updateScoreCASParticipant *types.ParticipantStruct
+	updateScoreCASExpected    int
+	updateScoreCASDelta       float64
+	updateScoreCASSwapped     bool
+	updateScoreCASError       error
 }
 
 func (m MockScoreDB) GetDb() (db *sql.DB) {
@@ -746,16 +1017,80 @@ func (m MockScoreDB) UpdateParticipantScore(participant *types.ParticipantStruct
 	return m.updateScoreError
 }
 
+func (m MockScoreDB) UpdateParticipantScoreCAS(participant *types.ParticipantStruct, expectedScore int, delta float64) (swapped bool, err error) {
+	if m.assertParameters {
+		assert.Equal(m.t, m.updateScoreCASParticipant, participant)
+		assert.Equal(m.t, m.updateScoreCASExpected, expectedScore)
+		assert.Equal(m.t, m.updateScoreCASDelta, delta)
+	}
+	return m.updateScoreCASSwapped, m.updateScoreCASError
+}
+
+// ApplyScoringEvent is unused by this file's tests (processScoringMessage's ApplyScoringEvent call
+// site is exercised in server_test.go, not here), so it just reports success.
+func (m MockScoreDB) ApplyScoringEvent(participantToScore *types.ParticipantStruct, msg *types.ScoringMessage, newPoints, delta float64, expectedScore int) (swapped bool, err error) {
+	return true, nil
+}
+
+// EvaluateScoringPolicy is unused by this file's tests (processScoringMessage's scoring-policy
+// call site is exercised in server_test.go, not here), so it just reports an unrestricted policy.
+func (m MockScoreDB) EvaluateScoringPolicy(participantToScore *types.ParticipantStruct, msg *types.ScoringMessage) (allowed bool, reason string, err error) {
+	return true, "", nil
+}
+
+// InsertScoringEventWithHash is unused by this file's tests, so it just reports success.
+func (m MockScoreDB) InsertScoringEventWithHash(participantToScore *types.ParticipantStruct, msg *types.ScoringMessage, newPoints float64, commitSHA, diffHash string) (err error) {
+	return nil
+}
+
+// UpsertPendingScoringEvent always reports a fresh ScoringEventPending event: none of this file's
+// tests exercise the dedup short-circuit itself (that's covered in server_test.go), so a real
+// existing-event scenario is never set up here.
+func (m MockScoreDB) UpsertPendingScoringEvent(dedupId string) (event *types.ScoringEventStruct, err error) {
+	return &types.ScoringEventStruct{DedupId: dedupId, Status: types.ScoringEventPending}, nil
+}
+
+func (m MockScoreDB) UpdateScoringEventStatus(id string, from, to types.ScoringEventStatus, points float64, reason string) (updated bool, err error) {
+	return true, nil
+}
+
+func (m MockScoreDB) ExpireStuckScoringEvents(ttl time.Duration, now time.Time) (expired int64, err error) {
+	return 0, nil
+}
+
+// RevokeScoringEvent, IsScoringEventRevoked and ReplayParticipantScore are unused by this file's
+// tests, so they just report the "nothing revoked" case.
+func (m MockScoreDB) RevokeScoringEvent(campaignName, scpName, repoOwner, repoName string, pr int, reason, actor string) (err error) {
+	return nil
+}
+
+func (m MockScoreDB) IsScoringEventRevoked(campaignName, scpName, repoOwner, repoName string, pr int) (revoked bool, err error) {
+	return false, nil
+}
+
+func (m MockScoreDB) ReplayParticipantScore(participant *types.ParticipantStruct) (score int, err error) {
+	return participant.Score, nil
+}
+
+func (m MockScoreDB) SelectScoringEventsForParticipant(campaignName, scpName, loginName string) (events []types.ScoringLedgerEntryStruct, err error) {
+	return nil, nil
+}
+
 var _ db.IScoreDB = (*MockScoreDB)(nil)
 
 func TestProcessLogsZeroLogs(t *testing.T) {
-	assert.NoError(t, processLogs(nil, nil, time.Now(), nil))
+	assert.NoError(t, processLogs(context.Background(), nil, nil, nil, time.Now(), NewBatchProcessor(0, 0), newSeenLogs(0), 0, nil))
 }
 
 func TestProcessLogsOneWithError(t *testing.T) {
+	logger = zaptest.NewLogger(t)
+	mock, pollDb, closeDbFunc := db.SetupMockDBPoll(t)
+	defer closeDbFunc()
+	db.SetupMockInsertDeadLetter(mock, "theDeadLetterId")
+
 	scoreDb := createMockScoreDb(t)
 
-	logs := []ddLog{
+	logs := []ScoringEvent{
 		{},
 	}
 	now := time.Now()
@@ -767,14 +1102,15 @@ func TestProcessLogsOneWithError(t *testing.T) {
 		return forcedError
 	}
 
-	err := processLogs(scoreDb, logs, now, processScoringMessage)
-	assert.EqualError(t, forcedError, err.Error())
+	// a failing message is dead-lettered rather than aborting the rest of the tick
+	err := processLogs(context.Background(), pollDb, scoreDb, logs, now, NewBatchProcessor(0, 0), newSeenLogs(0), 0, processScoringMessage)
+	assert.NoError(t, err)
 }
 
 func TestProcessLogsOne(t *testing.T) {
 	scoreDb := createMockScoreDb(t)
 
-	logs := []ddLog{
+	logs := []ScoringEvent{
 		{},
 	}
 	now := time.Now()
@@ -785,8 +1121,30 @@ func TestProcessLogsOne(t *testing.T) {
 		return
 	}
 
-	err := processLogs(scoreDb, logs, now, processScoringMessage)
+	err := processLogs(context.Background(), nil, scoreDb, logs, now, NewBatchProcessor(0, 0), newSeenLogs(0), 0, processScoringMessage)
+	assert.NoError(t, err)
+}
+
+// TestProcessLogsDuplicateLogIdIsScoredOnce covers a poll window overlapping a prior one (clock
+// skew, a retried poll) and redelivering the same log: processLogs must hand it to
+// processScoringMessage only the first time.
+func TestProcessLogsDuplicateLogIdIsScoredOnce(t *testing.T) {
+	scoreDb := createMockScoreDb(t)
+
+	logs := []ScoringEvent{
+		{ID: "dupe-id"},
+		{ID: "dupe-id"},
+	}
+	now := time.Now()
+	processedCount := 0
+	processScoringMessage := func(scoreDbCalled db.IScoreDB, nowCalled time.Time, msgCalled *types.ScoringMessage) (err error) {
+		processedCount++
+		return
+	}
+
+	err := processLogs(context.Background(), nil, scoreDb, logs, now, NewBatchProcessor(0, 0), newSeenLogs(0), 0, processScoringMessage)
 	assert.NoError(t, err)
+	assert.Equal(t, 1, processedCount)
 }
 
 func TestChaseTailPollError(t *testing.T) {
@@ -799,15 +1157,18 @@ func TestChaseTailPollError(t *testing.T) {
 	forcedError := fmt.Errorf("forced poll db error")
 	db.SetupMockPollSelectForcedError(mock, forcedError, poll.Id)
 
+	errorsBefore := testutil.ToFloat64(metrics.PollErrorsTotal.WithLabelValues("poll_db"))
+
 	processScoringMessage := func(scoreDb db.IScoreDB, now time.Time, msg *types.ScoringMessage) (err error) {
 		assert.Fail(t, "this should never run")
 		return
 	}
 
-	quitChan, errChan := ChaseTail(dbPoll, createMockScoreDb(t), 1, processScoringMessage)
-	defer close(quitChan)
+	handle := ChaseTail(context.Background(), NewDatadogSource(), dbPoll, createMockScoreDb(t), 1, 0, 0, 0, 0, 0, leader.Always("test-instance"), processScoringMessage)
+	defer func() { assert.NoError(t, handle.Stop(context.Background())) }()
 
-	assert.EqualError(t, <-errChan, forcedError.Error())
+	assert.EqualError(t, <-handle.ErrChan, forcedError.Error())
+	assert.Greater(t, testutil.ToFloat64(metrics.PollErrorsTotal.WithLabelValues("poll_db")), errorsBefore)
 }
 
 func TestChaseTailQuit(t *testing.T) {
@@ -825,9 +1186,9 @@ func TestChaseTailQuit(t *testing.T) {
 		return
 	}
 
-	quitChan, errChan := ChaseTail(dbPoll, createMockScoreDb(t), 1, processScoringMessage)
-	close(quitChan)
-	assert.Nil(t, <-errChan)
+	handle := ChaseTail(context.Background(), NewDatadogSource(), dbPoll, createMockScoreDb(t), 1, 0, 0, 0, 0, 0, leader.Always("test-instance"), processScoringMessage)
+	assert.NoError(t, handle.Stop(context.Background()))
+	assert.Nil(t, <-handle.ErrChan)
 }
 
 func TestChaseTailProcessLogsError(t *testing.T) {
@@ -839,6 +1200,7 @@ func TestChaseTailProcessLogsError(t *testing.T) {
 	poll := dbPoll.NewPoll()
 	now := time.Now()
 	db.SetupMockPollSelectAndUpdateAnyUpdateTime(mock, poll.Id, now, 1)
+	db.SetupMockInsertDeadLetter(mock, "theDeadLetterId")
 
 	logId := "myLogId"
 	eventSource := "myEventSource"
@@ -847,7 +1209,7 @@ func TestChaseTailProcessLogsError(t *testing.T) {
 		w.WriteHeader(http.StatusOK)
 
 		apiResp := datadog.LogsListResponse{
-			Data: &[]datadog.Log{
+			Data: []datadog.Log{
 				{
 					Id: &logId,
 					Attributes: &datadog.LogAttributes{
@@ -874,22 +1236,29 @@ func TestChaseTailProcessLogsError(t *testing.T) {
 	closeApiClient := setupMockDDogApiClient(urlTs)
 	defer closeApiClient()
 
-	msgProcessed := false
+	scoreDb := new(mocks.IScoreDB)
+	scoreDb.On("SelectPriorScore", (*types.ParticipantStruct)(nil), (*types.ScoringMessage)(nil)).Return(float64(0)).Once()
+	scoreDb.On("UpdateParticipantScore", (*types.ParticipantStruct)(nil), float64(0)).Return(nil).Once()
+
+	var msgProcessed int32
 	forcedError := fmt.Errorf("forced process logs error")
+	processErrorsBefore := testutil.ToFloat64(metrics.PollErrorsTotal.WithLabelValues("process"))
 	processScoringMessage := func(scoreDb db.IScoreDB, now time.Time, msg *types.ScoringMessage) (err error) {
-		msgProcessed = true
 		scoreDb.SelectPriorScore(nil, nil)
 		assert.NoError(t, scoreDb.UpdateParticipantScore(nil, 0))
 		assert.Equal(t, eventSource, msg.EventSource)
 		err = forcedError
+		atomic.StoreInt32(&msgProcessed, 1)
 		return
 	}
 
-	quitChan, _ := ChaseTail(dbPoll, createMockScoreDb(t), 1, processScoringMessage)
+	// the failing message is dead-lettered; ChaseTail keeps running rather than surfacing the error
+	handle := ChaseTail(context.Background(), NewDatadogSource(), dbPoll, scoreDb, 1, 0, 0, 0, 0, 0, leader.Always("test-instance"), processScoringMessage)
 
-	time.Sleep(2 * time.Second)
-	close(quitChan)
-	assert.True(t, msgProcessed)
+	waitutil.WaitFor(t, 2*time.Second, func() bool { return atomic.LoadInt32(&msgProcessed) == 1 }, "scoring message was never processed")
+	assert.NoError(t, handle.Stop(context.Background()))
+	scoreDb.AssertExpectations(t)
+	assert.Greater(t, testutil.ToFloat64(metrics.PollErrorsTotal.WithLabelValues("process")), processErrorsBefore)
 }
 
 func TestChaseTailOneLog(t *testing.T) {
@@ -909,7 +1278,7 @@ func TestChaseTailOneLog(t *testing.T) {
 		w.WriteHeader(http.StatusOK)
 
 		apiResp := datadog.LogsListResponse{
-			Data: &[]datadog.Log{
+			Data: []datadog.Log{
 				{
 					Id: &logId,
 					Attributes: &datadog.LogAttributes{
@@ -936,20 +1305,28 @@ func TestChaseTailOneLog(t *testing.T) {
 	closeApiClient := setupMockDDogApiClient(urlTs)
 	defer closeApiClient()
 
-	msgProcessed := false
+	scoreDb := new(mocks.IScoreDB)
+	scoreDb.On("SelectPriorScore", (*types.ParticipantStruct)(nil), (*types.ScoringMessage)(nil)).Return(float64(0)).Once()
+	scoreDb.On("UpdateParticipantScore", (*types.ParticipantStruct)(nil), float64(0)).Return(nil).Once()
+
+	var msgProcessed int32
+	logsBefore := testutil.ToFloat64(metrics.PollLogsTotal)
 	processScoringMessage := func(scoreDb db.IScoreDB, now time.Time, msg *types.ScoringMessage) (err error) {
-		msgProcessed = true
 		scoreDb.SelectPriorScore(nil, nil)
 		assert.NoError(t, scoreDb.UpdateParticipantScore(nil, 0))
 		assert.Equal(t, eventSource, msg.EventSource)
+		atomic.StoreInt32(&msgProcessed, 1)
 		return
 	}
 
-	quitChan, _ := ChaseTail(dbPoll, createMockScoreDb(t), 1, processScoringMessage)
+	handle := ChaseTail(context.Background(), NewDatadogSource(), dbPoll, scoreDb, 1, 0, 0, 0, 0, 0, leader.Always("test-instance"), processScoringMessage)
 
-	time.Sleep(2 * time.Second)
-	close(quitChan)
-	assert.True(t, msgProcessed)
+	waitutil.WaitFor(t, 2*time.Second, func() bool { return atomic.LoadInt32(&msgProcessed) == 1 }, "scoring message was never processed")
+	assert.NoError(t, handle.Stop(context.Background()))
+	scoreDb.AssertExpectations(t)
+	assert.Greater(t, testutil.ToFloat64(metrics.PollLogsTotal), logsBefore)
+	health := handle.Health()
+	assert.False(t, health.LastSuccess.IsZero())
 }
 
 func TestChaseTailOneLogWithOptMap(t *testing.T) {
@@ -979,7 +1356,7 @@ func TestChaseTailOneLogWithOptMap(t *testing.T) {
 
 		logId := "myLogId"
 		apiResp := datadog.LogsListResponse{
-			Data: &[]datadog.Log{
+			Data: []datadog.Log{
 				{
 					Id: &logId,
 					Attributes: &datadog.LogAttributes{
@@ -1007,20 +1384,23 @@ func TestChaseTailOneLogWithOptMap(t *testing.T) {
 	closeApiClient := setupMockDDogApiClient(urlTs)
 	defer closeApiClient()
 
-	msgProcessed := false
+	scoreDb := new(mocks.IScoreDB)
+	scoreDb.On("SelectPriorScore", (*types.ParticipantStruct)(nil), (*types.ScoringMessage)(nil)).Return(float64(0)).Once()
+	scoreDb.On("UpdateParticipantScore", (*types.ParticipantStruct)(nil), float64(0)).Return(nil).Once()
+	var msgProcessed int32
 	processScoringMessage := func(scoreDb db.IScoreDB, now time.Time, msg *types.ScoringMessage) (err error) {
-		msgProcessed = true
 		scoreDb.SelectPriorScore(nil, nil)
 		assert.NoError(t, scoreDb.UpdateParticipantScore(nil, 0))
 		assert.Equal(t, eventSource, msg.EventSource)
+		atomic.StoreInt32(&msgProcessed, 1)
 		return
 	}
 
-	quitChan, _ := ChaseTail(dbPoll, createMockScoreDb(t), 1, processScoringMessage)
+	handle := ChaseTail(context.Background(), NewDatadogSource(), dbPoll, scoreDb, 1, 0, 0, 0, 0, 0, leader.Always("test-instance"), processScoringMessage)
 
-	time.Sleep(2 * time.Second)
-	close(quitChan)
-	assert.True(t, msgProcessed)
+	waitutil.WaitFor(t, 2*time.Second, func() bool { return atomic.LoadInt32(&msgProcessed) == 1 }, "scoring message was never processed")
+	assert.NoError(t, handle.Stop(context.Background()))
+	scoreDb.AssertExpectations(t)
 }
 
 //goland:noinspection GoUnusedFunction
@@ -1045,10 +1425,8 @@ func xxxTestChaseTailLive(t *testing.T) {
 		return
 	}
 
-	quitChan, errChan := ChaseTail(dbPoll, createMockScoreDb(t), 1, processScoringMessage)
-	//defer close(quitChan)
+	handle := ChaseTail(context.Background(), NewDatadogSource(), dbPoll, createMockScoreDb(t), 1, 0, 0, 0, 0, 0, leader.Always("test-instance"), processScoringMessage)
 
 	time.Sleep(3 * time.Second)
-	close(quitChan)
-	assert.Equal(t, nil, <-errChan)
+	assert.NoError(t, handle.Stop(context.Background()))
 }
@@ -0,0 +1,148 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package poll
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// MultiSource aggregates several named ScoringSources behind a single ScoringSource, so
+// pollTheDog/ChaseTail's single-source polling loop can acquire events from any mix of pluggable
+// sources without another signature change. Each sub-source pages independently: the cursor
+// MultiSource hands ChaseTail is a JSON-encoded map of sub-source name to that sub-source's own
+// cursor string.
+type MultiSource struct {
+	mu      sync.Mutex
+	sources map[string]ScoringSource
+	stopped map[string]bool
+}
+
+var _ ScoringSource = (*MultiSource)(nil)
+
+// NewMultiSource builds a MultiSource from a name->ScoringSource map. Names are how the admin
+// sources API (see server.go's listSources/stopSource/restartSource) addresses a sub-source.
+func NewMultiSource(sources map[string]ScoringSource) *MultiSource {
+	return &MultiSource{
+		sources: sources,
+		stopped: make(map[string]bool),
+	}
+}
+
+// SourceStatus reports one sub-source's admin state, as returned by MultiSource.List.
+type SourceStatus struct {
+	Name    string `json:"name"`
+	Stopped bool   `json:"stopped"`
+}
+
+// List returns every sub-source's name and whether it's currently stopped.
+func (m *MultiSource) List() (statuses []SourceStatus) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for name := range m.sources {
+		statuses = append(statuses, SourceStatus{Name: name, Stopped: m.stopped[name]})
+	}
+	return
+}
+
+// Stop marks name as stopped: Fetch skips it until a matching Restart.
+func (m *MultiSource) Stop(name string) (err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.sources[name]; !ok {
+		return fmt.Errorf("no such scoring source: %s", name)
+	}
+	m.stopped[name] = true
+	return
+}
+
+// Restart clears a prior Stop, so Fetch resumes polling name.
+func (m *MultiSource) Restart(name string) (err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.sources[name]; !ok {
+		return fmt.Errorf("no such scoring source: %s", name)
+	}
+	delete(m.stopped, name)
+	return
+}
+
+// Fetch polls every non-stopped sub-source once, merging their events. A sub-source that errors
+// is logged and treated as done for this round rather than failing the whole aggregate, so one
+// misconfigured or unreachable source doesn't block acquisition from the rest.
+func (m *MultiSource) Fetch(ctx context.Context, since, until time.Time, cursor string) (events []ScoringEvent, nextCursor string, done bool, err error) {
+	m.mu.Lock()
+	names := make([]string, 0, len(m.sources))
+	sources := make(map[string]ScoringSource, len(m.sources))
+	stopped := make(map[string]bool, len(m.stopped))
+	for name, source := range m.sources {
+		names = append(names, name)
+		sources[name] = source
+	}
+	for name, s := range m.stopped {
+		stopped[name] = s
+	}
+	m.mu.Unlock()
+
+	cursors := make(map[string]string)
+	if cursor != "" {
+		if err = json.Unmarshal([]byte(cursor), &cursors); err != nil {
+			return
+		}
+	}
+
+	nextCursors := make(map[string]string)
+	done = true
+	for _, name := range names {
+		if stopped[name] {
+			continue
+		}
+
+		page, subNext, subDone, fetchErr := sources[name].Fetch(ctx, since, until, cursors[name])
+		if fetchErr != nil {
+			logger.Error("scoring source fetch failed", zap.String("source", name), zap.Error(fetchErr))
+			continue
+		}
+
+		events = append(events, page...)
+		if !subDone {
+			done = false
+			nextCursors[name] = subNext
+		}
+	}
+
+	if !done {
+		var marshalled []byte
+		marshalled, err = json.Marshal(nextCursors)
+		if err != nil {
+			return
+		}
+		nextCursor = string(marshalled)
+	}
+	return
+}
@@ -0,0 +1,98 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package poll
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+)
+
+// stubSource is a minimal ScoringSource for exercising MultiSource without a real backend.
+type stubSource struct {
+	events []ScoringEvent
+	err    error
+}
+
+var _ ScoringSource = (*stubSource)(nil)
+
+func (s *stubSource) Fetch(_ context.Context, _, _ time.Time, _ string) (events []ScoringEvent, nextCursor string, done bool, err error) {
+	return s.events, "", true, s.err
+}
+
+func TestMultiSourceMergesEventsFromEverySource(t *testing.T) {
+	source := NewMultiSource(map[string]ScoringSource{
+		"a": &stubSource{events: []ScoringEvent{{Message: types.ScoringMessage{TriggerUser: "alice"}}}},
+		"b": &stubSource{events: []ScoringEvent{{Message: types.ScoringMessage{TriggerUser: "bob"}}}},
+	})
+
+	events, _, done, err := source.Fetch(context.Background(), time.Time{}, time.Time{}, "")
+	assert.NoError(t, err)
+	assert.True(t, done)
+	assert.Equal(t, 2, len(events))
+}
+
+func TestMultiSourceSkipsAndLogsAnErroringSource(t *testing.T) {
+	logger = zaptest.NewLogger(t)
+
+	source := NewMultiSource(map[string]ScoringSource{
+		"ok":  &stubSource{events: []ScoringEvent{{Message: types.ScoringMessage{TriggerUser: "alice"}}}},
+		"bad": &stubSource{err: fmt.Errorf("boom")},
+	})
+
+	events, _, done, err := source.Fetch(context.Background(), time.Time{}, time.Time{}, "")
+	assert.NoError(t, err)
+	assert.True(t, done)
+	assert.Equal(t, 1, len(events))
+	assert.Equal(t, "alice", events[0].Message.TriggerUser)
+}
+
+func TestMultiSourceStopSkipsThatSource(t *testing.T) {
+	source := NewMultiSource(map[string]ScoringSource{
+		"a": &stubSource{events: []ScoringEvent{{Message: types.ScoringMessage{TriggerUser: "alice"}}}},
+	})
+
+	assert.NoError(t, source.Stop("a"))
+
+	events, _, _, err := source.Fetch(context.Background(), time.Time{}, time.Time{}, "")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(events))
+
+	statuses := source.List()
+	assert.Equal(t, 1, len(statuses))
+	assert.Equal(t, "a", statuses[0].Name)
+	assert.True(t, statuses[0].Stopped)
+
+	assert.NoError(t, source.Restart("a"))
+	events, _, _, err = source.Fetch(context.Background(), time.Time{}, time.Time{}, "")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(events))
+}
+
+func TestMultiSourceStopUnknownSourceErrors(t *testing.T) {
+	source := NewMultiSource(map[string]ScoringSource{})
+	assert.Error(t, source.Stop("nope"))
+	assert.Error(t, source.Restart("nope"))
+}
@@ -0,0 +1,99 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package poll
+
+import "sync"
+
+// defaultSeenLogsCap bounds how many ScoringEvent IDs a seenLogs set retains before evicting the
+// oldest, so a long-running poller's memory doesn't grow without bound.
+const defaultSeenLogsCap = 10000
+
+// seenLogs is a thread-safe, capacity-bounded set of ScoringEvent IDs ChaseTail has already handed
+// to processScoringMessage, so a log delivered twice by an overlapping poll window (clock skew, a
+// retried poll, or a restart that replays the last Poll row) isn't scored twice. Eviction is FIFO by
+// insertion order, tracked alongside the map rather than with a full LRU, since membership here only
+// needs to outlive the overlap between two adjacent poll windows, not forever.
+type seenLogs struct {
+	mu    sync.RWMutex
+	cap   int
+	ids   map[string]struct{}
+	order []string
+}
+
+// newSeenLogs builds a seenLogs set retaining at most capacity IDs; a non-positive capacity falls
+// back to defaultSeenLogsCap.
+func newSeenLogs(capacity int) *seenLogs {
+	if capacity <= 0 {
+		capacity = defaultSeenLogsCap
+	}
+	return &seenLogs{cap: capacity, ids: make(map[string]struct{})}
+}
+
+// Contains reports whether id has already been added.
+func (s *seenLogs) Contains(id string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.ids[id]
+	return ok
+}
+
+// Add records id as seen, evicting the oldest entry first if the set is already at capacity. A
+// no-op for an empty id, since sources with no stable per-record identifier pass through "" rather
+// than asking callers to treat it as a real value worth deduplicating.
+func (s *seenLogs) Add(id string) {
+	if id == "" {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.ids[id]; ok {
+		return
+	}
+	if len(s.order) >= s.cap {
+		oldest := s.order[0]
+		s.order = s.order[1:]
+		delete(s.ids, oldest)
+	}
+	s.ids[id] = struct{}{}
+	s.order = append(s.order, id)
+}
+
+// Remove drops id from the set, if present.
+func (s *seenLogs) Remove(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.ids[id]; !ok {
+		return
+	}
+	delete(s.ids, id)
+	for i, v := range s.order {
+		if v == id {
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// Len returns the number of IDs currently retained.
+func (s *seenLogs) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.ids)
+}
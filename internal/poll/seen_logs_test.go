@@ -0,0 +1,92 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package poll
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeenLogsAddAndContains(t *testing.T) {
+	s := newSeenLogs(0)
+	assert.False(t, s.Contains("a"))
+	s.Add("a")
+	assert.True(t, s.Contains("a"))
+	assert.Equal(t, 1, s.Len())
+}
+
+func TestSeenLogsAddEmptyIdIsNoop(t *testing.T) {
+	s := newSeenLogs(0)
+	s.Add("")
+	assert.False(t, s.Contains(""))
+	assert.Equal(t, 0, s.Len())
+}
+
+func TestSeenLogsAddDuplicateIsIdempotent(t *testing.T) {
+	s := newSeenLogs(0)
+	s.Add("a")
+	s.Add("a")
+	assert.Equal(t, 1, s.Len())
+}
+
+func TestSeenLogsRemove(t *testing.T) {
+	s := newSeenLogs(0)
+	s.Add("a")
+	s.Remove("a")
+	assert.False(t, s.Contains("a"))
+	assert.Equal(t, 0, s.Len())
+
+	// removing an id that was never added is a no-op, not an error
+	s.Remove("never-added")
+}
+
+func TestSeenLogsEvictsOldestWhenOverCapacity(t *testing.T) {
+	s := newSeenLogs(2)
+	s.Add("a")
+	s.Add("b")
+	s.Add("c")
+
+	assert.Equal(t, 2, s.Len())
+	assert.False(t, s.Contains("a"))
+	assert.True(t, s.Contains("b"))
+	assert.True(t, s.Contains("c"))
+}
+
+func TestSeenLogsNonPositiveCapacityFallsBackToDefault(t *testing.T) {
+	s := newSeenLogs(-1)
+	assert.Equal(t, defaultSeenLogsCap, s.cap)
+}
+
+func TestSeenLogsConcurrentAddIsRace(t *testing.T) {
+	s := newSeenLogs(0)
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			s.Add(strconv.Itoa(i))
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(t, 100, s.Len())
+}
@@ -0,0 +1,49 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package poll
+
+import (
+	"context"
+	"time"
+
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+)
+
+// ScoringEvent pairs a raw ScoringMessage with the time its source reported it was generated, so
+// ChaseTail can advance poll.EnvBaseTime and the batching window regardless of which ScoringSource
+// produced it.
+//
+// ID is the source's own identifier for the underlying record (e.g. a Datadog log's Id), used by
+// ChaseTail's seenLogs set to drop a duplicate delivered by an overlapping poll window. It's
+// optional: a source with no stable per-record identifier (most of them) leaves it empty, which
+// seenLogs always treats as "process, don't dedupe".
+type ScoringEvent struct {
+	ID          string
+	EnvBaseTime time.Time
+	Message     types.ScoringMessage
+}
+
+// ScoringSource is implemented by anything capable of yielding ScoringEvents for a polling window.
+// Fetch returns one page of events between since and until; callers page through a source by
+// feeding the returned cursor back into the next call until done is true. A source with no
+// concept of paging can ignore cursor and always return done=true.
+type ScoringSource interface {
+	Fetch(ctx context.Context, since, until time.Time, cursor string) (events []ScoringEvent, nextCursor string, done bool, err error)
+}
@@ -0,0 +1,37 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package poll
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCloudWatchSourceFetchIsNotImplemented(t *testing.T) {
+	source := NewCloudWatchSource("bbash-scoring")
+
+	events, _, done, err := source.Fetch(context.Background(), time.Time{}, time.Time{}, "")
+	assert.Error(t, err)
+	assert.True(t, done)
+	assert.Equal(t, 0, len(events))
+}
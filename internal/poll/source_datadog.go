@@ -0,0 +1,51 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package poll
+
+import (
+	"context"
+	"time"
+)
+
+// DatadogSource is the original ScoringSource, reading scoring events out of Datadog Logs via
+// fetchLogPage. It holds no state of its own; paging state lives in the cursor passed between
+// Fetch calls, and auth/client setup is handled by the package-level dogApiClient.
+type DatadogSource struct{}
+
+// NewDatadogSource builds a DatadogSource reading from Datadog Logs, configured via the DD_CLIENT_*
+// environment variables consumed by DogApiClient.
+func NewDatadogSource() *DatadogSource {
+	return &DatadogSource{}
+}
+
+var _ ScoringSource = (*DatadogSource)(nil)
+
+func (s *DatadogSource) Fetch(ctx context.Context, since, until time.Time, cursor string) (events []ScoringEvent, nextCursor string, done bool, err error) {
+	done, nextCursor, logs, _, err := fetchLogPage(ctx, since, until, &cursor)
+	if err != nil {
+		return
+	}
+
+	events = make([]ScoringEvent, len(logs))
+	for i, log := range logs {
+		events[i] = ScoringEvent{ID: log.Id, EnvBaseTime: log.Fields.envBaseTime, Message: log.Fields.scoringMessage}
+	}
+	return
+}
@@ -0,0 +1,57 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package poll
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDockerSourceReadsLinesAndAdvancesSince(t *testing.T) {
+	t1 := time.Date(2026, 7, 29, 10, 0, 0, 0, time.UTC)
+	t2 := t1.Add(time.Second)
+	setupMockCommandOutput(t, []byte(
+		t1.Format(time.RFC3339Nano)+` {"triggerUser":"alice"}`+"\n"+
+			t2.Format(time.RFC3339Nano)+` {"triggerUser":"bob"}`))
+
+	source := NewDockerSource("ci-runner")
+	events, _, done, err := source.Fetch(context.Background(), t1.Add(-time.Minute), time.Now(), "")
+	assert.NoError(t, err)
+	assert.True(t, done)
+	assert.Equal(t, 2, len(events))
+	assert.Equal(t, "alice", events[0].Message.TriggerUser)
+	assert.Equal(t, "bob", events[1].Message.TriggerUser)
+	assert.Equal(t, t2, source.since)
+}
+
+func TestDockerSourceSkipsLinesAtOrBeforeSince(t *testing.T) {
+	t1 := time.Date(2026, 7, 29, 10, 0, 0, 0, time.UTC)
+	source := &DockerSource{container: "ci-runner", since: t1}
+
+	setupMockCommandOutput(t, []byte(t1.Format(time.RFC3339Nano)+` {"triggerUser":"alice"}`))
+
+	events, _, done, err := source.Fetch(context.Background(), t1, time.Now(), "")
+	assert.NoError(t, err)
+	assert.True(t, done)
+	assert.Equal(t, 0, len(events))
+}
@@ -0,0 +1,33 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package poll
+
+import (
+	"context"
+	"os/exec"
+)
+
+// commandOutput runs name with args and returns its captured stdout, the same way dogApiClient is
+// a swappable package var: JournaldSource and DockerSource shell out to the journalctl/docker
+// CLIs rather than vendoring a client for either, so tests replace commandOutput instead of
+// requiring those binaries in CI.
+var commandOutput = func(ctx context.Context, name string, args ...string) (output []byte, err error) {
+	return exec.CommandContext(ctx, name, args...).Output()
+}
@@ -0,0 +1,76 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package poll
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+)
+
+// FileSource is a ScoringSource reading one JSON-encoded types.ScoringMessage per line from r, for
+// local development and integration tests that want to replay scoring events without a live
+// upstream log source. It yields every line once, across as many Fetch calls as it takes, then
+// reports done with no further events.
+type FileSource struct {
+	scanner *bufio.Scanner
+	drained bool
+}
+
+var _ ScoringSource = (*FileSource)(nil)
+
+// NewFileSource builds a FileSource reading scoring messages from r, e.g. an opened file or
+// os.Stdin.
+func NewFileSource(r io.Reader) *FileSource {
+	return &FileSource{scanner: bufio.NewScanner(r)}
+}
+
+func (f *FileSource) Fetch(_ context.Context, _, _ time.Time, _ string) (events []ScoringEvent, nextCursor string, done bool, err error) {
+	if f.drained {
+		done = true
+		return
+	}
+
+	now := time.Now()
+	for f.scanner.Scan() {
+		line := strings.TrimSpace(f.scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var msg types.ScoringMessage
+		if err = json.Unmarshal([]byte(line), &msg); err != nil {
+			return
+		}
+		events = append(events, ScoringEvent{EnvBaseTime: now, Message: msg})
+	}
+	if err = f.scanner.Err(); err != nil {
+		return
+	}
+
+	f.drained = true
+	done = true
+	return
+}
@@ -0,0 +1,66 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package poll
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileSourceYieldsEachLineOnce(t *testing.T) {
+	r := strings.NewReader(`{"triggerUser":"alice"}
+{"triggerUser":"bob"}
+`)
+	source := NewFileSource(r)
+
+	events, cursor, done, err := source.Fetch(context.Background(), time.Time{}, time.Time{}, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "", cursor)
+	assert.True(t, done)
+	assert.Equal(t, 2, len(events))
+	assert.Equal(t, "alice", events[0].Message.TriggerUser)
+	assert.Equal(t, "bob", events[1].Message.TriggerUser)
+
+	events, _, done, err = source.Fetch(context.Background(), time.Time{}, time.Time{}, "")
+	assert.NoError(t, err)
+	assert.True(t, done)
+	assert.Equal(t, 0, len(events))
+}
+
+func TestFileSourceSkipsBlankLines(t *testing.T) {
+	r := strings.NewReader("\n{\"triggerUser\":\"alice\"}\n\n")
+	source := NewFileSource(r)
+
+	events, _, done, err := source.Fetch(context.Background(), time.Time{}, time.Time{}, "")
+	assert.NoError(t, err)
+	assert.True(t, done)
+	assert.Equal(t, 1, len(events))
+}
+
+func TestFileSourceInvalidJsonErrors(t *testing.T) {
+	source := NewFileSource(strings.NewReader("not json"))
+
+	_, _, _, err := source.Fetch(context.Background(), time.Time{}, time.Time{}, "")
+	assert.Error(t, err)
+}
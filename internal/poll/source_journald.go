@@ -0,0 +1,97 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package poll
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+)
+
+// JournaldSource is a ScoringSource reading one JSON-encoded types.ScoringMessage per log entry's
+// MESSAGE field out of systemd's journal, via the journalctl CLI - so a self-hosted CI runner can
+// emit scoring messages to its own journal instead of requiring a Datadog account. Like
+// TailSource, journald's own cursor is remembered across Fetch calls as internal state rather than
+// the cursor parameter, since pollTheDog resets that parameter to "" at the start of every tick.
+type JournaldSource struct {
+	unit   string
+	cursor string
+}
+
+var _ ScoringSource = (*JournaldSource)(nil)
+
+// NewJournaldSource builds a JournaldSource reading entries from unit (journalctl's -u); an empty
+// unit reads the whole journal.
+func NewJournaldSource(unit string) *JournaldSource {
+	return &JournaldSource{unit: unit}
+}
+
+// journaldEntry is the subset of journalctl's "-o json" export fields this source reads.
+type journaldEntry struct {
+	Cursor  string `json:"__CURSOR"`
+	Message string `json:"MESSAGE"`
+}
+
+func (j *JournaldSource) Fetch(ctx context.Context, since, _ time.Time, _ string) (events []ScoringEvent, nextCursor string, done bool, err error) {
+	done = true
+
+	args := []string{"-o", "json", "--no-pager"}
+	if j.unit != "" {
+		args = append(args, "-u", j.unit)
+	}
+	if j.cursor != "" {
+		args = append(args, "--after-cursor="+j.cursor)
+	} else {
+		args = append(args, "--since", since.Format("2006-01-02 15:04:05"))
+	}
+
+	out, err := commandOutput(ctx, "journalctl", args...)
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if line == "" {
+			continue
+		}
+
+		var entry journaldEntry
+		if err = json.Unmarshal([]byte(line), &entry); err != nil {
+			return
+		}
+		j.cursor = entry.Cursor
+
+		trimmed := strings.TrimSpace(entry.Message)
+		if trimmed == "" {
+			continue
+		}
+		var msg types.ScoringMessage
+		if err = json.Unmarshal([]byte(trimmed), &msg); err != nil {
+			return
+		}
+		events = append(events, ScoringEvent{EnvBaseTime: now, Message: msg})
+	}
+
+	return
+}
@@ -0,0 +1,82 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package poll
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// setupMockCommandOutput swaps commandOutput for a stub returning output and recording the args
+// it was called with, restoring the real exec-backed implementation once the test finishes.
+func setupMockCommandOutput(t *testing.T, output []byte) (calledArgs *[]string) {
+	calledArgs = &[]string{}
+	orig := commandOutput
+	t.Cleanup(func() { commandOutput = orig })
+
+	commandOutput = func(_ context.Context, name string, args ...string) ([]byte, error) {
+		*calledArgs = append([]string{name}, args...)
+		return output, nil
+	}
+	return
+}
+
+func TestJournaldSourceReadsEntriesAndAdvancesCursor(t *testing.T) {
+	setupMockCommandOutput(t, []byte(
+		`{"__CURSOR":"c1","MESSAGE":"{\"triggerUser\":\"alice\"}"}`+"\n"+
+			`{"__CURSOR":"c2","MESSAGE":"{\"triggerUser\":\"bob\"}"}`))
+
+	source := NewJournaldSource("")
+	events, _, done, err := source.Fetch(context.Background(), time.Now(), time.Now(), "")
+	assert.NoError(t, err)
+	assert.True(t, done)
+	assert.Equal(t, 2, len(events))
+	assert.Equal(t, "alice", events[0].Message.TriggerUser)
+	assert.Equal(t, "bob", events[1].Message.TriggerUser)
+	assert.Equal(t, "c2", source.cursor)
+}
+
+func TestJournaldSourceUsesAfterCursorOnceSet(t *testing.T) {
+	source := NewJournaldSource("bbash-runner")
+	source.cursor = "c2"
+
+	calledArgs := setupMockCommandOutput(t, []byte(""))
+
+	events, _, done, err := source.Fetch(context.Background(), time.Now(), time.Now(), "")
+	assert.NoError(t, err)
+	assert.True(t, done)
+	assert.Equal(t, 0, len(events))
+	assert.Contains(t, *calledArgs, "--after-cursor=c2")
+	assert.Contains(t, *calledArgs, "-u")
+	assert.Contains(t, *calledArgs, "bbash-runner")
+}
+
+func TestJournaldSourceUsesSinceWhenNoCursorYet(t *testing.T) {
+	source := NewJournaldSource("")
+
+	calledArgs := setupMockCommandOutput(t, []byte(""))
+
+	_, _, _, err := source.Fetch(context.Background(), time.Now(), time.Now(), "")
+	assert.NoError(t, err)
+	assert.Contains(t, *calledArgs, "--since")
+}
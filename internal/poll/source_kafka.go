@@ -0,0 +1,53 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package poll
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// KafkaSource is a placeholder ScoringSource for configuring a Kafka topic as a scoring-event
+// source. Unlike RedisStreamSource (whose wire protocol is a handful of simple RESP commands),
+// Kafka's broker protocol (cluster metadata, partition assignment, consumer group coordination,
+// fetch/offset-commit framing) is well beyond what's proportionate to hand-roll here, and no
+// Kafka client library is available in this offline build environment. Fetch always errors so a
+// misconfigured "kafka" source fails loudly (MultiSource logs and skips it) rather than silently
+// dropping events; wiring in a real client (e.g. segmentio/kafka-go) once network access is
+// available is a drop-in replacement for this type.
+type KafkaSource struct {
+	brokers []string
+	topic   string
+}
+
+var _ ScoringSource = (*KafkaSource)(nil)
+
+// NewKafkaSource builds a KafkaSource configured for topic on brokers; see the type doc for why
+// Fetch is unimplemented.
+func NewKafkaSource(brokers []string, topic string) *KafkaSource {
+	return &KafkaSource{brokers: brokers, topic: topic}
+}
+
+func (k *KafkaSource) Fetch(_ context.Context, _, _ time.Time, _ string) (events []ScoringEvent, nextCursor string, done bool, err error) {
+	done = true
+	err = fmt.Errorf("kafka source for topic %q not implemented: no Kafka client available in this build", k.topic)
+	return
+}
@@ -0,0 +1,196 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package poll
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+)
+
+const (
+	envLogglyAccount  = "LOGGLY_ACCOUNT"
+	envLogglyApiToken = "LOGGLY_API_TOKEN"
+)
+
+// LogglyApiBase is the default base url for the Loggly search API.
+const LogglyApiBase = "https://%s.loggly.com/apiv2"
+
+const logglyQuery = "json." + qryEnv + "." + qryEnvExtraJsonFields + "." + qryFldFixedBugs + ">0"
+
+// logglySearchResponse is the subset of Loggly's /search response this source reads.
+type logglySearchResponse struct {
+	Rsid struct {
+		Id string `json:"id"`
+	} `json:"rsid"`
+}
+
+// logglyEvent is the subset of Loggly's /events response this source reads; the scoring message
+// and envBaseTime are nested under the same "env" object the Datadog source reads, since both are
+// shipped from the same application logging.
+type logglyEvent struct {
+	Timestamp int64 `json:"timestamp"`
+	Event     struct {
+		Json struct {
+			Env map[string]interface{} `json:"env"`
+		} `json:"json"`
+	} `json:"event"`
+}
+
+type logglyEventsResponse struct {
+	Events []logglyEvent `json:"events"`
+	Page   int           `json:"page"`
+	Total  int           `json:"total_events"`
+}
+
+// LogglySource is a ScoringSource reading scoring events out of Loggly, configured via the
+// LOGGLY_ACCOUNT and LOGGLY_API_TOKEN environment variables. Loggly's search API is two steps: a
+// search call returns an rsid, which is then paged through via the events call; cursor here
+// encodes "<rsid>:<page>".
+type LogglySource struct {
+	baseAPI string
+	token   string
+	http    *http.Client
+}
+
+var _ ScoringSource = (*LogglySource)(nil)
+
+// NewLogglySource builds a LogglySource reading from the account named by LOGGLY_ACCOUNT.
+func NewLogglySource() *LogglySource {
+	return &LogglySource{
+		baseAPI: fmt.Sprintf(LogglyApiBase, os.Getenv(envLogglyAccount)),
+		token:   os.Getenv(envLogglyApiToken),
+		http:    &http.Client{Timeout: time.Second * 10},
+	}
+}
+
+func (s *LogglySource) Fetch(ctx context.Context, since, until time.Time, cursor string) (events []ScoringEvent, nextCursor string, done bool, err error) {
+	var rsid string
+	var page int
+	if cursor == "" {
+		rsid, err = s.search(ctx, since, until)
+		if err != nil {
+			return
+		}
+		page = 0
+	} else {
+		if _, scanErr := fmt.Sscanf(cursor, "%s %d", &rsid, &page); scanErr != nil {
+			err = fmt.Errorf("invalid loggly cursor: %s", cursor)
+			return
+		}
+	}
+
+	var resp logglyEventsResponse
+	resp, err = s.events(ctx, rsid, page)
+	if err != nil {
+		return
+	}
+
+	events = make([]ScoringEvent, 0, len(resp.Events))
+	for _, evt := range resp.Events {
+		msg, convErr := scoringMessageFromEnv(evt.Event.Json.Env)
+		if convErr != nil {
+			err = convErr
+			return
+		}
+		events = append(events, ScoringEvent{
+			EnvBaseTime: time.Unix(0, evt.Timestamp*int64(time.Millisecond)),
+			Message:     msg,
+		})
+	}
+
+	if (resp.Page+1)*len(resp.Events) >= resp.Total || len(resp.Events) == 0 {
+		done = true
+		return
+	}
+	nextCursor = fmt.Sprintf("%s %d", rsid, resp.Page+1)
+	return
+}
+
+// scoringMessageFromEnv decodes the "env" json object embedded in a Loggly event into a
+// ScoringMessage, the same shape the application emits to whichever log sink is configured.
+func scoringMessageFromEnv(env map[string]interface{}) (msg types.ScoringMessage, err error) {
+	extra, ok := env[qryEnvExtraJsonFields]
+	if !ok {
+		return
+	}
+
+	extraMap, ok := extra.(map[string]interface{})
+	if !ok {
+		err = fmt.Errorf("unexpected %s type in %+v", qryEnvExtraJsonFields, env)
+		return
+	}
+
+	var jsonBytes []byte
+	jsonBytes, err = json.Marshal(extraMap)
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal(jsonBytes, &msg)
+	return
+}
+
+func (s *LogglySource) search(ctx context.Context, since, until time.Time) (rsid string, err error) {
+	url := fmt.Sprintf("%s/search?q=%s&from=%s&until=%s",
+		s.baseAPI, logglyQuery, since.Format(time.RFC3339), until.Format(time.RFC3339))
+
+	var resp logglySearchResponse
+	if err = s.getJSON(ctx, url, &resp); err != nil {
+		return
+	}
+	rsid = resp.Rsid.Id
+	return
+}
+
+func (s *LogglySource) events(ctx context.Context, rsid string, page int) (resp logglyEventsResponse, err error) {
+	url := fmt.Sprintf("%s/events?rsid=%s&page=%d", s.baseAPI, rsid, page)
+	err = s.getJSON(ctx, url, &resp)
+	return
+}
+
+func (s *LogglySource) getJSON(ctx context.Context, url string, out interface{}) (err error) {
+	var req *http.Request
+	req, err = http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return
+	}
+	req = req.WithContext(ctx)
+	req.Header.Add("Authorization", fmt.Sprintf("bearer %s", s.token))
+
+	var res *http.Response
+	res, err = s.http.Do(req)
+	if err != nil {
+		return
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		err = fmt.Errorf("loggly api error: %s", res.Status)
+		return
+	}
+
+	err = json.NewDecoder(res.Body).Decode(out)
+	return
+}
@@ -0,0 +1,99 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package poll
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testLogglySource(baseURL string) *LogglySource {
+	return &LogglySource{baseAPI: baseURL, token: "testToken", http: &http.Client{}}
+}
+
+func TestLogglySourceFetchOnePageIsDone(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/search":
+			resp := logglySearchResponse{}
+			resp.Rsid.Id = "myRsid"
+			assert.NoError(t, json.NewEncoder(w).Encode(resp))
+		case r.URL.Path == "/events":
+			assert.Equal(t, "myRsid", r.URL.Query().Get("rsid"))
+			resp := logglyEventsResponse{
+				Events: []logglyEvent{
+					{
+						Timestamp: 1000,
+						Event: struct {
+							Json struct {
+								Env map[string]interface{} `json:"env"`
+							} `json:"json"`
+						}{Json: struct {
+							Env map[string]interface{} `json:"env"`
+						}{Env: map[string]interface{}{
+							qryEnvExtraJsonFields: map[string]interface{}{"eventSource": "github"},
+						}}},
+					},
+				},
+				Page:  0,
+				Total: 1,
+			}
+			assert.NoError(t, json.NewEncoder(w).Encode(resp))
+		default:
+			t.Fatalf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer ts.Close()
+
+	source := testLogglySource(ts.URL)
+	events, cursor, done, err := source.Fetch(context.Background(), time.Now(), time.Now(), "")
+	assert.NoError(t, err)
+	assert.Equal(t, "", cursor)
+	assert.True(t, done)
+	assert.Equal(t, 1, len(events))
+	assert.Equal(t, "github", events[0].Message.EventSource)
+}
+
+func TestLogglySourceFetchWithCursorSkipsSearch(t *testing.T) {
+	searched := false
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/search" {
+			searched = true
+		}
+		assert.Equal(t, "/events", r.URL.Path)
+		assert.Equal(t, "1", r.URL.Query().Get("page"))
+		resp := logglyEventsResponse{}
+		assert.NoError(t, json.NewEncoder(w).Encode(resp))
+	}))
+	defer ts.Close()
+
+	source := testLogglySource(ts.URL)
+	events, _, done, err := source.Fetch(context.Background(), time.Now(), time.Now(), "myRsid 1")
+	assert.NoError(t, err)
+	assert.False(t, searched)
+	assert.True(t, done)
+	assert.Equal(t, 0, len(events))
+}
@@ -0,0 +1,148 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package poll
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+)
+
+const (
+	envLokiBaseURL = "LOKI_BASE_URL"
+	envLokiQuery   = "LOKI_QUERY"
+)
+
+// defaultLokiQuery selects every log line Promtail/Loki has labeled as coming from bbash; an
+// operator who labels their scrape config differently overrides it via LOKI_QUERY.
+const defaultLokiQuery = `{app="bbash"}`
+
+// lokiQueryRangeResponse is the subset of Loki's /loki/api/v1/query_range response this source
+// reads: a set of label-identified streams, each a list of [nanosecond timestamp string, line]
+// pairs in either direction's natural order.
+type lokiQueryRangeResponse struct {
+	Data struct {
+		Result []struct {
+			Values [][2]string `json:"values"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// LokiSource is a ScoringSource reading scoring events out of Grafana Loki, configured via
+// LOKI_BASE_URL (e.g. "http://loki:3100") and an optional LogQL LOKI_QUERY (defaults to
+// defaultLokiQuery). Like JournaldSource, Loki's own high-water mark is remembered across Fetch
+// calls as internal state rather than the cursor parameter, since pollTheDog resets that parameter
+// to "" at the start of every tick; each log line is expected to be the same JSON-encoded
+// types.ScoringMessage the application emits to any other log sink, nested under an "env" object.
+type LokiSource struct {
+	baseURL string
+	query   string
+	http    *http.Client
+	sinceNs int64
+}
+
+var _ ScoringSource = (*LokiSource)(nil)
+
+// NewLokiSource builds a LokiSource reading from LOKI_BASE_URL with the LogQL query in LOKI_QUERY,
+// or defaultLokiQuery if unset.
+func NewLokiSource() *LokiSource {
+	query := os.Getenv(envLokiQuery)
+	if query == "" {
+		query = defaultLokiQuery
+	}
+	return &LokiSource{
+		baseURL: os.Getenv(envLokiBaseURL),
+		query:   query,
+		http:    &http.Client{Timeout: time.Second * 10},
+	}
+}
+
+func (s *LokiSource) Fetch(ctx context.Context, since, until time.Time, _ string) (events []ScoringEvent, nextCursor string, done bool, err error) {
+	done = true
+
+	start := since.UnixNano()
+	if s.sinceNs > start {
+		start = s.sinceNs
+	}
+
+	url := fmt.Sprintf("%s/loki/api/v1/query_range?query=%s&start=%d&end=%d&direction=forward",
+		s.baseURL, s.query, start, until.UnixNano())
+
+	var req *http.Request
+	req, err = http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return
+	}
+	req = req.WithContext(ctx)
+
+	var res *http.Response
+	res, err = s.http.Do(req)
+	if err != nil {
+		return
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		err = fmt.Errorf("loki api error: %s", res.Status)
+		return
+	}
+
+	var resp lokiQueryRangeResponse
+	if err = json.NewDecoder(res.Body).Decode(&resp); err != nil {
+		return
+	}
+
+	for _, stream := range resp.Data.Result {
+		for _, value := range stream.Values {
+			var tsNs int64
+			tsNs, err = strconv.ParseInt(value[0], 10, 64)
+			if err != nil {
+				return
+			}
+			if tsNs > s.sinceNs {
+				s.sinceNs = tsNs
+			}
+
+			var env map[string]interface{}
+			if err = json.Unmarshal([]byte(value[1]), &env); err != nil {
+				return
+			}
+
+			var msg types.ScoringMessage
+			msg, err = scoringMessageFromEnv(env)
+			if err != nil {
+				return
+			}
+			events = append(events, ScoringEvent{EnvBaseTime: time.Unix(0, tsNs), Message: msg})
+		}
+	}
+
+	// advance past the last line seen so the next Fetch's since doesn't re-query it
+	if s.sinceNs > 0 {
+		s.sinceNs++
+	}
+	return
+}
@@ -0,0 +1,95 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package poll
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testLokiSource(baseURL string) *LokiSource {
+	return &LokiSource{baseURL: baseURL, query: defaultLokiQuery, http: &http.Client{}}
+}
+
+func TestLokiSourceFetchOneLine(t *testing.T) {
+	line, err := json.Marshal(map[string]interface{}{
+		qryEnvExtraJsonFields: map[string]interface{}{"eventSource": "github"},
+	})
+	assert.NoError(t, err)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/loki/api/v1/query_range", r.URL.Path)
+		resp := lokiQueryRangeResponse{}
+		resp.Data.Result = []struct {
+			Values [][2]string `json:"values"`
+		}{
+			{Values: [][2]string{{"1000", string(line)}}},
+		}
+		assert.NoError(t, json.NewEncoder(w).Encode(resp))
+	}))
+	defer ts.Close()
+
+	source := testLokiSource(ts.URL)
+	events, cursor, done, fetchErr := source.Fetch(context.Background(), time.Now(), time.Now(), "")
+	assert.NoError(t, fetchErr)
+	assert.Equal(t, "", cursor)
+	assert.True(t, done)
+	assert.Equal(t, 1, len(events))
+	assert.Equal(t, "github", events[0].Message.EventSource)
+}
+
+func TestLokiSourceFetchAdvancesSinceNsPastLastLine(t *testing.T) {
+	line, err := json.Marshal(map[string]interface{}{})
+	assert.NoError(t, err)
+
+	var gotStart string
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		resp := lokiQueryRangeResponse{}
+		if calls == 1 {
+			gotStart = r.URL.Query().Get("start")
+			resp.Data.Result = []struct {
+				Values [][2]string `json:"values"`
+			}{
+				{Values: [][2]string{{"1000", string(line)}}},
+			}
+		} else {
+			gotStart = r.URL.Query().Get("start")
+		}
+		assert.NoError(t, json.NewEncoder(w).Encode(resp))
+	}))
+	defer ts.Close()
+
+	source := testLokiSource(ts.URL)
+	_, _, _, err = source.Fetch(context.Background(), time.Unix(0, 0), time.Now(), "")
+	assert.NoError(t, err)
+
+	_, _, _, err = source.Fetch(context.Background(), time.Unix(0, 0), time.Now(), "")
+	assert.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("%d", int64(1001)), gotStart)
+}
@@ -0,0 +1,245 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package poll
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+)
+
+// RedisStreamSource is a ScoringSource reading types.ScoringMessage entries off a Redis Stream via
+// XREAD. As with leader.RedisElector, there is no vendored Redis client in this module (and none
+// can be fetched offline), so this speaks just enough RESP to issue XREAD and parse its nested
+// array reply - no consumer groups, no XACK/XCLAIM, no cluster support. Each stream entry is
+// expected to carry a single field (messageField) whose value is a JSON-encoded ScoringMessage.
+type RedisStreamSource struct {
+	addr        string
+	streamKey   string
+	dialTimeout time.Duration
+
+	lastID string
+}
+
+var _ ScoringSource = (*RedisStreamSource)(nil)
+
+// messageField is the stream entry field name RedisStreamSource reads the JSON-encoded
+// ScoringMessage from.
+const messageField = "message"
+
+// NewRedisStreamSource builds a RedisStreamSource reading streamKey off the Redis at addr
+// (host:port), starting from the beginning of the stream.
+func NewRedisStreamSource(addr, streamKey string) *RedisStreamSource {
+	return &RedisStreamSource{
+		addr:        addr,
+		streamKey:   streamKey,
+		dialTimeout: 2 * time.Second,
+		lastID:      "0",
+	}
+}
+
+// Fetch issues a single non-blocking XREAD from just after the last ID seen. RedisStreamSource
+// never reports done, since the stream can always grow further.
+func (s *RedisStreamSource) Fetch(ctx context.Context, _, _ time.Time, _ string) (events []ScoringEvent, nextCursor string, done bool, err error) {
+	done = true
+
+	dialer := net.Dialer{Timeout: s.dialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		err = fmt.Errorf("redis stream dial %s: %w", s.addr, err)
+		return
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	} else {
+		_ = conn.SetDeadline(time.Now().Add(s.dialTimeout))
+	}
+
+	if err = writeRESPCommand(conn, "XREAD", "COUNT", "500", "STREAMS", s.streamKey, s.lastID); err != nil {
+		return
+	}
+
+	reply, err := readRESPValue(bufio.NewReader(conn))
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	events, err = s.parseXReadReply(reply, now)
+	return
+}
+
+// parseXReadReply walks XREAD's reply shape: nil (nothing new), or
+// [ [streamKey, [ [entryID, [field, value, field, value, ...]], ... ]] ].
+func (s *RedisStreamSource) parseXReadReply(reply interface{}, now time.Time) (events []ScoringEvent, err error) {
+	if reply == nil {
+		return
+	}
+
+	streams, ok := reply.([]interface{})
+	if !ok || len(streams) == 0 {
+		return
+	}
+
+	for _, streamReply := range streams {
+		stream, ok := streamReply.([]interface{})
+		if !ok || len(stream) != 2 {
+			continue
+		}
+		entries, ok := stream[1].([]interface{})
+		if !ok {
+			continue
+		}
+
+		for _, entryReply := range entries {
+			entry, ok := entryReply.([]interface{})
+			if !ok || len(entry) != 2 {
+				continue
+			}
+			id, ok := entry[0].(string)
+			if !ok {
+				continue
+			}
+			fields, ok := entry[1].([]interface{})
+			if !ok {
+				continue
+			}
+
+			msg, found, parseErr := parseStreamFields(fields)
+			if parseErr != nil {
+				err = parseErr
+				return
+			}
+			if found {
+				events = append(events, ScoringEvent{EnvBaseTime: now, Message: msg})
+			}
+			s.lastID = id
+		}
+	}
+	return
+}
+
+// parseStreamFields pulls messageField out of a flat [field, value, field, value, ...] list and
+// decodes it as a types.ScoringMessage.
+func parseStreamFields(fields []interface{}) (msg types.ScoringMessage, found bool, err error) {
+	for i := 0; i+1 < len(fields); i += 2 {
+		field, _ := fields[i].(string)
+		if field != messageField {
+			continue
+		}
+		value, _ := fields[i+1].(string)
+		if err = json.Unmarshal([]byte(value), &msg); err != nil {
+			return
+		}
+		found = true
+		return
+	}
+	return
+}
+
+// writeRESPCommand sends args as a RESP array, the wire format every Redis command uses.
+func writeRESPCommand(conn net.Conn, args ...string) (err error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	_, err = conn.Write([]byte(b.String()))
+	return
+}
+
+// readRESPValue parses one RESP value, recursively for arrays (*): a simple string (+), error
+// (-), integer (:), bulk string ($, nil for length -1), or array (*, nil for count -1). This is
+// the general-purpose counterpart to leader.RedisElector's readRESPReply, which deliberately only
+// handles the flat reply shapes SET/GET/DEL return.
+func readRESPValue(r *bufio.Reader) (value interface{}, err error) {
+	line, err := readRESPLine(r)
+	if err != nil {
+		return nil, err
+	}
+	if line == "" {
+		return nil, fmt.Errorf("redis: empty reply")
+	}
+
+	switch line[0] {
+	case '+', ':':
+		return line[1:], nil
+	case '-':
+		return nil, fmt.Errorf("redis error: %s", line[1:])
+	case '$':
+		length, convErr := strconv.Atoi(line[1:])
+		if convErr != nil {
+			return nil, fmt.Errorf("redis: bad bulk length %q: %w", line[1:], convErr)
+		}
+		if length < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, length+2) // +2 for the trailing \r\n
+		if _, err = readRESPFull(r, buf); err != nil {
+			return nil, err
+		}
+		return string(buf[:length]), nil
+	case '*':
+		count, convErr := strconv.Atoi(line[1:])
+		if convErr != nil {
+			return nil, fmt.Errorf("redis: bad array length %q: %w", line[1:], convErr)
+		}
+		if count < 0 {
+			return nil, nil
+		}
+		values := make([]interface{}, count)
+		for i := 0; i < count; i++ {
+			if values[i], err = readRESPValue(r); err != nil {
+				return nil, err
+			}
+		}
+		return values, nil
+	default:
+		return nil, fmt.Errorf("redis: unsupported reply type %q", line[0])
+	}
+}
+
+func readRESPLine(r *bufio.Reader) (line string, err error) {
+	raw, err := r.ReadString('\n')
+	if err != nil {
+		return "", fmt.Errorf("redis read: %w", err)
+	}
+	return strings.TrimRight(raw, "\r\n"), nil
+}
+
+func readRESPFull(r *bufio.Reader, buf []byte) (n int, err error) {
+	for n < len(buf) {
+		var read int
+		read, err = r.Read(buf[n:])
+		n += read
+		if err != nil {
+			return n, fmt.Errorf("redis read: %w", err)
+		}
+	}
+	return n, nil
+}
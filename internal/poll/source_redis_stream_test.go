@@ -0,0 +1,61 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package poll
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// bulkString RESP-encodes s as a bulk string ($<len>\r\n<s>\r\n).
+func bulkString(s string) string {
+	return fmt.Sprintf("$%d\r\n%s\r\n", len(s), s)
+}
+
+func TestReadRESPValueParsesNestedXReadArray(t *testing.T) {
+	// one stream, one entry, one field: XREAD's shape for a single new message.
+	entryFields := "*2\r\n" + bulkString("message") + bulkString(`{"triggerUser":"bob"}`)
+	entry := "*2\r\n" + bulkString("1690000000-0") + entryFields
+	stream := "*2\r\n" + bulkString("scores") + "*1\r\n" + entry
+	raw := "*1\r\n" + stream
+
+	value, err := readRESPValue(bufio.NewReader(strings.NewReader(raw)))
+	assert.NoError(t, err)
+
+	events, err := (&RedisStreamSource{}).parseXReadReply(value, time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(events))
+	assert.Equal(t, "bob", events[0].Message.TriggerUser)
+}
+
+func TestReadRESPValueNilArrayMeansNothingNew(t *testing.T) {
+	value, err := readRESPValue(bufio.NewReader(strings.NewReader("*-1\r\n")))
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+
+	events, err := (&RedisStreamSource{}).parseXReadReply(value, time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(events))
+}
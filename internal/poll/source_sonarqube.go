@@ -0,0 +1,200 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package poll
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+)
+
+// sonarQubeEventSource is the ScoringMessage.EventSource recorded for findings read from
+// SonarQubeSource; a source_control_provider registered with this name is how participants
+// opt in to being scored on resolved Sonar issues, alongside whichever SCM they also registered.
+const sonarQubeEventSource = "sonarqube"
+
+// sonarIssueSearchResponse is the subset of SonarQube's /api/issues/search response this source reads.
+type sonarIssueSearchResponse struct {
+	Total  int          `json:"total"`
+	Paging sonarPaging  `json:"paging"`
+	Issues []sonarIssue `json:"issues"`
+}
+
+type sonarPaging struct {
+	PageIndex int `json:"pageIndex"`
+	PageSize  int `json:"pageSize"`
+	Total     int `json:"total"`
+}
+
+// sonarIssue is the wire shape of a single entry in /api/issues/search's "issues" array. SonarQube
+// reports resolution time as "2021-01-02T15:04:05-0700" (a non-RFC3339 timezone offset), so it's
+// read as a string here and parsed explicitly when converting to a types.SonarQubeFinding.
+type sonarIssue struct {
+	Key            string `json:"key"`
+	Rule           string `json:"rule"`
+	Severity       string `json:"severity"`
+	Type           string `json:"type"`
+	Status         string `json:"status"`
+	Assignee       string `json:"assignee"`
+	Project        string `json:"project"`
+	ResolvedAtText string `json:"updateDate"`
+}
+
+// sonarResolvedAtLayout is the timezone-offset-without-colon layout SonarQube uses for its date
+// fields, e.g. "2021-01-02T15:04:05-0700".
+const sonarResolvedAtLayout = "2006-01-02T15:04:05-0700"
+
+// toFinding converts the raw wire shape into the public types.SonarQubeFinding. A resolution time
+// that fails to parse is left zero rather than failing the whole page, since it's informational.
+func (i sonarIssue) toFinding() types.SonarQubeFinding {
+	resolvedAt, _ := time.Parse(sonarResolvedAtLayout, i.ResolvedAtText)
+	return types.SonarQubeFinding{
+		ProjectKey: i.Project,
+		IssueKey:   i.Key,
+		Rule:       i.Rule,
+		Severity:   i.Severity,
+		Type:       i.Type,
+		Status:     i.Status,
+		Assignee:   i.Assignee,
+		ResolvedAt: resolvedAt,
+	}
+}
+
+// SonarQubeSource is a ScoringSource reading resolved, fixed issues out of a SonarQube host's
+// /api/issues/search, paginated, filtered to resolved=true&resolutions=FIXED. Each finding is
+// mapped into a ScoringMessage keyed by sonarQubeEventSource so it flows through the same
+// BugStruct.Category/PointValue scoring as GitHub-sourced events. cursor encodes the next page
+// number to fetch, so resuming mid-project is just resuming the page sequence; seenIssues
+// deduplicates findings re-seen across overlapping since/until windows, mirroring the pollFudgeSeconds
+// overlap pollTheDog already tolerates for its own source.
+type SonarQubeSource struct {
+	host       string
+	token      string
+	projectKey string
+	http       *http.Client
+
+	seenIssues map[string]bool
+}
+
+var _ ScoringSource = (*SonarQubeSource)(nil)
+
+// NewSonarQubeSource builds a SonarQubeSource reading from host (a registered ScanProviderStruct
+// Url), scoped to projectKey.
+func NewSonarQubeSource(host, token, projectKey string) *SonarQubeSource {
+	return &SonarQubeSource{
+		host:       host,
+		token:      token,
+		projectKey: projectKey,
+		http:       &http.Client{Timeout: time.Second * 10},
+		seenIssues: map[string]bool{},
+	}
+}
+
+const sonarPageSize = 100
+
+func (s *SonarQubeSource) Fetch(ctx context.Context, since, until time.Time, cursor string) (events []ScoringEvent, nextCursor string, done bool, err error) {
+	page := 1
+	if cursor != "" {
+		page, err = strconv.Atoi(cursor)
+		if err != nil {
+			err = fmt.Errorf("invalid sonarqube cursor: %s", cursor)
+			return
+		}
+	}
+
+	var resp sonarIssueSearchResponse
+	resp, err = s.search(ctx, since, until, page)
+	if err != nil {
+		return
+	}
+
+	events = make([]ScoringEvent, 0, len(resp.Issues))
+	for _, issue := range resp.Issues {
+		finding := issue.toFinding()
+		if s.seenIssues[finding.IssueKey] {
+			continue
+		}
+		s.seenIssues[finding.IssueKey] = true
+
+		events = append(events, ScoringEvent{
+			EnvBaseTime: until,
+			Message:     scoringMessageFromFinding(finding),
+		})
+	}
+
+	if page*resp.Paging.PageSize >= resp.Paging.Total || len(resp.Issues) == 0 {
+		done = true
+		return
+	}
+	nextCursor = strconv.Itoa(page + 1)
+	return
+}
+
+// scoringMessageFromFinding maps a resolved SonarQube finding into the ScoringMessage shape
+// processScoringMessage already knows how to score, keying the bug count by Rule (the most
+// specific category SonarQube offers) so admins can price individual rules via BugStruct;
+// Severity is used as a fallback key when a finding has no rule.
+func scoringMessageFromFinding(finding types.SonarQubeFinding) types.ScoringMessage {
+	bugType := finding.Rule
+	if bugType == "" {
+		bugType = finding.Severity
+	}
+	return types.ScoringMessage{
+		EventSource: sonarQubeEventSource,
+		RepoName:    finding.ProjectKey,
+		TriggerUser: finding.Assignee,
+		TotalFixed:  1,
+		BugCounts:   map[string]int{bugType: 1},
+	}
+}
+
+func (s *SonarQubeSource) search(ctx context.Context, since, until time.Time, page int) (resp sonarIssueSearchResponse, err error) {
+	url := fmt.Sprintf(
+		"%s/api/issues/search?componentKeys=%s&resolved=true&resolutions=FIXED&createdAfter=%s&createdBefore=%s&p=%d&ps=%d",
+		s.host, s.projectKey, since.Format("2006-01-02"), until.Format("2006-01-02"), page, sonarPageSize)
+
+	var req *http.Request
+	req, err = http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return
+	}
+	req = req.WithContext(ctx)
+	req.SetBasicAuth(s.token, "")
+
+	var res *http.Response
+	res, err = s.http.Do(req)
+	if err != nil {
+		return
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		err = fmt.Errorf("sonarqube api error: %s", res.Status)
+		return
+	}
+
+	err = json.NewDecoder(res.Body).Decode(&resp)
+	return
+}
@@ -0,0 +1,90 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package poll
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSonarQubeSourceFetchOnePageIsDone(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/issues/search", r.URL.Path)
+		assert.Equal(t, "true", r.URL.Query().Get("resolved"))
+		assert.Equal(t, "FIXED", r.URL.Query().Get("resolutions"))
+		resp := sonarIssueSearchResponse{
+			Paging: sonarPaging{PageIndex: 1, PageSize: 100, Total: 1},
+			Issues: []sonarIssue{
+				{
+					Key:      "myIssueKey",
+					Rule:     "go:S1234",
+					Severity: "MAJOR",
+					Project:  "myProjectKey",
+					Assignee: "alice",
+				},
+			},
+		}
+		assert.NoError(t, json.NewEncoder(w).Encode(resp))
+	}))
+	defer ts.Close()
+
+	source := NewSonarQubeSource(ts.URL, "testToken", "myProjectKey")
+	now := time.Now()
+	events, cursor, done, err := source.Fetch(context.Background(), now, now, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "", cursor)
+	assert.True(t, done)
+	assert.Equal(t, 1, len(events))
+	assert.Equal(t, "sonarqube", events[0].Message.EventSource)
+	assert.Equal(t, "alice", events[0].Message.TriggerUser)
+	assert.Equal(t, "myProjectKey", events[0].Message.RepoName)
+	assert.Equal(t, 1, events[0].Message.BugCounts["go:S1234"])
+}
+
+func TestSonarQubeSourceFetchDedupesAcrossCalls(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := sonarIssueSearchResponse{
+			Paging: sonarPaging{PageIndex: 1, PageSize: 100, Total: 1},
+			Issues: []sonarIssue{
+				{Key: "myIssueKey", Rule: "go:S1234", Project: "myProjectKey", Assignee: "alice"},
+			},
+		}
+		assert.NoError(t, json.NewEncoder(w).Encode(resp))
+	}))
+	defer ts.Close()
+
+	source := NewSonarQubeSource(ts.URL, "testToken", "myProjectKey")
+	now := time.Now()
+
+	events, _, _, err := source.Fetch(context.Background(), now, now, "")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(events))
+
+	// same issue returned again (e.g. overlapping since/until window) is not re-emitted
+	events, _, _, err = source.Fetch(context.Background(), now, now, "")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(events))
+}
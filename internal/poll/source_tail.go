@@ -0,0 +1,93 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package poll
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+)
+
+// TailSource is a ScoringSource reading one JSON-encoded types.ScoringMessage per line from a
+// local file that's expected to keep growing, e.g. an application log a sidecar or log-forwarder
+// writes to. Unlike FileSource (which reads its reader once to EOF and is done), TailSource
+// remembers how far it read via offset and picks back up there on every Fetch, so it never
+// reports done: ChaseTail's poll loop keeps re-checking it for newly appended lines.
+type TailSource struct {
+	path   string
+	offset int64
+}
+
+var _ ScoringSource = (*TailSource)(nil)
+
+// NewTailSource builds a TailSource reading scoring messages appended to the file at path.
+func NewTailSource(path string) *TailSource {
+	return &TailSource{path: path}
+}
+
+func (t *TailSource) Fetch(_ context.Context, _, _ time.Time, _ string) (events []ScoringEvent, nextCursor string, done bool, err error) {
+	done = true
+
+	f, err := os.Open(t.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// nothing written yet; not an error, just nothing to tail
+			err = nil
+		}
+		return
+	}
+	defer f.Close()
+
+	if _, err = f.Seek(t.offset, io.SeekStart); err != nil {
+		return
+	}
+
+	now := time.Now()
+	reader := bufio.NewReader(f)
+	for {
+		line, readErr := reader.ReadString('\n')
+		if line != "" && strings.HasSuffix(line, "\n") {
+			t.offset += int64(len(line))
+
+			trimmed := strings.TrimSpace(line)
+			if trimmed != "" {
+				var msg types.ScoringMessage
+				if err = json.Unmarshal([]byte(trimmed), &msg); err != nil {
+					return
+				}
+				events = append(events, ScoringEvent{EnvBaseTime: now, Message: msg})
+			}
+		}
+
+		if readErr != nil {
+			// io.EOF, possibly with a partial trailing line that hasn't been newline-terminated
+			// yet: leave offset before it, so the next Fetch re-reads it complete.
+			break
+		}
+	}
+
+	return
+}
@@ -0,0 +1,93 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package poll
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTailSourceMissingFileIsNotAnError(t *testing.T) {
+	source := NewTailSource(filepath.Join(t.TempDir(), "does-not-exist.log"))
+
+	events, _, done, err := source.Fetch(context.Background(), time.Time{}, time.Time{}, "")
+	assert.NoError(t, err)
+	assert.True(t, done)
+	assert.Equal(t, 0, len(events))
+}
+
+func TestTailSourceReadsAppendedLinesAcrossFetches(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scoring.log")
+	assert.NoError(t, os.WriteFile(path, []byte(`{"triggerUser":"alice"}`+"\n"), 0644))
+
+	source := NewTailSource(path)
+
+	events, _, done, err := source.Fetch(context.Background(), time.Time{}, time.Time{}, "")
+	assert.NoError(t, err)
+	assert.True(t, done)
+	assert.Equal(t, 1, len(events))
+	assert.Equal(t, "alice", events[0].Message.TriggerUser)
+
+	// nothing new appended yet
+	events, _, done, err = source.Fetch(context.Background(), time.Time{}, time.Time{}, "")
+	assert.NoError(t, err)
+	assert.True(t, done)
+	assert.Equal(t, 0, len(events))
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	assert.NoError(t, err)
+	_, err = f.WriteString(`{"triggerUser":"bob"}` + "\n")
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+
+	events, _, done, err = source.Fetch(context.Background(), time.Time{}, time.Time{}, "")
+	assert.NoError(t, err)
+	assert.True(t, done)
+	assert.Equal(t, 1, len(events))
+	assert.Equal(t, "bob", events[0].Message.TriggerUser)
+}
+
+func TestTailSourceDoesNotConsumePartialTrailingLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scoring.log")
+	assert.NoError(t, os.WriteFile(path, []byte(`{"triggerUser":"alice"}`), 0644))
+
+	source := NewTailSource(path)
+
+	events, _, _, err := source.Fetch(context.Background(), time.Time{}, time.Time{}, "")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(events))
+	assert.Equal(t, int64(0), source.offset)
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	assert.NoError(t, err)
+	_, err = f.WriteString("\n")
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+
+	events, _, _, err = source.Fetch(context.Background(), time.Time{}, time.Time{}, "")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(events))
+	assert.Equal(t, "alice", events[0].Message.TriggerUser)
+}
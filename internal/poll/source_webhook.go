@@ -0,0 +1,132 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package poll
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+)
+
+// IngestSignatureHeader carries the hex-encoded HMAC-SHA256 of the raw request body, keyed by a
+// signed WebhookSource's secret - the same convention source_webhook_gitea.go uses for forge
+// webhooks, applied here to the generic log-pipeline ingestion endpoint.
+const IngestSignatureHeader = "X-BBash-Signature"
+
+// WebhookSource is a ScoringSource fed by push rather than pull: ServeHTTP accepts either a
+// single types.ScoringMessage object or a JSON array of them (so a batching log pipeline like
+// Fluentd, Vector, or Loki can ship many at once) and queues them, and Fetch drains whatever has
+// queued up since the last call. It has no concept of since/until/cursor paging, since it never
+// reaches further back than "everything queued so far".
+type WebhookSource struct {
+	// secret, when non-empty, requires every request to carry a valid IngestSignatureHeader;
+	// empty disables verification, for sources fronted by a trusted network boundary instead.
+	secret string
+
+	mu    sync.Mutex
+	queue []ScoringEvent
+}
+
+var _ ScoringSource = (*WebhookSource)(nil)
+var _ http.Handler = (*WebhookSource)(nil)
+
+// NewWebhookSource builds an empty, unsigned WebhookSource, ready to be mounted as an http.Handler
+// and/or passed to ChaseTail/MultiSource.
+func NewWebhookSource() *WebhookSource {
+	return &WebhookSource{}
+}
+
+// NewSignedWebhookSource builds an empty WebhookSource that rejects any request not carrying a
+// valid IngestSignatureHeader signature of its body, keyed by secret.
+func NewSignedWebhookSource(secret string) *WebhookSource {
+	return &WebhookSource{secret: secret}
+}
+
+// ServeHTTP decodes one types.ScoringMessage, or a JSON array of them, from the request body and
+// queues each for the next Fetch call.
+func (w *WebhookSource) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if w.secret != "" && !validIngestSignature(body, r.Header.Get(IngestSignatureHeader), w.secret) {
+		http.Error(rw, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	now := time.Now()
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		var msgs []types.ScoringMessage
+		if err = json.Unmarshal(trimmed, &msgs); err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+		for _, msg := range msgs {
+			w.Ingest(msg, now)
+		}
+	} else {
+		var msg types.ScoringMessage
+		if err = json.Unmarshal(trimmed, &msg); err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Ingest(msg, now)
+	}
+
+	rw.WriteHeader(http.StatusAccepted)
+}
+
+// validIngestSignature reports whether signature is the hex-encoded HMAC-SHA256 of body, keyed by
+// secret.
+func validIngestSignature(body []byte, signature, secret string) bool {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	expected := hex.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+// Ingest queues msg for the next Fetch call, reported as having occurred at envBaseTime.
+func (w *WebhookSource) Ingest(msg types.ScoringMessage, envBaseTime time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.queue = append(w.queue, ScoringEvent{EnvBaseTime: envBaseTime, Message: msg})
+}
+
+func (w *WebhookSource) Fetch(_ context.Context, _, _ time.Time, _ string) (events []ScoringEvent, nextCursor string, done bool, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	events = w.queue
+	w.queue = nil
+	done = true
+	return
+}
@@ -0,0 +1,123 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package poll
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+)
+
+// giteaSignatureHeader and forgejoSignatureHeader carry the same hex-encoded HMAC-SHA256 of the
+// raw request body, keyed by the registered webhook secret; Forgejo (a Gitea fork) sends both for
+// compatibility, so either is accepted.
+const giteaSignatureHeader = "X-Gitea-Signature"
+const forgejoSignatureHeader = "X-Forgejo-Signature"
+
+// giteaMergedBugCategory is the BugStruct.Category a merged pull request is scored under, letting
+// admins price "a merged PR" the same way they price any other bug category.
+const giteaMergedBugCategory = "merged-pull-request"
+
+// giteaPullRequestPayload is the subset of Gitea/Forgejo's PullRequestPayload webhook body needed
+// to detect an actual merge (as opposed to any other "closed" transition) and attribute it.
+type giteaPullRequestPayload struct {
+	Action      string `json:"action"`
+	Number      int    `json:"number"`
+	PullRequest struct {
+		Merged         bool   `json:"merged"`
+		MergeCommitSha string `json:"merge_commit_sha"`
+		MergedBy       *struct {
+			Login string `json:"login"`
+		} `json:"merged_by"`
+		Base struct {
+			Repo struct {
+				Name  string `json:"name"`
+				Owner struct {
+					Login string `json:"login"`
+				} `json:"owner"`
+			} `json:"repo"`
+		} `json:"base"`
+	} `json:"pull_request"`
+}
+
+// giteaForgejoAdapter is a poll.WebhookAdapter for Gitea and Forgejo's PullRequestPayload webhook,
+// scoring only the "closed"+merged=true transition so opens/syncs/non-merge closes aren't scored.
+type giteaForgejoAdapter struct {
+	eventSource string
+}
+
+var _ WebhookAdapter = (*giteaForgejoAdapter)(nil)
+
+// NewGiteaForgejoAdapter builds a giteaForgejoAdapter reporting eventSource ("gitea" or "forgejo")
+// on every ScoringMessage it produces.
+func NewGiteaForgejoAdapter(eventSource string) *giteaForgejoAdapter {
+	return &giteaForgejoAdapter{eventSource: eventSource}
+}
+
+func (a *giteaForgejoAdapter) Verify(header http.Header, body []byte, secret string) (err error) {
+	signature := header.Get(giteaSignatureHeader)
+	if signature == "" {
+		signature = header.Get(forgejoSignatureHeader)
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	expected := hex.EncodeToString(mac.Sum(nil))
+
+	if !hmac.Equal([]byte(signature), []byte(expected)) {
+		err = fmt.Errorf("%s: invalid webhook signature", a.eventSource)
+	}
+	return
+}
+
+func (a *giteaForgejoAdapter) Parse(body []byte) (msg types.ScoringMessage, scored bool, err error) {
+	payload := giteaPullRequestPayload{}
+	if err = json.Unmarshal(body, &payload); err != nil {
+		return
+	}
+
+	if payload.Action != "closed" || !payload.PullRequest.Merged {
+		// opened/synchronized/reopened, or closed without merging: nothing to score
+		return
+	}
+
+	var triggerUser string
+	if payload.PullRequest.MergedBy != nil {
+		triggerUser = payload.PullRequest.MergedBy.Login
+	}
+
+	msg = types.ScoringMessage{
+		EventSource: a.eventSource,
+		RepoOwner:   payload.PullRequest.Base.Repo.Owner.Login,
+		RepoName:    payload.PullRequest.Base.Repo.Name,
+		TriggerUser: triggerUser,
+		TotalFixed:  1,
+		BugCounts:   map[string]int{giteaMergedBugCategory: 1},
+		PullRequest: payload.Number,
+		MergeSHA:    payload.PullRequest.MergeCommitSha,
+	}
+	scored = true
+	return
+}
@@ -0,0 +1,101 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package poll
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+)
+
+// gitlabTokenHeader carries the registered webhook secret verbatim; unlike Gitea/Forgejo, GitLab
+// doesn't sign the payload, it just echoes back the configured token.
+const gitlabTokenHeader = "X-Gitlab-Token"
+
+// gitlabMergedBugCategory is the BugStruct.Category a merged merge request is scored under.
+const gitlabMergedBugCategory = "merged-pull-request"
+
+// gitlabMergeRequestPayload is the subset of GitLab's "Merge Request Hook" body needed to detect
+// an actual merge (state == "merged") and attribute it.
+type gitlabMergeRequestPayload struct {
+	ObjectKind       string `json:"object_kind"`
+	ObjectAttributes struct {
+		Iid            int    `json:"iid"`
+		Action         string `json:"action"`
+		State          string `json:"state"`
+		MergeCommitSha string `json:"merge_commit_sha"`
+		Target         struct {
+			Name              string `json:"name"`
+			PathWithNamespace string `json:"path_with_namespace"`
+			Namespace         string `json:"namespace"`
+		} `json:"target"`
+	} `json:"object_attributes"`
+	User struct {
+		Username string `json:"username"`
+	} `json:"user"`
+}
+
+// gitlabAdapter is a poll.WebhookAdapter for GitLab's "Merge Request Hook" webhook, scoring only
+// the "merge" action with state "merged" so opens/updates/closes-without-merging aren't scored.
+type gitlabAdapter struct{}
+
+var _ WebhookAdapter = (*gitlabAdapter)(nil)
+
+// NewGitLabAdapter builds a gitlabAdapter.
+func NewGitLabAdapter() *gitlabAdapter {
+	return &gitlabAdapter{}
+}
+
+func (a *gitlabAdapter) Verify(header http.Header, _ []byte, secret string) (err error) {
+	token := header.Get(gitlabTokenHeader)
+	if subtle.ConstantTimeCompare([]byte(token), []byte(secret)) != 1 {
+		err = fmt.Errorf("gitlab: invalid webhook token")
+	}
+	return
+}
+
+func (a *gitlabAdapter) Parse(body []byte) (msg types.ScoringMessage, scored bool, err error) {
+	payload := gitlabMergeRequestPayload{}
+	if err = json.Unmarshal(body, &payload); err != nil {
+		return
+	}
+
+	if payload.ObjectKind != "merge_request" ||
+		payload.ObjectAttributes.Action != "merge" ||
+		payload.ObjectAttributes.State != "merged" {
+		return
+	}
+
+	msg = types.ScoringMessage{
+		EventSource: "gitlab",
+		RepoOwner:   payload.ObjectAttributes.Target.Namespace,
+		RepoName:    payload.ObjectAttributes.Target.Name,
+		TriggerUser: payload.User.Username,
+		TotalFixed:  1,
+		BugCounts:   map[string]int{gitlabMergedBugCategory: 1},
+		PullRequest: payload.ObjectAttributes.Iid,
+		MergeSHA:    payload.ObjectAttributes.MergeCommitSha,
+	}
+	scored = true
+	return
+}
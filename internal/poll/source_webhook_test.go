@@ -0,0 +1,118 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package poll
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookSourceFetchDrainsQueue(t *testing.T) {
+	source := NewWebhookSource()
+	source.Ingest(types.ScoringMessage{TriggerUser: "alice"}, time.Now())
+
+	events, _, done, err := source.Fetch(context.Background(), time.Time{}, time.Time{}, "")
+	assert.NoError(t, err)
+	assert.True(t, done)
+	assert.Equal(t, 1, len(events))
+	assert.Equal(t, "alice", events[0].Message.TriggerUser)
+
+	events, _, done, err = source.Fetch(context.Background(), time.Time{}, time.Time{}, "")
+	assert.NoError(t, err)
+	assert.True(t, done)
+	assert.Equal(t, 0, len(events))
+}
+
+func TestWebhookSourceServeHTTPQueuesMessage(t *testing.T) {
+	source := NewWebhookSource()
+
+	req := httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewReader([]byte(`{"triggerUser":"bob"}`)))
+	rec := httptest.NewRecorder()
+	source.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+
+	events, _, _, err := source.Fetch(context.Background(), time.Time{}, time.Time{}, "")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(events))
+	assert.Equal(t, "bob", events[0].Message.TriggerUser)
+}
+
+func TestWebhookSourceServeHTTPInvalidBody(t *testing.T) {
+	source := NewWebhookSource()
+
+	req := httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewReader([]byte("not json")))
+	rec := httptest.NewRecorder()
+	source.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestWebhookSourceServeHTTPQueuesBatch(t *testing.T) {
+	source := NewWebhookSource()
+
+	body := `[{"triggerUser":"alice"},{"triggerUser":"bob"}]`
+	req := httptest.NewRequest(http.MethodPost, "/scoring/ingest", bytes.NewReader([]byte(body)))
+	rec := httptest.NewRecorder()
+	source.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+
+	events, _, _, err := source.Fetch(context.Background(), time.Time{}, time.Time{}, "")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(events))
+	assert.Equal(t, "alice", events[0].Message.TriggerUser)
+	assert.Equal(t, "bob", events[1].Message.TriggerUser)
+}
+
+func TestSignedWebhookSourceRejectsMissingOrWrongSignature(t *testing.T) {
+	source := NewSignedWebhookSource("s3cr3t")
+
+	req := httptest.NewRequest(http.MethodPost, "/scoring/ingest", bytes.NewReader([]byte(`{"triggerUser":"alice"}`)))
+	rec := httptest.NewRecorder()
+	source.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestSignedWebhookSourceAcceptsValidSignature(t *testing.T) {
+	source := NewSignedWebhookSource("s3cr3t")
+
+	body := []byte(`{"triggerUser":"alice"}`)
+	mac := hmac.New(sha256.New, []byte("s3cr3t"))
+	mac.Write(body)
+	signature := hex.EncodeToString(mac.Sum(nil))
+
+	req := httptest.NewRequest(http.MethodPost, "/scoring/ingest", bytes.NewReader(body))
+	req.Header.Set(IngestSignatureHeader, signature)
+	rec := httptest.NewRecorder()
+	source.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+
+	events, _, _, err := source.Fetch(context.Background(), time.Time{}, time.Time{}, "")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(events))
+}
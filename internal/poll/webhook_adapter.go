@@ -0,0 +1,56 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package poll
+
+import (
+	"net/http"
+
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+)
+
+// WebhookAdapter normalizes one source control provider's native webhook payload into a
+// types.ScoringMessage. Unlike ScoringSource, an adapter doesn't poll or queue anything itself; the
+// caller (the /webhook/{scpName} handler) owns reading the request body and feeding
+// processScoringMessage, so adapters stay pure functions of (headers, body, secret) and are easy to
+// unit test without an HTTP server.
+type WebhookAdapter interface {
+	// Verify checks the provider's signature/token header against secret, returning a non-nil error
+	// if the request shouldn't be trusted.
+	Verify(header http.Header, body []byte, secret string) error
+
+	// Parse normalizes body into a ScoringMessage. scored is false for events this adapter
+	// recognizes but that aren't an actual merge (e.g. a PR opened or closed-without-merging), so
+	// the caller can ack the webhook without awarding any points.
+	Parse(body []byte) (msg types.ScoringMessage, scored bool, err error)
+}
+
+// webhookAdapters maps a registered types.SourceControlProviderStruct's Kind to the adapter that
+// understands its webhook payload.
+var webhookAdapters = map[string]WebhookAdapter{
+	"gitea":   NewGiteaForgejoAdapter("gitea"),
+	"forgejo": NewGiteaForgejoAdapter("forgejo"),
+	"gitlab":  NewGitLabAdapter(),
+}
+
+// WebhookAdapterForKind returns the adapter registered for kind, and whether one exists.
+func WebhookAdapterForKind(kind string) (adapter WebhookAdapter, ok bool) {
+	adapter, ok = webhookAdapters[kind]
+	return
+}
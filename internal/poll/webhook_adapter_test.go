@@ -0,0 +1,137 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package poll
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookAdapterForKind(t *testing.T) {
+	for _, kind := range []string{"gitea", "forgejo", "gitlab"} {
+		adapter, ok := WebhookAdapterForKind(kind)
+		assert.True(t, ok, kind)
+		assert.NotNil(t, adapter, kind)
+	}
+
+	_, ok := WebhookAdapterForKind("bitbucket")
+	assert.False(t, ok)
+}
+
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestGiteaForgejoAdapterVerify(t *testing.T) {
+	adapter := NewGiteaForgejoAdapter("gitea")
+	body := []byte(`{"action":"closed"}`)
+
+	header := http.Header{}
+	header.Set(giteaSignatureHeader, sign("shh", body))
+	assert.NoError(t, adapter.Verify(header, body, "shh"))
+
+	header = http.Header{}
+	header.Set(giteaSignatureHeader, sign("wrong", body))
+	assert.Error(t, adapter.Verify(header, body, "shh"))
+}
+
+func TestGiteaForgejoAdapterParseMergedPR(t *testing.T) {
+	adapter := NewGiteaForgejoAdapter("gitea")
+	body := []byte(`{
+		"action": "closed",
+		"number": 42,
+		"pull_request": {
+			"merged": true,
+			"merged_by": {"login": "alice"},
+			"base": {"repo": {"name": "myRepo", "owner": {"login": "myOrg"}}}
+		}
+	}`)
+
+	msg, scored, err := adapter.Parse(body)
+	assert.NoError(t, err)
+	assert.True(t, scored)
+	assert.Equal(t, "gitea", msg.EventSource)
+	assert.Equal(t, "myOrg", msg.RepoOwner)
+	assert.Equal(t, "myRepo", msg.RepoName)
+	assert.Equal(t, "alice", msg.TriggerUser)
+	assert.Equal(t, 42, msg.PullRequest)
+	assert.Equal(t, 1, msg.BugCounts[giteaMergedBugCategory])
+}
+
+func TestGiteaForgejoAdapterParseClosedWithoutMerge(t *testing.T) {
+	adapter := NewGiteaForgejoAdapter("gitea")
+	body := []byte(`{"action": "closed", "pull_request": {"merged": false}}`)
+
+	_, scored, err := adapter.Parse(body)
+	assert.NoError(t, err)
+	assert.False(t, scored)
+}
+
+func TestGitLabAdapterVerify(t *testing.T) {
+	adapter := NewGitLabAdapter()
+
+	header := http.Header{}
+	header.Set(gitlabTokenHeader, "shh")
+	assert.NoError(t, adapter.Verify(header, nil, "shh"))
+
+	header = http.Header{}
+	header.Set(gitlabTokenHeader, "wrong")
+	assert.Error(t, adapter.Verify(header, nil, "shh"))
+}
+
+func TestGitLabAdapterParseMergedMR(t *testing.T) {
+	adapter := NewGitLabAdapter()
+	body := []byte(`{
+		"object_kind": "merge_request",
+		"object_attributes": {
+			"iid": 7,
+			"action": "merge",
+			"state": "merged",
+			"target": {"name": "myRepo", "namespace": "myOrg"}
+		},
+		"user": {"username": "bob"}
+	}`)
+
+	msg, scored, err := adapter.Parse(body)
+	assert.NoError(t, err)
+	assert.True(t, scored)
+	assert.Equal(t, "gitlab", msg.EventSource)
+	assert.Equal(t, "myOrg", msg.RepoOwner)
+	assert.Equal(t, "myRepo", msg.RepoName)
+	assert.Equal(t, "bob", msg.TriggerUser)
+	assert.Equal(t, 7, msg.PullRequest)
+	assert.Equal(t, 1, msg.BugCounts[gitlabMergedBugCategory])
+}
+
+func TestGitLabAdapterParseNonMergeAction(t *testing.T) {
+	adapter := NewGitLabAdapter()
+	body := []byte(`{"object_kind": "merge_request", "object_attributes": {"action": "close", "state": "closed"}}`)
+
+	_, scored, err := adapter.Parse(body)
+	assert.NoError(t, err)
+	assert.False(t, scored)
+}
@@ -0,0 +1,136 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package scp
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+)
+
+// bitbucketEventSource is the ScoringMessage.EventSource value Bitbucket-originated events carry.
+const bitbucketEventSource = "bitbucket"
+
+// bitbucketSignatureHeader carries a hex-encoded HMAC-SHA256 of the raw request body, keyed by the
+// registered webhook secret, the same convention Bitbucket Server uses for its webhooks.
+const bitbucketSignatureHeader = "X-Hub-Signature"
+
+// bitbucketMergedBugCategory is the BugStruct.Category a merged pull request is scored under,
+// letting admins price "a merged PR" the same way they price any other bug category.
+const bitbucketMergedBugCategory = "merged-pull-request"
+
+// bitbucketPullRequestPayload is the subset of Bitbucket's pullrequest:fulfilled webhook body
+// needed to detect an actual merge (merge_commit is only populated once a pull request is merged)
+// and attribute it.
+type bitbucketPullRequestPayload struct {
+	PullRequest struct {
+		ID          int `json:"id"`
+		MergeCommit struct {
+			Hash string `json:"hash"`
+		} `json:"merge_commit"`
+		Destination struct {
+			Repository struct {
+				Name      string `json:"name"`
+				Workspace struct {
+					Slug string `json:"slug"`
+				} `json:"workspace"`
+			} `json:"repository"`
+		} `json:"destination"`
+	} `json:"pullrequest"`
+	Actor struct {
+		Username string `json:"username"`
+	} `json:"actor"`
+}
+
+// bitbucketWorkspacePattern matches a Bitbucket workspace identifier: either a slug or a UUID
+// wrapped in curly braces, Bitbucket's own workspace-id format.
+var bitbucketWorkspacePattern = regexp.MustCompile(`^(\{[0-9a-fA-F-]{36}\}|[a-zA-Z0-9_-]+)$`)
+
+// bitbucketProvider implements SCPProvider for Bitbucket: RepoOwner is a workspace slug or UUID,
+// and pull requests live under "pull-requests" rather than GitHub's "pull".
+type bitbucketProvider struct{}
+
+func (bitbucketProvider) Name() string { return bitbucketEventSource }
+
+func (bitbucketProvider) ValidateOrg(_ context.Context, org string) (err error) {
+	if !bitbucketWorkspacePattern.MatchString(org) {
+		return fmt.Errorf("bitbucket: %q is not a valid workspace slug or UUID", org)
+	}
+	return nil
+}
+
+func (bitbucketProvider) NormalizeLogin(login string) string {
+	return strings.ToLower(login)
+}
+
+func (p bitbucketProvider) EnrichScoringMessage(_ context.Context, msg types.ScoringMessage) (types.ScoringMessage, error) {
+	msg.TriggerUser = p.NormalizeLogin(msg.TriggerUser)
+	return msg, nil
+}
+
+func (bitbucketProvider) PullRequestURL(msg types.ScoringMessage) string {
+	return fmt.Sprintf("https://bitbucket.org/%s/%s/pull-requests/%d", msg.RepoOwner, msg.RepoName, msg.PullRequest)
+}
+
+func (bitbucketProvider) VerifySignature(header http.Header, body []byte, secret string) (err error) {
+	signature := header.Get(bitbucketSignatureHeader)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	expected := hex.EncodeToString(mac.Sum(nil))
+
+	if !hmac.Equal([]byte(signature), []byte(expected)) {
+		err = fmt.Errorf("bitbucket: invalid webhook signature")
+	}
+	return
+}
+
+func (p bitbucketProvider) ParseWebhook(body []byte) (msg types.ScoringMessage, scored bool, err error) {
+	payload := bitbucketPullRequestPayload{}
+	if err = json.Unmarshal(body, &payload); err != nil {
+		return
+	}
+
+	if payload.PullRequest.MergeCommit.Hash == "" {
+		// opened/updated, or declined/closed without merging: nothing to score
+		return
+	}
+
+	msg = types.ScoringMessage{
+		EventSource: bitbucketEventSource,
+		RepoOwner:   payload.PullRequest.Destination.Repository.Workspace.Slug,
+		RepoName:    payload.PullRequest.Destination.Repository.Name,
+		TriggerUser: p.NormalizeLogin(payload.Actor.Username),
+		TotalFixed:  1,
+		BugCounts:   map[string]int{bitbucketMergedBugCategory: 1},
+		PullRequest: payload.PullRequest.ID,
+		MergeSHA:    payload.PullRequest.MergeCommit.Hash,
+	}
+	scored = true
+	return
+}
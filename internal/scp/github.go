@@ -0,0 +1,142 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package scp
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+)
+
+// githubEventSource is the ScoringMessage.EventSource value GitHub-originated events carry.
+const githubEventSource = "github"
+
+// githubSignatureHeader carries a hex-encoded HMAC-SHA256 of the raw request body, prefixed with
+// "sha256=", keyed by the registered webhook secret.
+const githubSignatureHeader = "X-Hub-Signature-256"
+
+// githubSignaturePrefix is stripped from githubSignatureHeader before comparing against the
+// locally computed HMAC.
+const githubSignaturePrefix = "sha256="
+
+// githubMergedBugCategory is the BugStruct.Category a merged pull request is scored under, letting
+// admins price "a merged PR" the same way they price any other bug category.
+const githubMergedBugCategory = "merged-pull-request"
+
+// githubPullRequestPayload is the subset of GitHub's "pull_request" webhook body needed to detect
+// an actual merge (as opposed to any other "closed" transition) and attribute it.
+type githubPullRequestPayload struct {
+	Action      string `json:"action"`
+	Number      int    `json:"number"`
+	PullRequest struct {
+		Merged         bool   `json:"merged"`
+		MergeCommitSha string `json:"merge_commit_sha"`
+		MergedBy       *struct {
+			Login string `json:"login"`
+		} `json:"merged_by"`
+		Base struct {
+			Repo struct {
+				Name  string `json:"name"`
+				Owner struct {
+					Login string `json:"login"`
+				} `json:"owner"`
+			} `json:"repo"`
+		} `json:"base"`
+	} `json:"pull_request"`
+}
+
+// githubProvider implements SCPProvider for GitHub: a flat owner/repo organization and a
+// case-insensitive login, matching GitHub's own semantics.
+type githubProvider struct{}
+
+func (githubProvider) Name() string { return githubEventSource }
+
+func (githubProvider) ValidateOrg(_ context.Context, org string) (err error) {
+	if strings.TrimSpace(org) == "" {
+		return fmt.Errorf("github: organization must not be empty")
+	}
+	if strings.ContainsAny(org, "/ ") {
+		return fmt.Errorf("github: organization %q must not contain '/' or spaces", org)
+	}
+	return nil
+}
+
+func (githubProvider) NormalizeLogin(login string) string {
+	return strings.ToLower(login)
+}
+
+func (p githubProvider) EnrichScoringMessage(_ context.Context, msg types.ScoringMessage) (types.ScoringMessage, error) {
+	msg.TriggerUser = p.NormalizeLogin(msg.TriggerUser)
+	return msg, nil
+}
+
+func (githubProvider) PullRequestURL(msg types.ScoringMessage) string {
+	return fmt.Sprintf("https://github.com/%s/%s/pull/%d", msg.RepoOwner, msg.RepoName, msg.PullRequest)
+}
+
+func (githubProvider) VerifySignature(header http.Header, body []byte, secret string) (err error) {
+	signature := strings.TrimPrefix(header.Get(githubSignatureHeader), githubSignaturePrefix)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	expected := hex.EncodeToString(mac.Sum(nil))
+
+	if !hmac.Equal([]byte(signature), []byte(expected)) {
+		err = fmt.Errorf("github: invalid webhook signature")
+	}
+	return
+}
+
+func (p githubProvider) ParseWebhook(body []byte) (msg types.ScoringMessage, scored bool, err error) {
+	payload := githubPullRequestPayload{}
+	if err = json.Unmarshal(body, &payload); err != nil {
+		return
+	}
+
+	if payload.Action != "closed" || !payload.PullRequest.Merged {
+		// opened/synchronized/reopened, or closed without merging: nothing to score
+		return
+	}
+
+	var triggerUser string
+	if payload.PullRequest.MergedBy != nil {
+		triggerUser = p.NormalizeLogin(payload.PullRequest.MergedBy.Login)
+	}
+
+	msg = types.ScoringMessage{
+		EventSource: githubEventSource,
+		RepoOwner:   payload.PullRequest.Base.Repo.Owner.Login,
+		RepoName:    payload.PullRequest.Base.Repo.Name,
+		TriggerUser: triggerUser,
+		TotalFixed:  1,
+		BugCounts:   map[string]int{githubMergedBugCategory: 1},
+		PullRequest: payload.Number,
+		MergeSHA:    payload.PullRequest.MergeCommitSha,
+	}
+	scored = true
+	return
+}
@@ -0,0 +1,129 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package scp
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+)
+
+// gitlabEventSource is the ScoringMessage.EventSource value GitLab-originated events carry.
+const gitlabEventSource = "gitlab"
+
+// gitlabTokenHeader carries the registered webhook secret verbatim; unlike Gitea/Forgejo, GitLab
+// doesn't sign the payload, it just echoes back the configured token.
+const gitlabTokenHeader = "X-Gitlab-Token"
+
+// gitlabMergedBugCategory is the BugStruct.Category a merged merge request is scored under.
+const gitlabMergedBugCategory = "merged-pull-request"
+
+// gitlabMergeRequestPayload is the subset of GitLab's "Merge Request Hook" body needed to detect
+// an actual merge (state == "merged") and attribute it.
+type gitlabMergeRequestPayload struct {
+	ObjectKind       string `json:"object_kind"`
+	ObjectAttributes struct {
+		Iid            int    `json:"iid"`
+		Action         string `json:"action"`
+		State          string `json:"state"`
+		MergeCommitSha string `json:"merge_commit_sha"`
+		Target         struct {
+			Name              string `json:"name"`
+			PathWithNamespace string `json:"path_with_namespace"`
+			Namespace         string `json:"namespace"`
+		} `json:"target"`
+	} `json:"object_attributes"`
+	User struct {
+		Username string `json:"username"`
+	} `json:"user"`
+}
+
+// gitlabProvider implements SCPProvider for GitLab: RepoOwner may be a nested group path (e.g.
+// "group/subgroup") rather than GitHub's flat org, and merge requests link with
+// "-/merge_requests" instead of GitHub's "/pull".
+type gitlabProvider struct{}
+
+func (gitlabProvider) Name() string { return gitlabEventSource }
+
+func (gitlabProvider) ValidateOrg(_ context.Context, org string) (err error) {
+	trimmed := strings.Trim(org, "/")
+	if trimmed == "" {
+		return fmt.Errorf("gitlab: group path must not be empty")
+	}
+	for _, segment := range strings.Split(trimmed, "/") {
+		if strings.TrimSpace(segment) == "" {
+			return fmt.Errorf("gitlab: group path %q must not contain empty segments", org)
+		}
+	}
+	return nil
+}
+
+func (gitlabProvider) NormalizeLogin(login string) string {
+	return strings.ToLower(login)
+}
+
+func (p gitlabProvider) EnrichScoringMessage(_ context.Context, msg types.ScoringMessage) (types.ScoringMessage, error) {
+	msg.TriggerUser = p.NormalizeLogin(msg.TriggerUser)
+	msg.RepoOwner = strings.Trim(msg.RepoOwner, "/")
+	return msg, nil
+}
+
+func (gitlabProvider) PullRequestURL(msg types.ScoringMessage) string {
+	return fmt.Sprintf("https://gitlab.com/%s/%s/-/merge_requests/%d", msg.RepoOwner, msg.RepoName, msg.PullRequest)
+}
+
+func (gitlabProvider) VerifySignature(header http.Header, _ []byte, secret string) (err error) {
+	token := header.Get(gitlabTokenHeader)
+	if subtle.ConstantTimeCompare([]byte(token), []byte(secret)) != 1 {
+		err = fmt.Errorf("gitlab: invalid webhook token")
+	}
+	return
+}
+
+func (gitlabProvider) ParseWebhook(body []byte) (msg types.ScoringMessage, scored bool, err error) {
+	payload := gitlabMergeRequestPayload{}
+	if err = json.Unmarshal(body, &payload); err != nil {
+		return
+	}
+
+	if payload.ObjectKind != "merge_request" ||
+		payload.ObjectAttributes.Action != "merge" ||
+		payload.ObjectAttributes.State != "merged" {
+		return
+	}
+
+	msg = types.ScoringMessage{
+		EventSource: gitlabEventSource,
+		RepoOwner:   payload.ObjectAttributes.Target.Namespace,
+		RepoName:    payload.ObjectAttributes.Target.Name,
+		TriggerUser: payload.User.Username,
+		TotalFixed:  1,
+		BugCounts:   map[string]int{gitlabMergedBugCategory: 1},
+		PullRequest: payload.ObjectAttributes.Iid,
+		MergeSHA:    payload.ObjectAttributes.MergeCommitSha,
+	}
+	scored = true
+	return
+}
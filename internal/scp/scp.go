@@ -0,0 +1,82 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+// Package scp holds the source-control-provider-specific rules a ScoringMessage's EventSource
+// selects: how its org/group identifier is validated, how a login is normalized for participant
+// matching, how the message is enriched before scoring, how its native webhook is authenticated
+// and parsed, and how to link back to the pull request it scored. Without this package those rules
+// were hard-coded GitHub assumptions (a flat org name, a case-insensitive login) sprinkled through
+// server.go's scoring path; registering one SCPProvider per EventSource here lets GitLab (nested
+// group paths) and Bitbucket (workspace UUIDs) apply their own instead, and lets a third party
+// Register a new SCP without patching server.go's handlers.
+package scp
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+)
+
+// SCPProvider applies one source control provider's own rules to a ScoringMessage, keyed by its
+// EventSource.
+type SCPProvider interface {
+	// Name returns the EventSource this provider is registered under.
+	Name() string
+	// ValidateOrg reports whether org is a well-formed organization/group/workspace identifier
+	// for this SCP, independent of whether it's actually registered with bbash - that existence
+	// check stays db.IBBashDB.ValidOrganization's job.
+	ValidateOrg(ctx context.Context, org string) error
+	// NormalizeLogin canonicalizes login the way this SCP does, so a scoring event matches the
+	// participant row it's for regardless of the case (or other per-SCP quirk) it arrived in.
+	NormalizeLogin(login string) string
+	// EnrichScoringMessage applies this SCP's normalization to msg before it's scored (e.g.
+	// NormalizeLogin-ing TriggerUser, trimming a GitLab group path) and returns the result.
+	EnrichScoringMessage(ctx context.Context, msg types.ScoringMessage) (types.ScoringMessage, error)
+	// PullRequestURL returns the browsable URL for the pull/merge request msg scored.
+	PullRequestURL(msg types.ScoringMessage) string
+	// VerifySignature checks this SCP's signature/token header against secret, returning a non-nil
+	// error if the webhook shouldn't be trusted.
+	VerifySignature(header http.Header, body []byte, secret string) error
+	// ParseWebhook normalizes body into a ScoringMessage. scored is false for events this provider
+	// recognizes but that aren't an actual merge (e.g. a PR opened or closed-without-merging), so
+	// the caller can ack the webhook without awarding any points.
+	ParseWebhook(body []byte) (msg types.ScoringMessage, scored bool, err error)
+}
+
+// providers maps a registered EventSource to the SCPProvider that knows that SCP's rules,
+// mirroring internal/status.reporters and internal/poll.webhookAdapters.
+var providers = map[string]SCPProvider{
+	githubEventSource:    githubProvider{},
+	gitlabEventSource:    gitlabProvider{},
+	bitbucketEventSource: bitbucketProvider{},
+}
+
+// ForEventSource returns the SCPProvider registered for eventSource, and whether one exists.
+func ForEventSource(eventSource string) (provider SCPProvider, ok bool) {
+	provider, ok = providers[eventSource]
+	return
+}
+
+// Register adds provider to the registry under provider.Name(), overwriting any provider already
+// registered under that name. This is how a third party SCP (or a test's mocks.SCPProvider) is
+// plugged in without patching server.go's handlers.
+func Register(provider SCPProvider) {
+	providers[provider.Name()] = provider
+}
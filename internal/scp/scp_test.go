@@ -0,0 +1,253 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package scp
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"testing"
+
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestForEventSource(t *testing.T) {
+	for _, eventSource := range []string{"github", "gitlab", "bitbucket"} {
+		provider, ok := ForEventSource(eventSource)
+		assert.True(t, ok, eventSource)
+		assert.Equal(t, eventSource, provider.Name())
+	}
+
+	_, ok := ForEventSource("sonarqube")
+	assert.False(t, ok)
+}
+
+// fakeProvider is a minimal SCPProvider used by TestRegister to confirm a new SCP can be plugged
+// in without touching this package's built-ins.
+type fakeProvider struct{}
+
+func (fakeProvider) Name() string                                      { return "sonarqube" }
+func (fakeProvider) ValidateOrg(context.Context, string) error         { return nil }
+func (fakeProvider) NormalizeLogin(login string) string                { return login }
+func (fakeProvider) PullRequestURL(types.ScoringMessage) string        { return "" }
+func (fakeProvider) VerifySignature(http.Header, []byte, string) error { return nil }
+func (fakeProvider) ParseWebhook(body []byte) (types.ScoringMessage, bool, error) {
+	return types.ScoringMessage{}, false, nil
+}
+func (p fakeProvider) EnrichScoringMessage(_ context.Context, msg types.ScoringMessage) (types.ScoringMessage, error) {
+	return msg, nil
+}
+
+func TestRegister(t *testing.T) {
+	defer delete(providers, "sonarqube")
+
+	_, ok := ForEventSource("sonarqube")
+	assert.False(t, ok)
+
+	Register(fakeProvider{})
+
+	provider, ok := ForEventSource("sonarqube")
+	assert.True(t, ok)
+	assert.Equal(t, "sonarqube", provider.Name())
+}
+
+func TestGithubProvider(t *testing.T) {
+	p := githubProvider{}
+	assert.NoError(t, p.ValidateOrg(context.Background(), "sonatype-nexus-community"))
+	assert.Error(t, p.ValidateOrg(context.Background(), ""))
+	assert.Error(t, p.ValidateOrg(context.Background(), "group/subgroup"))
+	assert.Equal(t, "octocat", p.NormalizeLogin("OctoCat"))
+
+	msg, err := p.EnrichScoringMessage(context.Background(), types.ScoringMessage{TriggerUser: "OctoCat"})
+	assert.NoError(t, err)
+	assert.Equal(t, "octocat", msg.TriggerUser)
+
+	msg = types.ScoringMessage{RepoOwner: "sonatype-nexus-community", RepoName: "bbash", PullRequest: 42}
+	assert.Equal(t, "https://github.com/sonatype-nexus-community/bbash/pull/42", p.PullRequestURL(msg))
+}
+
+func TestGithubProviderVerifySignature(t *testing.T) {
+	p := githubProvider{}
+	body := []byte(`{"action":"closed"}`)
+
+	header := http.Header{}
+	header.Set(githubSignatureHeader, githubSignaturePrefix+sign("shh", body))
+	assert.NoError(t, p.VerifySignature(header, body, "shh"))
+
+	header = http.Header{}
+	header.Set(githubSignatureHeader, githubSignaturePrefix+sign("wrong", body))
+	assert.Error(t, p.VerifySignature(header, body, "shh"))
+}
+
+func TestGithubProviderParseWebhookMergedPR(t *testing.T) {
+	p := githubProvider{}
+	body := []byte(`{
+		"action": "closed",
+		"number": 42,
+		"pull_request": {
+			"merged": true,
+			"merge_commit_sha": "abc123",
+			"merged_by": {"login": "OctoCat"},
+			"base": {"repo": {"name": "bbash", "owner": {"login": "sonatype-nexus-community"}}}
+		}
+	}`)
+
+	msg, scored, err := p.ParseWebhook(body)
+	assert.NoError(t, err)
+	assert.True(t, scored)
+	assert.Equal(t, "github", msg.EventSource)
+	assert.Equal(t, "sonatype-nexus-community", msg.RepoOwner)
+	assert.Equal(t, "bbash", msg.RepoName)
+	assert.Equal(t, "octocat", msg.TriggerUser)
+	assert.Equal(t, 42, msg.PullRequest)
+	assert.Equal(t, 1, msg.BugCounts[githubMergedBugCategory])
+}
+
+func TestGithubProviderParseWebhookClosedWithoutMerge(t *testing.T) {
+	p := githubProvider{}
+	body := []byte(`{"action": "closed", "pull_request": {"merged": false}}`)
+
+	_, scored, err := p.ParseWebhook(body)
+	assert.NoError(t, err)
+	assert.False(t, scored)
+}
+
+func TestGitlabProvider(t *testing.T) {
+	p := gitlabProvider{}
+	assert.NoError(t, p.ValidateOrg(context.Background(), "group/subgroup"))
+	assert.Error(t, p.ValidateOrg(context.Background(), ""))
+	assert.Error(t, p.ValidateOrg(context.Background(), "group//subgroup"))
+
+	msg, err := p.EnrichScoringMessage(context.Background(), types.ScoringMessage{TriggerUser: "OctoCat", RepoOwner: "/group/subgroup/"})
+	assert.NoError(t, err)
+	assert.Equal(t, "octocat", msg.TriggerUser)
+	assert.Equal(t, "group/subgroup", msg.RepoOwner)
+
+	msg = types.ScoringMessage{RepoOwner: "group/subgroup", RepoName: "bbash", PullRequest: 7}
+	assert.Equal(t, "https://gitlab.com/group/subgroup/bbash/-/merge_requests/7", p.PullRequestURL(msg))
+}
+
+func TestGitlabProviderVerifySignature(t *testing.T) {
+	p := gitlabProvider{}
+
+	header := http.Header{}
+	header.Set(gitlabTokenHeader, "shh")
+	assert.NoError(t, p.VerifySignature(header, nil, "shh"))
+
+	header = http.Header{}
+	header.Set(gitlabTokenHeader, "wrong")
+	assert.Error(t, p.VerifySignature(header, nil, "shh"))
+}
+
+func TestGitlabProviderParseWebhookMergedMR(t *testing.T) {
+	p := gitlabProvider{}
+	body := []byte(`{
+		"object_kind": "merge_request",
+		"object_attributes": {
+			"iid": 7,
+			"action": "merge",
+			"state": "merged",
+			"target": {"name": "bbash", "namespace": "group/subgroup"}
+		},
+		"user": {"username": "bob"}
+	}`)
+
+	msg, scored, err := p.ParseWebhook(body)
+	assert.NoError(t, err)
+	assert.True(t, scored)
+	assert.Equal(t, "gitlab", msg.EventSource)
+	assert.Equal(t, "group/subgroup", msg.RepoOwner)
+	assert.Equal(t, "bbash", msg.RepoName)
+	assert.Equal(t, 7, msg.PullRequest)
+	assert.Equal(t, 1, msg.BugCounts[gitlabMergedBugCategory])
+}
+
+func TestGitlabProviderParseWebhookNonMergeAction(t *testing.T) {
+	p := gitlabProvider{}
+	body := []byte(`{"object_kind": "merge_request", "object_attributes": {"action": "close", "state": "closed"}}`)
+
+	_, scored, err := p.ParseWebhook(body)
+	assert.NoError(t, err)
+	assert.False(t, scored)
+}
+
+func TestBitbucketProvider(t *testing.T) {
+	p := bitbucketProvider{}
+	assert.NoError(t, p.ValidateOrg(context.Background(), "my-workspace"))
+	assert.NoError(t, p.ValidateOrg(context.Background(), "{3b241101-e2bb-4255-8caf-4136c566a962}"))
+	assert.Error(t, p.ValidateOrg(context.Background(), "not a workspace"))
+	assert.Equal(t, "octocat", p.NormalizeLogin("OctoCat"))
+
+	msg := types.ScoringMessage{RepoOwner: "my-workspace", RepoName: "bbash", PullRequest: 3}
+	assert.Equal(t, "https://bitbucket.org/my-workspace/bbash/pull-requests/3", p.PullRequestURL(msg))
+}
+
+func TestBitbucketProviderVerifySignature(t *testing.T) {
+	p := bitbucketProvider{}
+	body := []byte(`{"pullrequest":{"id":3}}`)
+
+	header := http.Header{}
+	header.Set(bitbucketSignatureHeader, sign("shh", body))
+	assert.NoError(t, p.VerifySignature(header, body, "shh"))
+
+	header = http.Header{}
+	header.Set(bitbucketSignatureHeader, sign("wrong", body))
+	assert.Error(t, p.VerifySignature(header, body, "shh"))
+}
+
+func TestBitbucketProviderParseWebhookMergedPR(t *testing.T) {
+	p := bitbucketProvider{}
+	body := []byte(`{
+		"pullrequest": {
+			"id": 3,
+			"merge_commit": {"hash": "abc123"},
+			"destination": {"repository": {"name": "bbash", "workspace": {"slug": "my-workspace"}}}
+		},
+		"actor": {"username": "OctoCat"}
+	}`)
+
+	msg, scored, err := p.ParseWebhook(body)
+	assert.NoError(t, err)
+	assert.True(t, scored)
+	assert.Equal(t, "bitbucket", msg.EventSource)
+	assert.Equal(t, "my-workspace", msg.RepoOwner)
+	assert.Equal(t, "bbash", msg.RepoName)
+	assert.Equal(t, "octocat", msg.TriggerUser)
+	assert.Equal(t, 3, msg.PullRequest)
+	assert.Equal(t, 1, msg.BugCounts[bitbucketMergedBugCategory])
+}
+
+func TestBitbucketProviderParseWebhookNotMerged(t *testing.T) {
+	p := bitbucketProvider{}
+	body := []byte(`{"pullrequest": {"id": 3}}`)
+
+	_, scored, err := p.ParseWebhook(body)
+	assert.NoError(t, err)
+	assert.False(t, scored)
+}
@@ -0,0 +1,95 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package status
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+)
+
+// githubAPI is GitHub's fixed API root; Gitea/Forgejo are self-hosted, so their root is always
+// derived from the registered provider's own Url instead.
+const githubAPI = "https://api.github.com"
+
+// forgeStatusBody is the JSON body GitHub, Gitea, and Forgejo all accept, unchanged, at
+// POST .../repos/{owner}/{repo}/statuses/{sha}.
+type forgeStatusBody struct {
+	State       string `json:"state"`
+	TargetURL   string `json:"target_url,omitempty"`
+	Description string `json:"description,omitempty"`
+	Context     string `json:"context,omitempty"`
+}
+
+// forgeReporter posts a commit status via the GitHub-shaped status API that GitHub, Gitea, and
+// Forgejo all implement identically.
+type forgeReporter struct {
+	baseURL string // fixed API root (GitHub); empty means derive it from scp.Url (Gitea/Forgejo)
+	client  *http.Client
+}
+
+func newForgeReporter(baseURL string) *forgeReporter {
+	return &forgeReporter{baseURL: baseURL, client: newStatusHTTPClient()}
+}
+
+var _ StatusReporter = (*forgeReporter)(nil)
+
+func (r *forgeReporter) ReportStatus(ctx context.Context, scp *types.SourceControlProviderStruct, status types.CommitStatusStruct) (err error) {
+	base := r.baseURL
+	if base == "" {
+		base = strings.TrimSuffix(scp.Url, "/") + "/api/v1"
+	}
+	url := fmt.Sprintf("%s/repos/%s/%s/statuses/%s", base, status.RepoOwner, status.RepoName, status.SHA)
+
+	body, err := json.Marshal(forgeStatusBody{
+		State:       status.State,
+		TargetURL:   status.TargetURL,
+		Description: status.Description,
+		Context:     status.Context,
+	})
+	if err != nil {
+		return
+	}
+
+	var req *http.Request
+	if req, err = http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body)); err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "token "+scp.ReportingToken)
+
+	var res *http.Response
+	if res, err = r.client.Do(req); err != nil {
+		return
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	if res.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(res.Body)
+		err = fmt.Errorf("%s: commit status post failed: %s: %s", scp.SCPName, res.Status, string(respBody))
+	}
+	return
+}
@@ -0,0 +1,70 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+)
+
+func TestForgeReporterReportStatus(t *testing.T) {
+	var gotBody forgeStatusBody
+	var gotPath, gotAuth string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotAuth = r.Header.Get("Authorization")
+		_ = json.NewDecoder(r.Body).Decode(&gotBody)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer ts.Close()
+
+	reporter := newForgeReporter("")
+	scp := &types.SourceControlProviderStruct{SCPName: "someGitea", Url: ts.URL, ReportingToken: "tok"}
+	err := reporter.ReportStatus(context.Background(), scp, types.CommitStatusStruct{
+		RepoOwner: "owner", RepoName: "repo", SHA: "abc123",
+		State: "success", Description: "+5 points", Context: "bbash/campaign",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "/api/v1/repos/owner/repo/statuses/abc123", gotPath)
+	assert.Equal(t, "token tok", gotAuth)
+	assert.Equal(t, forgeStatusBody{State: "success", Description: "+5 points", Context: "bbash/campaign"}, gotBody)
+}
+
+func TestForgeReporterReportStatusError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("boom"))
+	}))
+	defer ts.Close()
+
+	reporter := newForgeReporter("")
+	scp := &types.SourceControlProviderStruct{SCPName: "someGitea", Url: ts.URL}
+	err := reporter.ReportStatus(context.Background(), scp, types.CommitStatusStruct{RepoOwner: "o", RepoName: "r", SHA: "sha"})
+
+	assert.Error(t, err)
+}
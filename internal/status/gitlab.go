@@ -0,0 +1,79 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package status
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+)
+
+// gitlabReporter posts a commit status via GitLab's "POST /projects/:id/statuses/:sha" API, which
+// takes its fields as query parameters rather than a JSON body.
+type gitlabReporter struct {
+	client *http.Client
+}
+
+func newGitLabReporter() *gitlabReporter {
+	return &gitlabReporter{client: newStatusHTTPClient()}
+}
+
+var _ StatusReporter = (*gitlabReporter)(nil)
+
+func (r *gitlabReporter) ReportStatus(ctx context.Context, scp *types.SourceControlProviderStruct, status types.CommitStatusStruct) (err error) {
+	projectID := url.QueryEscape(status.RepoOwner + "/" + status.RepoName)
+	base := strings.TrimSuffix(scp.Url, "/")
+	endpoint := fmt.Sprintf("%s/api/v4/projects/%s/statuses/%s", base, projectID, status.SHA)
+
+	query := url.Values{}
+	query.Set("state", status.State)
+	if status.TargetURL != "" {
+		query.Set("target_url", status.TargetURL)
+	}
+	if status.Description != "" {
+		query.Set("description", status.Description)
+	}
+	if status.Context != "" {
+		query.Set("name", status.Context)
+	}
+
+	var req *http.Request
+	if req, err = http.NewRequestWithContext(ctx, http.MethodPost, endpoint+"?"+query.Encode(), nil); err != nil {
+		return
+	}
+	req.Header.Set("PRIVATE-TOKEN", scp.ReportingToken)
+
+	var res *http.Response
+	if res, err = r.client.Do(req); err != nil {
+		return
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	if res.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(res.Body)
+		err = fmt.Errorf("%s: commit status post failed: %s: %s", scp.SCPName, res.Status, string(respBody))
+	}
+	return
+}
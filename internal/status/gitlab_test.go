@@ -0,0 +1,69 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package status
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+)
+
+func TestGitLabReporterReportStatus(t *testing.T) {
+	var gotPath, gotToken string
+	var gotQuery url.Values
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotToken = r.Header.Get("PRIVATE-TOKEN")
+		gotQuery = r.URL.Query()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	reporter := newGitLabReporter()
+	scp := &types.SourceControlProviderStruct{SCPName: "someGitLab", Url: ts.URL, ReportingToken: "tok"}
+	err := reporter.ReportStatus(context.Background(), scp, types.CommitStatusStruct{
+		RepoOwner: "owner", RepoName: "repo", SHA: "abc123", State: "success", Context: "bbash/campaign",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "/api/v4/projects/owner/repo/statuses/abc123", gotPath)
+	assert.Equal(t, "tok", gotToken)
+	assert.Equal(t, "success", gotQuery.Get("state"))
+	assert.Equal(t, "bbash/campaign", gotQuery.Get("name"))
+}
+
+func TestGitLabReporterReportStatusError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer ts.Close()
+
+	reporter := newGitLabReporter()
+	scp := &types.SourceControlProviderStruct{SCPName: "someGitLab", Url: ts.URL}
+	err := reporter.ReportStatus(context.Background(), scp, types.CommitStatusStruct{RepoOwner: "o", RepoName: "r", SHA: "sha"})
+
+	assert.Error(t, err)
+}
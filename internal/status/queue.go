@@ -0,0 +1,123 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package status
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+)
+
+// queueMaxRetries bounds how many times a failed commit status post is retried before it's
+// dropped (logged, not silently discarded) rather than retried forever.
+const queueMaxRetries = 5
+
+// queueBaseDelay is the first retry's backoff; each subsequent retry doubles it, the same
+// exponential shape as internal/upstream's RetryConfig.
+const queueBaseDelay = 2 * time.Second
+
+// queueMaxDelay caps the backoff so a long outage doesn't push a retry out indefinitely.
+const queueMaxDelay = 2 * time.Minute
+
+// entry is one pending commit status post plus its retry state.
+type entry struct {
+	scp         *types.SourceControlProviderStruct
+	status      types.CommitStatusStruct
+	attempt     int
+	nextAttempt time.Time
+}
+
+// Queue posts commit statuses through a StatusReporter, retrying transient failures with
+// exponential backoff instead of dropping them. A failed post never blocks or fails the scoring
+// request that triggered it: Enqueue tries once synchronously and, on failure, queues the entry
+// for Drain to retry later, the same non-blocking shape db.IDBPoll's dead letter queue uses for
+// ScoringMessages processScoringMessage couldn't apply.
+type Queue struct {
+	reporter StatusReporter
+	logger   *zap.Logger
+
+	mu      sync.Mutex
+	pending []*entry
+}
+
+// NewQueue builds a Queue that posts through reporter, logging via logger.
+func NewQueue(reporter StatusReporter, logger *zap.Logger) (q *Queue) {
+	return &Queue{reporter: reporter, logger: logger}
+}
+
+// Enqueue posts status immediately; on failure it's queued for Drain to retry rather than
+// returning the error to the caller.
+func (q *Queue) Enqueue(ctx context.Context, scp *types.SourceControlProviderStruct, status types.CommitStatusStruct) {
+	if err := q.reporter.ReportStatus(ctx, scp, status); err != nil {
+		q.logger.Warn("commit status post failed, queuing for retry",
+			zap.String("scpName", scp.SCPName), zap.Error(err))
+		q.mu.Lock()
+		q.pending = append(q.pending, &entry{
+			scp:         scp,
+			status:      status,
+			attempt:     1,
+			nextAttempt: time.Now().Add(queueBaseDelay),
+		})
+		q.mu.Unlock()
+	}
+}
+
+// Drain retries every pending entry whose backoff has elapsed, dropping (and logging) any that
+// exhausts queueMaxRetries. Callers are expected to invoke Drain opportunistically, the same way
+// db.IDBPoll's dead letter queue piggybacks its retries on every poll tick.
+func (q *Queue) Drain(ctx context.Context) {
+	q.mu.Lock()
+	var due, retry []*entry
+	now := time.Now()
+	for _, e := range q.pending {
+		if e.nextAttempt.After(now) {
+			retry = append(retry, e)
+			continue
+		}
+		due = append(due, e)
+	}
+	q.pending = nil
+	q.mu.Unlock()
+
+	for _, e := range due {
+		if err := q.reporter.ReportStatus(ctx, e.scp, e.status); err != nil {
+			if e.attempt >= queueMaxRetries {
+				q.logger.Error("commit status post gave up after retries",
+					zap.String("scpName", e.scp.SCPName), zap.Int("attempt", e.attempt), zap.Error(err))
+				continue
+			}
+			e.attempt++
+			delay := queueBaseDelay << (e.attempt - 1)
+			if delay > queueMaxDelay {
+				delay = queueMaxDelay
+			}
+			e.nextAttempt = time.Now().Add(delay)
+			retry = append(retry, e)
+		}
+	}
+
+	q.mu.Lock()
+	q.pending = append(q.pending, retry...)
+	q.mu.Unlock()
+}
@@ -0,0 +1,100 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package status
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+)
+
+// fakeReporter fails its first failAttempts calls, then succeeds; calls counts every attempt.
+type fakeReporter struct {
+	failAttempts int
+	calls        int
+}
+
+func (f *fakeReporter) ReportStatus(context.Context, *types.SourceControlProviderStruct, types.CommitStatusStruct) error {
+	f.calls++
+	if f.calls <= f.failAttempts {
+		return fmt.Errorf("forced failure %d", f.calls)
+	}
+	return nil
+}
+
+func TestQueueEnqueueSucceedsImmediately(t *testing.T) {
+	reporter := &fakeReporter{}
+	q := NewQueue(reporter, zap.NewNop())
+
+	q.Enqueue(context.Background(), &types.SourceControlProviderStruct{}, types.CommitStatusStruct{})
+
+	assert.Equal(t, 1, reporter.calls)
+	assert.Empty(t, q.pending)
+}
+
+func TestQueueEnqueueFailureIsQueued(t *testing.T) {
+	reporter := &fakeReporter{failAttempts: 1}
+	q := NewQueue(reporter, zap.NewNop())
+
+	q.Enqueue(context.Background(), &types.SourceControlProviderStruct{SCPName: "someSCP"}, types.CommitStatusStruct{})
+
+	assert.Equal(t, 1, reporter.calls)
+	assert.Len(t, q.pending, 1)
+}
+
+func TestQueueDrainRetriesUntilDue(t *testing.T) {
+	reporter := &fakeReporter{failAttempts: 1}
+	q := NewQueue(reporter, zap.NewNop())
+	q.Enqueue(context.Background(), &types.SourceControlProviderStruct{}, types.CommitStatusStruct{})
+	assert.Len(t, q.pending, 1)
+
+	// backoff hasn't elapsed yet: Drain shouldn't retry
+	q.Drain(context.Background())
+	assert.Equal(t, 1, reporter.calls)
+	assert.Len(t, q.pending, 1)
+
+	// force the pending entry due now
+	q.pending[0].nextAttempt = time.Now().Add(-time.Second)
+	q.Drain(context.Background())
+	assert.Equal(t, 2, reporter.calls)
+	assert.Empty(t, q.pending)
+}
+
+func TestQueueDrainGivesUpAfterMaxRetries(t *testing.T) {
+	reporter := &fakeReporter{failAttempts: queueMaxRetries + 1}
+	q := NewQueue(reporter, zap.NewNop())
+	q.Enqueue(context.Background(), &types.SourceControlProviderStruct{SCPName: "someSCP"}, types.CommitStatusStruct{})
+
+	for i := 0; i < queueMaxRetries; i++ {
+		if len(q.pending) == 0 {
+			break
+		}
+		q.pending[0].nextAttempt = time.Now().Add(-time.Second)
+		q.Drain(context.Background())
+	}
+
+	assert.Empty(t, q.pending)
+}
@@ -0,0 +1,61 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+// Package status posts a types.CommitStatusStruct back to the source control provider a scored
+// event came from, so a contributor sees their points directly on the commit/PR bbash just scored,
+// the way poll.WebhookAdapter normalizes that provider's native webhook into a ScoringMessage.
+package status
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+)
+
+// StatusReporter posts status for a scored event to scp, the types.SourceControlProviderStruct the
+// event came from. Unlike poll.WebhookAdapter, a reporter owns its own outbound HTTP call; callers
+// aren't expected to retry it themselves (see Queue for that).
+type StatusReporter interface {
+	ReportStatus(ctx context.Context, scp *types.SourceControlProviderStruct, status types.CommitStatusStruct) error
+}
+
+// reporters maps a registered types.SourceControlProviderStruct's Kind to the reporter that knows
+// that provider's commit-status API, mirroring poll.webhookAdapters.
+var reporters = map[string]StatusReporter{
+	"github":  newForgeReporter(githubAPI),
+	"gitea":   newForgeReporter(""),
+	"forgejo": newForgeReporter(""),
+	"gitlab":  newGitLabReporter(),
+}
+
+// ReporterForKind returns the StatusReporter registered for kind, and whether one exists.
+func ReporterForKind(kind string) (reporter StatusReporter, ok bool) {
+	reporter, ok = reporters[kind]
+	return
+}
+
+// statusHTTPTimeout bounds a single commit-status POST; Queue's retry loop is what absorbs a slow
+// or momentarily-down forge, not a long-lived request.
+const statusHTTPTimeout = 10 * time.Second
+
+func newStatusHTTPClient() *http.Client {
+	return &http.Client{Timeout: statusHTTPTimeout}
+}
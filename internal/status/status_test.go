@@ -0,0 +1,37 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package status
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReporterForKind(t *testing.T) {
+	for _, kind := range []string{"github", "gitea", "forgejo", "gitlab"} {
+		reporter, ok := ReporterForKind(kind)
+		assert.True(t, ok, kind)
+		assert.NotNil(t, reporter, kind)
+	}
+
+	_, ok := ReporterForKind("bitbucket")
+	assert.False(t, ok)
+}
@@ -0,0 +1,162 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// otlpResourceMetrics, otlpScopeMetrics, otlpMetric, otlpNumberDataPoint and otlpAttribute are a
+// minimal, hand-written subset of the OTLP metrics JSON wire format (the collector's HTTP receiver
+// accepts application/json as an alternative to protobuf), just enough to report one monotonic sum
+// per event - not the full go.opentelemetry.io/otel SDK, which at this module's go.mod go
+// directive was still pre-1.0 for metrics and would have pulled in a gRPC/protobuf dependency chain
+// disproportionate to reporting a single counter. See internal/upstream.HTTPSinkBackend for the
+// same tradeoff made for leaderboard sync.
+type otlpExportRequest struct {
+	ResourceMetrics []otlpResourceMetrics `json:"resourceMetrics"`
+}
+
+type otlpResourceMetrics struct {
+	ScopeMetrics []otlpScopeMetrics `json:"scopeMetrics"`
+}
+
+type otlpScopeMetrics struct {
+	Metrics []otlpMetric `json:"metrics"`
+}
+
+type otlpMetric struct {
+	Name string  `json:"name"`
+	Sum  otlpSum `json:"sum"`
+}
+
+type otlpSum struct {
+	DataPoints             []otlpNumberDataPoint `json:"dataPoints"`
+	AggregationTemporality int                   `json:"aggregationTemporality"`
+	IsMonotonic            bool                  `json:"isMonotonic"`
+}
+
+// aggregationTemporalityDelta matches OTLP's AGGREGATION_TEMPORALITY_DELTA enum value: each
+// exported data point is this event alone, not a running total since process start.
+const aggregationTemporalityDelta = 1
+
+type otlpNumberDataPoint struct {
+	Attributes   []otlpAttribute `json:"attributes"`
+	TimeUnixNano string          `json:"timeUnixNano"`
+	AsInt        string          `json:"asInt"`
+}
+
+type otlpAttribute struct {
+	Key   string             `json:"key"`
+	Value otlpAttributeValue `json:"value"`
+}
+
+type otlpAttributeValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+// OTLPSink POSTs a single-data-point metrics export for every event to an OTLP/HTTP collector
+// endpoint (see envOTLPEndpoint), so deployments that already centralize telemetry in an OTel
+// collector don't need a second Prometheus scrape target just for bbash feature usage.
+type OTLPSink struct {
+	Endpoint string
+	client   *http.Client
+	logger   *zap.Logger
+}
+
+var _ Sink = (*OTLPSink)(nil)
+
+// NewOTLPSink builds an OTLPSink posting to endpoint (the collector's "/v1/metrics" URL).
+func NewOTLPSink(endpoint string, logger *zap.Logger) *OTLPSink {
+	return &OTLPSink{
+		Endpoint: endpoint,
+		client:   &http.Client{Timeout: 5 * time.Second},
+		logger:   logger,
+	}
+}
+
+func (o *OTLPSink) Record(ctx context.Context, feature, call string, attrs map[string]string) {
+	attributes := []otlpAttribute{
+		{Key: "feature", Value: otlpAttributeValue{StringValue: feature}},
+		{Key: "call", Value: otlpAttributeValue{StringValue: call}},
+	}
+	for k, v := range attrs {
+		attributes = append(attributes, otlpAttribute{Key: k, Value: otlpAttributeValue{StringValue: v}})
+	}
+
+	req := otlpExportRequest{
+		ResourceMetrics: []otlpResourceMetrics{{
+			ScopeMetrics: []otlpScopeMetrics{{
+				Metrics: []otlpMetric{{
+					Name: "bbash.telemetry.events",
+					Sum: otlpSum{
+						AggregationTemporality: aggregationTemporalityDelta,
+						IsMonotonic:            true,
+						DataPoints: []otlpNumberDataPoint{{
+							Attributes:   attributes,
+							TimeUnixNano: fmt.Sprintf("%d", time.Now().UnixNano()),
+							AsInt:        "1",
+						}},
+					},
+				}},
+			}},
+		}},
+	}
+
+	// Fire-and-forget, like the rest of telemetry recording: a slow or unreachable collector
+	// must never add latency to the request the event was recorded for. Detached onto
+	// context.Background() rather than ctx, since ctx belongs to the request that triggered this
+	// event and is typically canceled the moment that request's handler returns - well before
+	// this goroutine gets a chance to run.
+	go o.post(context.Background(), req)
+}
+
+func (o *OTLPSink) post(ctx context.Context, req otlpExportRequest) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		o.logger.Debug("otlp sink marshal error", zap.Error(err))
+		return
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, o.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		o.logger.Debug("otlp sink request error", zap.Error(err))
+		return
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	res, err := o.client.Do(httpReq)
+	if err != nil {
+		o.logger.Debug("otlp sink post error", zap.Error(err))
+		return
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		o.logger.Debug("otlp sink rejected export", zap.String("status", res.Status))
+	}
+}
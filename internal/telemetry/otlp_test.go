@@ -0,0 +1,81 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestOTLPSinkPostsExpectedShape(t *testing.T) {
+	received := make(chan otlpExportRequest, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		body, _ := ioutil.ReadAll(r.Body)
+		var req otlpExportRequest
+		assert.NoError(t, json.Unmarshal(body, &req))
+		received <- req
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	sink := NewOTLPSink(ts.URL, zaptest.NewLogger(t))
+	sink.Record(context.Background(), "leaderboard", "getActiveCampaigns", map[string]string{"campaignName": "fall-bash"})
+
+	select {
+	case req := <-received:
+		dataPoint := req.ResourceMetrics[0].ScopeMetrics[0].Metrics[0].Sum.DataPoints[0]
+		assert.Equal(t, "1", dataPoint.AsInt)
+
+		attrsByKey := map[string]string{}
+		for _, a := range dataPoint.Attributes {
+			attrsByKey[a.Key] = a.Value.StringValue
+		}
+		assert.Equal(t, "leaderboard", attrsByKey["feature"])
+		assert.Equal(t, "getActiveCampaigns", attrsByKey["call"])
+		assert.Equal(t, "fall-bash", attrsByKey["campaignName"])
+	case <-time.After(2 * time.Second):
+		t.Fatal("otlp sink never posted its export request")
+	}
+}
+
+func TestOTLPSinkDoesNotBlockOnUnreachableEndpoint(t *testing.T) {
+	sink := NewOTLPSink("http://127.0.0.1:1/unreachable", zaptest.NewLogger(t))
+
+	done := make(chan struct{})
+	go func() {
+		sink.Record(context.Background(), "feature", "call", nil)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Record should return immediately regardless of endpoint reachability")
+	}
+}
@@ -0,0 +1,100 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+// Package telemetry replaces server.go's old logTelemetry (a bare zap info line) with a pluggable
+// Sink: ZapSink preserves that original behavior, PrometheusSink makes feature usage queryable the
+// same way internal/metrics already exposes HTTP/scoring counters, and OTLPSink forwards events to
+// an OpenTelemetry collector for deployments that already centralize telemetry there.
+package telemetry
+
+import (
+	"context"
+	"os"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+// Sink records a single feature-usage event, e.g. "someone called GET /participant/list for
+// campaign X". attrs carries event-specific dimensions (e.g. "campaignName") beyond feature/call -
+// implementations that can't support arbitrary labels (PrometheusSink) may drop ones they don't
+// recognize.
+type Sink interface {
+	Record(ctx context.Context, feature, call string, attrs map[string]string)
+}
+
+// ZapSink reproduces server.go's original logTelemetry behavior: a single "log-telemetry" info
+// line per event, with feature/call (and now attrs) as structured fields.
+type ZapSink struct {
+	Logger *zap.Logger
+}
+
+var _ Sink = ZapSink{}
+
+func (z ZapSink) Record(_ context.Context, feature, call string, attrs map[string]string) {
+	fields := make([]zap.Field, 0, 2+len(attrs))
+	fields = append(fields, zap.String("feature", feature), zap.String("call", call))
+	for k, v := range attrs {
+		fields = append(fields, zap.String(k, v))
+	}
+	z.Logger.Info("log-telemetry", fields...)
+}
+
+// EventsTotal counts every telemetry event PrometheusSink records, labeled by feature and call, so
+// an operator can graph feature usage the same way they'd graph internal/metrics.RequestsTotal.
+var EventsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "bbash_telemetry_events_total",
+	Help: "Total feature-usage telemetry events recorded, labeled by feature and call.",
+}, []string{"feature", "call"})
+
+// PrometheusSink increments EventsTotal for every event; attrs are ignored; a per-campaign,
+// per-participant label cardinality isn't something a Prometheus counter should carry.
+type PrometheusSink struct{}
+
+var _ Sink = PrometheusSink{}
+
+func (PrometheusSink) Record(_ context.Context, feature, call string, _ map[string]string) {
+	EventsTotal.WithLabelValues(feature, call).Inc()
+}
+
+// MultiSink fans a single event out to every Sink it holds.
+type MultiSink []Sink
+
+var _ Sink = MultiSink(nil)
+
+func (m MultiSink) Record(ctx context.Context, feature, call string, attrs map[string]string) {
+	for _, sink := range m {
+		sink.Record(ctx, feature, call, attrs)
+	}
+}
+
+// envOTLPEndpoint, when set, turns on OTLPSink (see New): the full URL of an OTLP/HTTP collector's
+// metrics endpoint, e.g. "http://otel-collector:4318/v1/metrics".
+const envOTLPEndpoint = "OTLP_ENDPOINT"
+
+// New builds the Sink server.go's logTelemetry should record every event to: always ZapSink and
+// PrometheusSink, plus OTLPSink when envOTLPEndpoint is configured.
+func New(logger *zap.Logger) Sink {
+	sinks := MultiSink{ZapSink{Logger: logger}, PrometheusSink{}}
+	if endpoint := os.Getenv(envOTLPEndpoint); endpoint != "" {
+		sinks = append(sinks, NewOTLPSink(endpoint, logger))
+	}
+	return sinks
+}
@@ -0,0 +1,79 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package telemetry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+)
+
+type recordingSink struct {
+	feature, call string
+	attrs         map[string]string
+}
+
+func (r *recordingSink) Record(_ context.Context, feature, call string, attrs map[string]string) {
+	r.feature, r.call, r.attrs = feature, call, attrs
+}
+
+func TestMultiSinkFansOutToEverySink(t *testing.T) {
+	a, b := &recordingSink{}, &recordingSink{}
+	sinks := MultiSink{a, b}
+
+	sinks.Record(context.Background(), "leaderboard", "getActiveCampaigns", map[string]string{"campaignName": "fall-bash"})
+
+	assert.Equal(t, "leaderboard", a.feature)
+	assert.Equal(t, "getActiveCampaigns", a.call)
+	assert.Equal(t, map[string]string{"campaignName": "fall-bash"}, a.attrs)
+	assert.Equal(t, "leaderboard", b.feature)
+	assert.Equal(t, "getActiveCampaigns", b.call)
+}
+
+func TestZapSinkDoesNotPanicWithoutAttrs(t *testing.T) {
+	sink := ZapSink{Logger: zaptest.NewLogger(t)}
+	sink.Record(context.Background(), "feature", "call", nil)
+}
+
+func TestPrometheusSinkIncrementsEventsTotal(t *testing.T) {
+	before := testutil.ToFloat64(EventsTotal.WithLabelValues("feature-x", "call-y"))
+
+	PrometheusSink{}.Record(context.Background(), "feature-x", "call-y", nil)
+
+	after := testutil.ToFloat64(EventsTotal.WithLabelValues("feature-x", "call-y"))
+	assert.Equal(t, before+1, after)
+}
+
+func TestNewIncludesOTLPSinkOnlyWhenEndpointConfigured(t *testing.T) {
+	t.Setenv(envOTLPEndpoint, "")
+	sink := New(zaptest.NewLogger(t))
+	multi, ok := sink.(MultiSink)
+	assert.True(t, ok)
+	assert.Len(t, multi, 2)
+
+	t.Setenv(envOTLPEndpoint, "http://localhost:4318/v1/metrics")
+	sink = New(zaptest.NewLogger(t))
+	multi, ok = sink.(MultiSink)
+	assert.True(t, ok)
+	assert.Len(t, multi, 3)
+}
@@ -0,0 +1,50 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+// Package testutil holds small test helpers shared across this module's test suites.
+package testutil
+
+import (
+	"testing"
+	"time"
+)
+
+// waitForPollInterval is how often WaitFor re-evaluates check while waiting for it to turn true.
+const waitForPollInterval = 5 * time.Millisecond
+
+// WaitFor polls check every waitForPollInterval until it returns true or timeout elapses, whichever
+// happens first, failing t with msg if check never returns true. It replaces a fixed time.Sleep
+// before asserting on state a background goroutine sets asynchronously (e.g. ChaseTail's poll
+// ticker processing a log): tests finish as soon as the condition is met instead of always paying
+// the worst-case sleep duration, and they fail loudly rather than racily passing if the production
+// code path never runs at all.
+func WaitFor(t *testing.T, timeout time.Duration, check func() bool, msg string) {
+	t.Helper()
+
+	deadline := time.Now().Add(timeout)
+	for {
+		if check() {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal(msg)
+		}
+		time.Sleep(waitForPollInterval)
+	}
+}
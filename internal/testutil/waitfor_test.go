@@ -0,0 +1,58 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package testutil
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWaitForReturnsAsSoonAsCheckIsTrue(t *testing.T) {
+	var ready int32
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		atomic.StoreInt32(&ready, 1)
+	}()
+
+	start := time.Now()
+	WaitFor(t, time.Second, func() bool { return atomic.LoadInt32(&ready) == 1 }, "ready was never set")
+	elapsed := time.Since(start)
+	if elapsed >= time.Second {
+		t.Fatalf("WaitFor did not return early once check turned true, took %s", elapsed)
+	}
+}
+
+func TestWaitForFailsTestWhenCheckNeverTrue(t *testing.T) {
+	// WaitFor calls t.Fatal on timeout, which calls runtime.Goexit on the calling goroutine -
+	// run it on its own goroutine against a throwaway *testing.T so that Goexit doesn't tear
+	// down this test, and poll the throwaway T's Failed() instead of waiting on the goroutine
+	// to return normally (it won't, past Goexit).
+	fakeT := &testing.T{}
+	go WaitFor(fakeT, 20*time.Millisecond, func() bool { return false }, "never true")
+
+	deadline := time.Now().Add(time.Second)
+	for !fakeT.Failed() {
+		if time.Now().After(deadline) {
+			t.Fatal("WaitFor never failed the test after its timeout elapsed")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -28,6 +28,54 @@ type SourceControlProviderStruct struct {
 	ID      string `json:"guid"`
 	SCPName string `json:"scpName"`
 	Url     string `json:"url"`
+	// Kind selects which scp.SCPProvider or poll.WebhookAdapter /scp/{scpName}/webhook dispatches
+	// to, e.g. "github", "gitlab", "bitbucket", "gitea", "forgejo". Empty Kind means the provider
+	// has no native webhook receiver.
+	Kind string `json:"kind"`
+	// Secret is this provider's webhook secret: an HMAC signing key for kinds that sign their
+	// payload (GitHub, Bitbucket, Gitea, Forgejo), or a bare shared token for kinds that don't
+	// (GitLab).
+	Secret string `json:"secret"`
+	// ReportingToken authenticates outbound commit-status API calls (status.StatusReporter), e.g.
+	// a GitHub personal access token or Gitea/GitLab API token. It is a distinct credential from
+	// Secret: Secret authenticates inbound webhooks from the provider, ReportingToken authenticates
+	// bbash's own outbound calls back to it.
+	ReportingToken string `json:"reportingToken"`
+}
+
+// CommitStatusStruct is a provider-agnostic commit status, normalized to the shape GitHub,
+// Gitea, and Forgejo's "POST .../statuses/{sha}" APIs (and, via status.StatusReporter, GitLab's
+// equivalent) all accept.
+type CommitStatusStruct struct {
+	RepoOwner string `json:"repoOwner"`
+	RepoName  string `json:"repoName"`
+	SHA       string `json:"sha"`
+	// State is one of "pending", "success", "failure", "error".
+	State       string `json:"state"`
+	TargetURL   string `json:"targetUrl,omitempty"`
+	Description string `json:"description,omitempty"`
+	Context     string `json:"context"`
+}
+
+// ScanProviderStruct registers a SonarQube (or compatible) host that SonarQubeSource polls for
+// resolved issues, alongside the SourceControlProviderStruct hosts scoring messages arrive from.
+type ScanProviderStruct struct {
+	ID     string `json:"guid"`
+	SPName string `json:"spName"`
+	Url    string `json:"url"`
+	ApiKey string `json:"apiKey"`
+}
+
+// SonarQubeFinding is a single resolved issue read from a SonarQube host's /api/issues/search.
+type SonarQubeFinding struct {
+	ProjectKey string    `json:"projectKey"`
+	IssueKey   string    `json:"issueKey"`
+	Rule       string    `json:"rule"`
+	Severity   string    `json:"severity"`
+	Type       string    `json:"type"`
+	Status     string    `json:"status"`
+	Assignee   string    `json:"assignee"`
+	ResolvedAt time.Time `json:"resolvedAt"`
 }
 
 type CampaignStruct struct {
@@ -38,6 +86,22 @@ type CampaignStruct struct {
 	StartOn      time.Time      `json:"startOn"`
 	EndOn        time.Time      `json:"endOn"`
 	Note         sql.NullString `json:"note"`
+	UpstreamId   string         `json:"upstreamId"`
+	// ReportStatus enables posting a commit status back to the source control provider (via
+	// status.StatusReporter) whenever a participant is scored during this campaign.
+	ReportStatus bool `json:"reportStatus"`
+	// State is the campaign's lifecycle stage (see internal/campaign.State): "draft",
+	// "scheduled", "active", "paused", or "ended". Kept as a plain string here, the same way
+	// Note is a plain sql.NullString, so this package doesn't have to import internal/campaign
+	// just to describe a row.
+	State string `json:"state"`
+	// EnabledSCPs allowlists the scp.SCPProvider EventSources this campaign accepts scoring
+	// events from, e.g. []string{"github", "gitlab"}. Empty means every registered SCPProvider is
+	// allowed, so campaigns created before this field existed keep scoring from every source.
+	// Like UpstreamId above, campaign rows have no enabled_scps column in this snapshot's
+	// migrations, so this is never populated from the database - it's always the zero value
+	// (allow all) until that lands.
+	EnabledSCPs []string `json:"enabledScps"`
 }
 
 type OrganizationStruct struct {
@@ -54,6 +118,9 @@ type ScoringMessage struct {
 	TotalFixed  int            `json:"fixed-bugs"`
 	BugCounts   map[string]int `json:"fixed-bug-types"`
 	PullRequest int            `json:"pullRequestId"`
+	// MergeSHA is the merge commit's SHA, when the event that produced this message was a merge
+	// reported with one (e.g. a poll.WebhookAdapter); empty for sources that don't carry one.
+	MergeSHA string `json:"mergeCommitSha,omitempty"`
 }
 
 type ParticipantStruct struct {
@@ -66,6 +133,7 @@ type ParticipantStruct struct {
 	Score        int       `json:"score"`
 	TeamName     string    `json:"teamName"`
 	JoinedAt     time.Time `json:"joinedAt"`
+	UpstreamId   string    `json:"upstreamId"`
 }
 
 type TeamStruct struct {
@@ -81,9 +149,279 @@ type BugStruct struct {
 	PointValue int    `json:"pointValue"`
 }
 
+// LeaderboardEntry is one cached row of the leaderboard table db.BBashDB.RefreshLeaderboard
+// populates: ParticipantId's Score and Rank within CampaignName as of UpdatedAt, so
+// db.BBashDB.GetLeaderboard can serve a campaign's standings straight from an indexed table
+// instead of sorting every participant client-side on every request.
+type LeaderboardEntry struct {
+	CampaignName  string    `json:"campaignName"`
+	ParticipantId string    `json:"participantId"`
+	LoginName     string    `json:"loginName"`
+	TeamName      string    `json:"teamName"`
+	Score         int       `json:"score"`
+	Rank          int       `json:"rank"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+// ScoringPolicyStruct is campaignName's scoring_policy row: the anti-cheat limits
+// db.BBashDB.EvaluateScoringPolicy checks a scoring event against before it's applied. A
+// zero-value field means "not limited" rather than "limit to zero" - a campaign with no row at
+// all is treated the same way, entirely unrestricted. MaxEventsPerHour, MaxPointsPerDay and
+// MinSecondsBetweenEvents describe limits EvaluateScoringPolicy can't yet enforce against this
+// schema's scoring_event table (see EvaluateScoringPolicy's doc comment for why) but are stored
+// here so a future migration adding the timestamp column they need doesn't also need a new table.
+type ScoringPolicyStruct struct {
+	CampaignName            string `json:"campaignName"`
+	MaxEventsPerHour        int    `json:"maxEventsPerHour,omitempty"`
+	MaxPointsPerDay         int    `json:"maxPointsPerDay,omitempty"`
+	MinSecondsBetweenEvents int    `json:"minSecondsBetweenEvents,omitempty"`
+	RequireDistinctRepo     bool   `json:"requireDistinctRepo"`
+}
+
+// StopwatchStruct tracks a participant's "focused work" window on a single issue/PR (IssueRef,
+// formatted the same way scoring matches a fix: "repoOwner/repoName#pullRequestId"), from picking
+// it up (StartedAt) to the fix merging (StoppedAt). ElapsedSeconds is fixed once the stopwatch is
+// stopped and is what scorePoints consults for the stopwatch bonus multiplier.
+type StopwatchStruct struct {
+	ID             string     `json:"guid"`
+	ParticipantID  string     `json:"participantId"`
+	IssueRef       string     `json:"issueRef"`
+	StartedAt      time.Time  `json:"startedAt"`
+	StoppedAt      *time.Time `json:"stoppedAt,omitempty"`
+	ElapsedSeconds float64    `json:"elapsedSeconds"`
+}
+
 type Poll struct {
-	Id                string    `json:"pollInstance"`
-	LastPolled        time.Time `json:"lastPolledOn"`
-	EnvBaseTime       time.Time `json:"envBaseTime"`
+	Id          string    `json:"pollInstance"`
+	LastPolled  time.Time `json:"lastPolledOn"`
+	EnvBaseTime time.Time `json:"envBaseTime"`
+	// LeaderInstance is the leader.Elector instance id that most recently won the poll loop's
+	// lease and ran this poll tick. Id stays the singleton row key ("1", see db.PollId); a
+	// replica's identity is tracked separately so rotating leaders doesn't change the row bbash
+	// looks the poll state up by.
+	LeaderInstance    string    `json:"leaderInstance"`
 	LastPollCompleted time.Time `json:"lastPollCompleted"`
+	// Schedule is either a 5-field cron expression or an ISO-8601 repeating interval
+	// (e.g. "R/PT15M") controlling how often the poll loop ticks; empty keeps the existing
+	// fixed-interval behavior. See db.ComputeNextRun.
+	Schedule string `json:"schedule,omitempty"`
+	// NextRunAt is populated by IDBPoll.SelectPoll from Schedule, so the poller can sleep until
+	// precisely then instead of on a fixed cadence. Zero when Schedule is empty.
+	NextRunAt time.Time `json:"nextRunAt,omitempty"`
+}
+
+// PollRun records the outcome of a single poll tick, so the UI can show a timeline of scraper
+// health instead of only the latest Poll.LastPolled/LastPollCompleted - see
+// PollStruct.RecordPollRun/SelectRecentPolls.
+type PollRun struct {
+	Id          string        `json:"guid"`
+	Source      string        `json:"source"`
+	StartedOn   time.Time     `json:"startedOn"`
+	Duration    time.Duration `json:"duration"`
+	RowsScraped int           `json:"rowsScraped"`
+	Error       string        `json:"error,omitempty"`
+}
+
+// DeadLetterEntry records a ScoringMessage that processScoringMessage failed to apply, so it can
+// be inspected, retried, or discarded without blocking the rest of a poll tick.
+type DeadLetterEntry struct {
+	Id          string         `json:"guid"`
+	Message     ScoringMessage `json:"message"`
+	EnvBaseTime time.Time      `json:"envBaseTime"`
+	Error       string         `json:"error"`
+	RetryCount  int            `json:"retryCount"`
+	CreatedOn   time.Time      `json:"createdOn"`
+	LastAttempt time.Time      `json:"lastAttempted"`
+}
+
+// AuthTokenStruct is a static API token issued to a single admin user, stored hashed (never in the
+// clear - see internal/auth.HashToken) so a leaked database backup doesn't leak usable tokens. Role
+// is the highest internal/auth.Role this token authenticates as.
+type AuthTokenStruct struct {
+	Id        string     `json:"guid"`
+	Name      string     `json:"name"`
+	TokenHash string     `json:"-"`
+	Role      string     `json:"role"`
+	CreatedOn time.Time  `json:"createdOn"`
+	RevokedOn *time.Time `json:"revokedOn,omitempty"`
+}
+
+// AdminStruct registers a caller as an admin identity independent of any one credential: Subject
+// is the identifier an internal/auth.Authenticator resolves a request to (a BasicAuthenticator
+// username, an OIDC "sub" claim, or an AuthTokenStruct.Name), and Role is the internal/auth.Role
+// that identity is provisioned with. It lets the "who is an admin" roster be managed (and audited)
+// separately from minting the credentials that prove a given Subject's identity.
+type AdminStruct struct {
+	Id        string    `json:"guid"`
+	Subject   string    `json:"subject"`
+	Role      string    `json:"role"`
+	CreatedOn time.Time `json:"createdOn"`
+}
+
+// AuditEntryStruct records a single call against an /admin route, so who changed what and when is
+// always attributable, regardless of which internal/auth.Authenticator made the call.
+type AuditEntryStruct struct {
+	Id         string    `json:"guid"`
+	Subject    string    `json:"subject"`
+	Role       string    `json:"role"`
+	Method     string    `json:"method"`
+	Path       string    `json:"path"`
+	StatusCode int       `json:"statusCode"`
+	OccurredOn time.Time `json:"occurredOn"`
+}
+
+// IdempotencyRecordStruct caches the response a bulk-import handler (e.g. putBugs) or addCampaign
+// sent for a given Idempotency-Key/route pair, so a retried call can replay it instead of re-running
+// the import and risking duplicate inserts.
+type IdempotencyRecordStruct struct {
+	Id    string `json:"guid"`
+	Key   string `json:"key"`
+	Route string `json:"route"`
+	// BodyHash is a sha256 hex digest of the request body the key was first used with, so a retry
+	// that reuses Key with a different body (a caller bug, not a safe retry) can be rejected instead
+	// of silently replaying the wrong response.
+	BodyHash   string `json:"-"`
+	StatusCode int    `json:"statusCode"`
+	// ContentType is the original response's Content-Type (e.g. addCampaign's plain guid vs. a
+	// bulk-import handler's JSON {accepted, rejected}), preserved so a replay looks identical to
+	// the original response, not just byte-identical.
+	ContentType  string    `json:"-"`
+	ResponseBody string    `json:"-"`
+	CreatedOn    time.Time `json:"createdOn"`
+}
+
+// ScoringEventStatus is a ScoringEventStruct's place in the pending -> validated -> scored state
+// machine, or the terminal states it can fall into instead of reaching scored.
+type ScoringEventStatus string
+
+const (
+	ScoringEventPending    ScoringEventStatus = "pending"
+	ScoringEventValidated  ScoringEventStatus = "validated"
+	ScoringEventScored     ScoringEventStatus = "scored"
+	ScoringEventInvalid    ScoringEventStatus = "invalid"
+	ScoringEventSuperseded ScoringEventStatus = "superseded"
+	ScoringEventExpired    ScoringEventStatus = "expired"
+)
+
+// ScoringEventStruct records one attempt to score a ScoringMessage, keyed by DedupId (a sha256 hex
+// digest of the message's EventSource/RepoOwner/RepoName/PullRequest/MergeSHA and its BugCounts, in
+// a canonical order - see server.go's scoringEventDedupID) so a redelivered webhook or a replayed
+// poll log line resolves to the same row instead of being scored twice. Status only ever moves
+// forward through the state machine described on ScoringEventStatus; GET /admin/scoring-events/:id
+// exposes it for troubleshooting a stuck or rejected delivery.
+type ScoringEventStruct struct {
+	Id        string             `json:"guid"`
+	DedupId   string             `json:"dedupId"`
+	Status    ScoringEventStatus `json:"status"`
+	Reason    string             `json:"reason,omitempty"`
+	Points    float64            `json:"points"`
+	CreatedOn time.Time          `json:"createdOn"`
+	UpdatedOn time.Time          `json:"updatedOn"`
+}
+
+// ScoringLedgerEntryStruct is a row of the scoring_event table - the points ledger ApplyScoringEvent
+// upserts into, distinct from ScoringEventStruct's scoring_message_event (the webhook-delivery
+// dedup/status tracker). See db.SelectScoringEventsForParticipant.
+type ScoringLedgerEntryStruct struct {
+	RepoOwner   string  `json:"repositoryOwner"`
+	RepoName    string  `json:"repositoryName"`
+	PullRequest int     `json:"pullRequestId"`
+	TriggerUser string  `json:"triggerUser"`
+	Points      float64 `json:"points"`
+}
+
+// OutboxEntryStruct is a row in the upstream_outbox table: the same DB transaction that updates a
+// participant's score (or creates/deletes one) inserts one of these describing the upstream
+// mutation that should follow, so a background worker (internal/outbox.Worker) can publish it
+// after the transaction commits and retry across restarts instead of coupling API/poll latency and
+// error handling to upstream availability. Op identifies which upstream call Payload should be
+// unmarshalled for (e.g. internal/outbox.OpUpdateScore); ParticipantId is the row this entry is
+// about, for troubleshooting and so a participant can be filtered out of the queue if it's deleted.
+// DeadLettered is set once internal/outbox.Worker has exhausted its retries, so GET
+// /admin/outbox/dead-letter can surface the entry for manual inspection/replay instead of it
+// retrying forever.
+type OutboxEntryStruct struct {
+	Id            string    `json:"guid"`
+	Op            string    `json:"op"`
+	ParticipantId string    `json:"participantId"`
+	Payload       string    `json:"payload"`
+	Attempts      int       `json:"attempts"`
+	NextAttemptAt time.Time `json:"nextAttemptAt"`
+	Done          bool      `json:"done"`
+	DeadLettered  bool      `json:"deadLettered"`
+	CreatedOn     time.Time `json:"createdOn"`
+}
+
+// APIKeyStruct is a caller's public key, registered out-of-band (see the register-key CLI
+// subcommand) so internal/auth.SignatureAuthenticator can verify an HTTP-signed request without
+// ever holding the caller's private key. KeyId is the opaque identifier callers put in their
+// Signature header's keyId parameter - PublicKey is PEM-encoded, in whichever form Algorithm
+// expects (PKIX for rsa-sha256, raw ed25519 seed-less public key for ed25519).
+type APIKeyStruct struct {
+	Id        string     `json:"guid"`
+	KeyId     string     `json:"keyId"`
+	Name      string     `json:"name"`
+	Algorithm string     `json:"algorithm"`
+	PublicKey string     `json:"publicKey"`
+	CreatedOn time.Time  `json:"createdOn"`
+	RevokedOn *time.Time `json:"revokedOn,omitempty"`
+}
+
+// SubscriptionStruct registers a third-party URL (a Slack bot, a dashboard, a Discord notifier)
+// to receive the domain events internal/events.Dispatcher fans out. Events is the set of event
+// types (e.g. "campaign.created") this subscription wants; an empty Events means "everything".
+// Secret signs each delivery's X-BBash-Signature header (see internal/events.sign) and is never
+// echoed back by getSubscriptions/GetSubscriptions, the same way AuthTokenStruct.TokenHash isn't.
+type SubscriptionStruct struct {
+	Id        string    `json:"guid"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"-"`
+	Events    []string  `json:"events"`
+	CreatedOn time.Time `json:"createdOn"`
+}
+
+// EventEntryStruct is a row in the event_outbox table: the same transaction that creates or
+// mutates a campaign or participant inserts one of these describing the domain event that
+// followed, so internal/events.Dispatcher can fan it out to every matching SubscriptionStruct
+// after the transaction commits and retry across restarts instead of coupling API latency and
+// error handling to subscriber availability - the same shape as OutboxEntryStruct, one level up
+// from a single upstream.Backend call.
+type EventEntryStruct struct {
+	Id            string    `json:"guid"`
+	Type          string    `json:"type"`
+	Payload       string    `json:"payload"`
+	Attempts      int       `json:"attempts"`
+	NextAttemptAt time.Time `json:"nextAttemptAt"`
+	Done          bool      `json:"done"`
+	CreatedOn     time.Time `json:"createdOn"`
+}
+
+// AuditEventStruct is a row in the audit_events table: internal/auditlog.Logger writes one of
+// these for each administrative mutation (participant create/delete, score update, campaign
+// update) a handler in server.go performs, distinct from AuditEntryStruct/audit_entry - which
+// auth.Audit writes generically for every /admin call - in that it carries the specific entities a
+// mutation acted on, not just the route it hit. Target fields are blank when not applicable to
+// Action (e.g. CampaignName/ParticipantUpstreamId are both empty for a campaign.update).
+type AuditEventStruct struct {
+	Id                    string    `json:"guid"`
+	Actor                 string    `json:"actor"`
+	Action                string    `json:"action"`
+	CampaignName          string    `json:"campaignName,omitempty"`
+	ScpName               string    `json:"scpName,omitempty"`
+	LoginName             string    `json:"loginName,omitempty"`
+	ParticipantUpstreamId string    `json:"participantUpstreamId,omitempty"`
+	RequestIP             string    `json:"requestIp"`
+	Success               bool      `json:"success"`
+	UpstreamStatus        int       `json:"upstreamStatus,omitempty"`
+	Error                 string    `json:"error,omitempty"`
+	OccurredOn            time.Time `json:"occurredOn"`
+}
+
+// AuditEventFilter narrows a ListAuditEvents query; a zero-value field means "don't filter on
+// this" rather than "match empty/zero".
+type AuditEventFilter struct {
+	Actor        string
+	CampaignName string
+	From         time.Time
+	To           time.Time
 }
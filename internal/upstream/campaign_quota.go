@@ -0,0 +1,63 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package upstream
+
+import "sync"
+
+// CampaignRateLimiters gives each campaign its own RateLimiter, lazily created on first use. A
+// Backend's shared RateLimiter caps total throughput to the upstream API; this sits in front of
+// it per campaign so one noisy campaign being throttled (or just making a lot of requests) can't
+// starve the quota the other campaigns need out of that same shared budget.
+type CampaignRateLimiters struct {
+	mu            sync.Mutex
+	limiters      map[string]*RateLimiter
+	ratePerSecond float64
+	burst         int
+}
+
+// NewCampaignRateLimiters builds a CampaignRateLimiters whose per-campaign RateLimiters each allow
+// ratePerSecond sustained requests with bursts up to burst tokens, the same limits a single
+// NewRateLimiter enforces, just scoped per campaign instead of shared globally.
+func NewCampaignRateLimiters(ratePerSecond float64, burst int) *CampaignRateLimiters {
+	return &CampaignRateLimiters{
+		limiters:      make(map[string]*RateLimiter),
+		ratePerSecond: ratePerSecond,
+		burst:         burst,
+	}
+}
+
+// For returns campaignName's RateLimiter, creating it on first use. A nil *CampaignRateLimiters
+// (e.g. a Backend built without one) returns a nil *RateLimiter, which itself is a no-op - the
+// same nil-safety RateLimiter's own methods already provide.
+func (c *CampaignRateLimiters) For(campaignName string) *RateLimiter {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	limiter, ok := c.limiters[campaignName]
+	if !ok {
+		limiter = NewRateLimiter(c.ratePerSecond, c.burst)
+		c.limiters[campaignName] = limiter
+	}
+	return limiter
+}
@@ -0,0 +1,49 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package upstream
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCampaignRateLimitersCreatesOnePerCampaign(t *testing.T) {
+	limiters := NewCampaignRateLimiters(10, 5)
+
+	a1 := limiters.For("campaign-a")
+	a2 := limiters.For("campaign-a")
+	b := limiters.For("campaign-b")
+
+	assert.Same(t, a1, a2)
+	assert.NotSame(t, a1, b)
+}
+
+func TestCampaignRateLimitersAreIndependent(t *testing.T) {
+	limiters := NewCampaignRateLimiters(10, 1)
+
+	a := limiters.For("campaign-a")
+	b := limiters.For("campaign-b")
+
+	a.ReportTooManyRequests()
+
+	assert.Equal(t, int64(1), a.Metrics().TooManyRequests)
+	assert.Equal(t, int64(0), b.Metrics().TooManyRequests)
+}
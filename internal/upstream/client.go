@@ -0,0 +1,199 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package upstream
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"go.uber.org/zap"
+)
+
+// ParticipantDoc is the subset of a participant WebflowBackend needs an UpstreamClient to store as
+// a leaderboard item, decoupled from types.ParticipantStruct so UpstreamClient doesn't depend on
+// the rest of bbash's domain model.
+type ParticipantDoc struct {
+	LoginName          string
+	Score              int
+	CampaignUpstreamId string
+}
+
+// UpstreamClient is the narrow surface WebflowBackend needs against the
+// /collections/{collection}/items[/{id}] participant endpoints. Pulling it out of WebflowBackend
+// means every code path that creates, scores, or deletes a participant (addParticipant, the
+// scoring poll loop, the outbox worker) can be exercised against fakeUpstreamClient instead of an
+// httptest.Server, and client_contract_test.go runs the same scenarios against both
+// implementations so a single mis-routed path doesn't require editing dozens of handler tests.
+type UpstreamClient interface {
+	// UpsertParticipant creates a new leaderboard item for doc and returns its upstream id.
+	UpsertParticipant(ctx context.Context, doc ParticipantDoc) (upstreamId string, err error)
+
+	// PatchScore updates the score of the leaderboard item known upstream as upstreamId.
+	PatchScore(ctx context.Context, upstreamId string, score int) (err error)
+
+	// DeleteParticipant removes the leaderboard item known upstream as upstreamId.
+	DeleteParticipant(ctx context.Context, upstreamId string) (err error)
+}
+
+// webflowItemsClient is the real UpstreamClient, issuing requests against one Webflow CMS
+// collection (the participant collection) through a shared retryingClient.
+type webflowItemsClient struct {
+	baseAPI    string
+	token      string
+	collection string
+	http       *retryingClient
+	logger     *zap.Logger
+}
+
+var _ UpstreamClient = (*webflowItemsClient)(nil)
+
+// newWebflowItemsClient builds a webflowItemsClient that talks to collection via http.
+func newWebflowItemsClient(baseAPI, token, collection string, http *retryingClient, logger *zap.Logger) *webflowItemsClient {
+	return &webflowItemsClient{
+		baseAPI:    baseAPI,
+		token:      token,
+		collection: collection,
+		http:       http,
+		logger:     logger,
+	}
+}
+
+func (c *webflowItemsClient) requestHeaderSetup(req *http.Request) {
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", c.token))
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("accept-version", "1.0.0")
+}
+
+func (c *webflowItemsClient) doRequest(ctx context.Context, req *http.Request, errMsgPattern string) (res *http.Response, err error) {
+	req = req.WithContext(ctx)
+	c.requestHeaderSetup(req)
+
+	res, err = c.http.Do(req)
+	if err != nil {
+		return
+	}
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		defer res.Body.Close()
+		c.logger.Debug("unexpected webflow response", zap.Any("response", res))
+		var responseBody []byte
+		_, _ = res.Body.Read(responseBody)
+		c.logger.Debug("webflow response body", zap.ByteString("body", responseBody))
+		err = &CreateError{MsgPattern: errMsgPattern, Status: res.Status}
+		return
+	}
+	return
+}
+
+func (c *webflowItemsClient) UpsertParticipant(ctx context.Context, doc ParticipantDoc) (upstreamId string, err error) {
+	item := leaderboardItem{
+		UserName:           doc.LoginName,
+		Slug:               doc.LoginName,
+		Score:              doc.Score,
+		CampaignUpstreamId: doc.CampaignUpstreamId,
+	}
+
+	body, err := json.Marshal(leaderboardPayload{Fields: item})
+	if err != nil {
+		return
+	}
+
+	req, err := http.NewRequest("POST", fmt.Sprintf("%s/collections/%s/items?live=true", c.baseAPI, c.collection), bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	if err = setIdempotencyKey(req); err != nil {
+		return
+	}
+
+	var res *http.Response
+	res, err = c.doRequest(ctx, req, msgPatternCreateErrorParticipant)
+	if err != nil {
+		return
+	}
+
+	var response leaderboardResponse
+	if err = json.NewDecoder(res.Body).Decode(&response); err != nil {
+		return
+	}
+	upstreamId = response.Id
+
+	c.logger.Debug("created new upstream participant", zap.Any("leaderboardItem", item))
+	return
+}
+
+func (c *webflowItemsClient) PatchScore(ctx context.Context, upstreamId string, score int) (err error) {
+	var payload struct {
+		Fields struct {
+			Score int `json:"score"`
+		} `json:"fields"`
+	}
+	payload.Fields.Score = score
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return
+	}
+
+	url := fmt.Sprintf("%s/collections/%s/items/%s?live=true", c.baseAPI, c.collection, upstreamId)
+	req, err := http.NewRequest("PATCH", url, bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	if err = setIdempotencyKey(req); err != nil {
+		return
+	}
+	req = req.WithContext(ctx)
+	c.requestHeaderSetup(req)
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		return
+	} else if res.StatusCode < 200 || res.StatusCode >= 300 {
+		c.logger.Debug("unexpected webflow response", zap.Any("request", req))
+		var responseBody []byte
+		_, _ = res.Body.Read(responseBody)
+		c.logger.Debug("webflow response body", zap.ByteString("body", responseBody))
+		err = &ParticipantUpdateError{Status: res.Status}
+		return
+	}
+
+	c.logger.Debug("updated upstream score", zap.String("participantUpstreamId", upstreamId), zap.Int("score", score))
+	return
+}
+
+// delete from upstream - warning: slugs are cached until webflow republishes site. create, delete, create will complain
+func (c *webflowItemsClient) DeleteParticipant(ctx context.Context, upstreamId string) (err error) {
+	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s/collections/%s/items/%s?live=true", c.baseAPI, c.collection, upstreamId), nil)
+	if err != nil {
+		return
+	}
+
+	_, err = c.doRequest(ctx, req, msgPatternDeleteErrorParticipant)
+	if err != nil {
+		return
+	}
+
+	c.logger.Debug("deleted upstream participant", zap.String("participantUpstreamId", upstreamId))
+	return
+}
@@ -0,0 +1,93 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package upstream
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// fakeUpstreamClient is an in-memory UpstreamClient used by tests in place of an
+// httptest.Server, the same role MemoryBackend plays for Backend. UpsertErr/PatchScoreErr/
+// DeleteErr let a test force the next call to that method to fail, to exercise error paths
+// without standing up a real HTTP handler.
+type fakeUpstreamClient struct {
+	mu     sync.Mutex
+	nextId int
+
+	Participants map[string]ParticipantDoc
+	Scores       map[string]int
+
+	UpsertErr     error
+	PatchScoreErr error
+	DeleteErr     error
+}
+
+var _ UpstreamClient = (*fakeUpstreamClient)(nil)
+
+// newFakeUpstreamClient builds an empty fakeUpstreamClient.
+func newFakeUpstreamClient() *fakeUpstreamClient {
+	return &fakeUpstreamClient{
+		Participants: map[string]ParticipantDoc{},
+		Scores:       map[string]int{},
+	}
+}
+
+func (f *fakeUpstreamClient) UpsertParticipant(_ context.Context, doc ParticipantDoc) (upstreamId string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.UpsertErr != nil {
+		return "", f.UpsertErr
+	}
+
+	f.nextId++
+	upstreamId = fmt.Sprintf("participant-%d", f.nextId)
+	f.Participants[upstreamId] = doc
+	f.Scores[upstreamId] = doc.Score
+	return
+}
+
+func (f *fakeUpstreamClient) PatchScore(_ context.Context, upstreamId string, score int) (err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.PatchScoreErr != nil {
+		return f.PatchScoreErr
+	}
+	if _, ok := f.Participants[upstreamId]; !ok {
+		return &ParticipantUpdateError{Status: "404 Not Found"}
+	}
+	f.Scores[upstreamId] = score
+	return
+}
+
+func (f *fakeUpstreamClient) DeleteParticipant(_ context.Context, upstreamId string) (err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.DeleteErr != nil {
+		return f.DeleteErr
+	}
+	delete(f.Participants, upstreamId)
+	delete(f.Scores, upstreamId)
+	return
+}
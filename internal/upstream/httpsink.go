@@ -0,0 +1,129 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package upstream
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+	"go.uber.org/zap"
+)
+
+const (
+	envHTTPSinkURL   = "UPSTREAM_HTTP_URL"
+	envHTTPSinkToken = "UPSTREAM_HTTP_TOKEN"
+)
+
+// httpSinkEvent is the generic JSON body posted to an HTTPSinkBackend for every mutation, so
+// that any HTTP service (a Firestore-backed function, a webhook relay, a custom dashboard
+// backend) can consume bbash leaderboard updates without speaking Webflow's CMS API.
+type httpSinkEvent struct {
+	Event               string                   `json:"event"`
+	Campaign            *types.CampaignStruct    `json:"campaign,omitempty"`
+	Participant         *types.ParticipantStruct `json:"participant,omitempty"`
+	CampaignUpstreamId  string                   `json:"campaignUpstreamId,omitempty"`
+	CampaignName        string                   `json:"campaignName,omitempty"`
+	ParticipantUpstream string                   `json:"participantUpstreamId,omitempty"`
+	Score               int                      `json:"score,omitempty"`
+	IsActive            bool                     `json:"isActive,omitempty"`
+}
+
+// HTTPSinkBackend is a Backend that POSTs a generic JSON event to a single configurable
+// endpoint for every mutation, for deployments without a Webflow account - e.g. a Cloud
+// Function in front of Firestore/Datastore, or any other JSON-speaking sink.
+type HTTPSinkBackend struct {
+	URL    string
+	Token  string
+	client *http.Client
+	logger *zap.Logger
+}
+
+var _ Backend = (*HTTPSinkBackend)(nil)
+
+// NewHTTPSinkBackend builds an HTTPSinkBackend configured from the UPSTREAM_HTTP_* environment variables.
+func NewHTTPSinkBackend(logger *zap.Logger) *HTTPSinkBackend {
+	return &HTTPSinkBackend{
+		URL:    os.Getenv(envHTTPSinkURL),
+		Token:  os.Getenv(envHTTPSinkToken),
+		client: &http.Client{Timeout: time.Second * 10},
+		logger: logger,
+	}
+}
+
+func (h *HTTPSinkBackend) post(ctx context.Context, event httpSinkEvent) (err error) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+
+	req, err := http.NewRequest(http.MethodPost, h.URL, bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	if h.Token != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", h.Token))
+	}
+
+	res, err := h.client.Do(req)
+	if err != nil {
+		return
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		err = fmt.Errorf("upstream http sink rejected %s event. response status: %s", event.Event, res.Status)
+		h.logger.Debug("http sink error", zap.Error(err))
+	}
+	return
+}
+
+func (h *HTTPSinkBackend) CreateCampaign(ctx context.Context, campaign *types.CampaignStruct, isActive bool) (upstreamId string, err error) {
+	upstreamId = campaign.ID
+	err = h.post(ctx, httpSinkEvent{Event: "campaign.create", Campaign: campaign, IsActive: isActive})
+	return
+}
+
+func (h *HTTPSinkBackend) ActivateCampaign(ctx context.Context, campaign *types.CampaignStruct, isActive bool) (upstreamId string, err error) {
+	upstreamId = campaign.UpstreamId
+	err = h.post(ctx, httpSinkEvent{Event: "campaign.activate", Campaign: campaign, IsActive: isActive})
+	return
+}
+
+func (h *HTTPSinkBackend) UpsertParticipant(ctx context.Context, participant *types.ParticipantStruct, campaignUpstreamId string) (upstreamId string, err error) {
+	upstreamId = participant.ID
+	err = h.post(ctx, httpSinkEvent{Event: "participant.upsert", Participant: participant, CampaignUpstreamId: campaignUpstreamId})
+	return
+}
+
+func (h *HTTPSinkBackend) UpdateScore(ctx context.Context, campaignName, participantUpstreamId string, score int) (err error) {
+	return h.post(ctx, httpSinkEvent{Event: "participant.score", CampaignName: campaignName, ParticipantUpstream: participantUpstreamId, Score: score})
+}
+
+func (h *HTTPSinkBackend) DeleteParticipant(ctx context.Context, participantUpstreamId string) (err error) {
+	return h.post(ctx, httpSinkEvent{Event: "participant.delete", ParticipantUpstream: participantUpstreamId})
+}
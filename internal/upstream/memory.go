@@ -0,0 +1,105 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package upstream
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+)
+
+// MemoryBackend keeps campaigns, participants and scores in memory. It never talks to the
+// network, making it suitable for tests and for running bbash without any upstream leaderboard.
+type MemoryBackend struct {
+	mu           sync.Mutex
+	nextId       int
+	Campaigns    map[string]types.CampaignStruct
+	Participants map[string]types.ParticipantStruct
+	Scores       map[string]int
+}
+
+var _ Backend = (*MemoryBackend)(nil)
+
+// NewMemoryBackend builds an empty MemoryBackend.
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{
+		Campaigns:    map[string]types.CampaignStruct{},
+		Participants: map[string]types.ParticipantStruct{},
+		Scores:       map[string]int{},
+	}
+}
+
+func (m *MemoryBackend) newId(prefix string) string {
+	m.nextId++
+	return fmt.Sprintf("%s-%d", prefix, m.nextId)
+}
+
+func (m *MemoryBackend) CreateCampaign(_ context.Context, campaign *types.CampaignStruct, _ bool) (upstreamId string, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	upstreamId = m.newId("campaign")
+	m.Campaigns[upstreamId] = *campaign
+	return
+}
+
+func (m *MemoryBackend) ActivateCampaign(_ context.Context, campaign *types.CampaignStruct, _ bool) (upstreamId string, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	upstreamId = campaign.UpstreamId
+	if upstreamId == "" {
+		upstreamId = m.newId("campaign")
+	}
+	m.Campaigns[upstreamId] = *campaign
+	return
+}
+
+func (m *MemoryBackend) UpsertParticipant(_ context.Context, participant *types.ParticipantStruct, _ string) (upstreamId string, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	upstreamId = m.newId("participant")
+	m.Participants[upstreamId] = *participant
+	m.Scores[upstreamId] = participant.Score
+	return
+}
+
+func (m *MemoryBackend) UpdateScore(_ context.Context, _, participantUpstreamId string, score int) (err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.Participants[participantUpstreamId]; !ok {
+		return fmt.Errorf("no such upstream participant: %s", participantUpstreamId)
+	}
+	m.Scores[participantUpstreamId] = score
+	return
+}
+
+func (m *MemoryBackend) DeleteParticipant(_ context.Context, participantUpstreamId string) (err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.Participants, participantUpstreamId)
+	delete(m.Scores, participantUpstreamId)
+	return
+}
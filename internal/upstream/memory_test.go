@@ -0,0 +1,65 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package upstream
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryBackendCampaignLifecycle(t *testing.T) {
+	backend := NewMemoryBackend()
+
+	campaign := types.CampaignStruct{Name: "myCampaign"}
+	upstreamId, err := backend.CreateCampaign(context.Background(), &campaign, false)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, upstreamId)
+	assert.Equal(t, campaign, backend.Campaigns[upstreamId])
+
+	campaign.UpstreamId = upstreamId
+	activatedId, err := backend.ActivateCampaign(context.Background(), &campaign, true)
+	assert.NoError(t, err)
+	assert.Equal(t, upstreamId, activatedId)
+}
+
+func TestMemoryBackendParticipantLifecycle(t *testing.T) {
+	backend := NewMemoryBackend()
+
+	participant := types.ParticipantStruct{LoginName: "theLoginName", Score: 1}
+	upstreamId, err := backend.UpsertParticipant(context.Background(), &participant, "theCampaignUpstreamId")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, backend.Scores[upstreamId])
+
+	assert.NoError(t, backend.UpdateScore(context.Background(), "someCampaign", upstreamId, 5))
+	assert.Equal(t, 5, backend.Scores[upstreamId])
+
+	assert.NoError(t, backend.DeleteParticipant(context.Background(), upstreamId))
+	_, stillPresent := backend.Participants[upstreamId]
+	assert.False(t, stillPresent)
+}
+
+func TestMemoryBackendUpdateScoreUnknownParticipant(t *testing.T) {
+	backend := NewMemoryBackend()
+	err := backend.UpdateScore(context.Background(), "someCampaign", "nonExistent", 5)
+	assert.EqualError(t, err, "no such upstream participant: nonExistent")
+}
@@ -0,0 +1,58 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package upstream
+
+import (
+	"context"
+
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+)
+
+// NoopBackend discards every call instead of keeping state like MemoryBackend does, for a
+// deployment that wants bbash's leaderboard sync disabled outright (e.g. while a real upstream is
+// being provisioned) without special-casing "is upstream enabled" at every call site.
+type NoopBackend struct{}
+
+var _ Backend = (*NoopBackend)(nil)
+
+// NewNoopBackend builds a Backend whose methods always succeed and never publish anything.
+func NewNoopBackend() *NoopBackend {
+	return &NoopBackend{}
+}
+
+func (NoopBackend) CreateCampaign(context.Context, *types.CampaignStruct, bool) (upstreamId string, err error) {
+	return
+}
+
+func (NoopBackend) ActivateCampaign(context.Context, *types.CampaignStruct, bool) (upstreamId string, err error) {
+	return
+}
+
+func (NoopBackend) UpsertParticipant(context.Context, *types.ParticipantStruct, string) (upstreamId string, err error) {
+	return
+}
+
+func (NoopBackend) UpdateScore(context.Context, string, string, int) (err error) {
+	return
+}
+
+func (NoopBackend) DeleteParticipant(context.Context, string) (err error) {
+	return
+}
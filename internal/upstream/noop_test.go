@@ -0,0 +1,47 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package upstream
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNoopBackendNeverFails(t *testing.T) {
+	backend := NewNoopBackend()
+
+	upstreamId, err := backend.CreateCampaign(context.Background(), &types.CampaignStruct{Name: "myCampaign"}, true)
+	assert.NoError(t, err)
+	assert.Empty(t, upstreamId)
+
+	upstreamId, err = backend.ActivateCampaign(context.Background(), &types.CampaignStruct{}, true)
+	assert.NoError(t, err)
+	assert.Empty(t, upstreamId)
+
+	upstreamId, err = backend.UpsertParticipant(context.Background(), &types.ParticipantStruct{}, "theCampaignUpstreamId")
+	assert.NoError(t, err)
+	assert.Empty(t, upstreamId)
+
+	assert.NoError(t, backend.UpdateScore(context.Background(), "someCampaign", "theParticipantUpstreamId", 42))
+	assert.NoError(t, backend.DeleteParticipant(context.Background(), "theParticipantUpstreamId"))
+}
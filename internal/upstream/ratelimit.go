@@ -0,0 +1,168 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package upstream
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// tooManyRequestsSlowdownFactor is how much ReportTooManyRequests cuts the effective rate by on
+// every 429, and minRateFraction is the floor (as a fraction of the configured base rate) that
+// cutting can never go below, so a provider returning sustained 429s can't drive the limiter to a
+// standstill.
+const tooManyRequestsSlowdownFactor = 0.5
+const minRateFraction = 0.1
+
+// recoveryFractionPerSecond is how fast the effective rate recovers back towards the configured
+// base rate once 429s stop, expressed as a fraction of the base rate regained per second.
+const recoveryFractionPerSecond = 0.05
+
+// RateLimiter is a simple token bucket shared across every call a Backend makes upstream, so
+// that bursts from several API calls in the same polling tick don't exceed a provider's
+// per-minute quota (e.g. Webflow's CMS API). Its effective rate backs off on sustained 429s (see
+// ReportTooManyRequests) and recovers gradually afterwards, rather than retrying at the same rate
+// that got rate-limited in the first place.
+type RateLimiter struct {
+	mu          sync.Mutex
+	tokens      float64
+	burst       float64
+	baseRate    float64
+	currentRate float64
+	lastRefill  time.Time
+	sleep       func(time.Duration)
+	metrics     RateLimiterMetrics
+}
+
+// RateLimiterMetrics counts what a RateLimiter has done, the same atomic-counter-with-snapshot
+// shape as RetryMetrics, so callers can alert on a client that's spending most of its time waiting
+// or getting rate-limited.
+type RateLimiterMetrics struct {
+	Permits         int64
+	Waits           int64
+	TooManyRequests int64
+}
+
+func (m *RateLimiterMetrics) snapshot() RateLimiterMetrics {
+	return RateLimiterMetrics{
+		Permits:         atomic.LoadInt64(&m.Permits),
+		Waits:           atomic.LoadInt64(&m.Waits),
+		TooManyRequests: atomic.LoadInt64(&m.TooManyRequests),
+	}
+}
+
+// NewRateLimiter builds a RateLimiter allowing ratePerSecond sustained requests with bursts up
+// to burst tokens. A ratePerSecond <= 0 disables limiting.
+func NewRateLimiter(ratePerSecond float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		tokens:      float64(burst),
+		burst:       float64(burst),
+		baseRate:    ratePerSecond,
+		currentRate: ratePerSecond,
+		lastRefill:  time.Now(),
+		sleep:       time.Sleep,
+	}
+}
+
+// Metrics returns a point-in-time snapshot of permits served, waits, and 429s reported.
+func (r *RateLimiter) Metrics() RateLimiterMetrics {
+	if r == nil {
+		return RateLimiterMetrics{}
+	}
+	return r.metrics.snapshot()
+}
+
+// ReportTooManyRequests backs the limiter's effective rate off by tooManyRequestsSlowdownFactor,
+// down to a floor of minRateFraction*baseRate - called by retryingClient whenever the upstream
+// answers with a 429, so a sustained burst of rejections slows every subsequent caller down
+// instead of retrying at the rate that triggered them. recoverLocked brings the rate back up
+// gradually once 429s stop.
+func (r *RateLimiter) ReportTooManyRequests() {
+	if r == nil || r.baseRate <= 0 {
+		return
+	}
+	atomic.AddInt64(&r.metrics.TooManyRequests, 1)
+
+	r.mu.Lock()
+	floor := r.baseRate * minRateFraction
+	r.currentRate = maxFloat(floor, r.currentRate*tooManyRequestsSlowdownFactor)
+	r.mu.Unlock()
+}
+
+// Wait blocks until a token is available or ctx is done, then consumes it. Passing ctx lets a
+// caller give up on a request it no longer cares about instead of holding the limiter hostage.
+func (r *RateLimiter) Wait(ctx context.Context) (err error) {
+	if r == nil || r.baseRate <= 0 {
+		return
+	}
+
+	for {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+
+		r.mu.Lock()
+		now := time.Now()
+		elapsed := now.Sub(r.lastRefill).Seconds()
+		r.recoverLocked(elapsed)
+		r.tokens = minFloat(r.burst, r.tokens+elapsed*r.currentRate)
+		r.lastRefill = now
+
+		if r.tokens >= 1 {
+			r.tokens--
+			r.mu.Unlock()
+			atomic.AddInt64(&r.metrics.Permits, 1)
+			return nil
+		}
+
+		wait := time.Duration((1 - r.tokens) / r.currentRate * float64(time.Second))
+		r.mu.Unlock()
+		atomic.AddInt64(&r.metrics.Waits, 1)
+		r.sleep(wait)
+	}
+}
+
+// recoverLocked nudges currentRate back toward baseRate by however much recoveryFractionPerSecond
+// allows in elapsedSeconds; must be called with mu held.
+func (r *RateLimiter) recoverLocked(elapsedSeconds float64) {
+	if r.currentRate >= r.baseRate {
+		return
+	}
+	r.currentRate = minFloat(r.baseRate, r.currentRate+r.baseRate*recoveryFractionPerSecond*elapsedSeconds)
+}
+
+func minFloat(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxFloat(a, b float64) float64 {
+	if a > b {
+		return a
+	}
+	return b
+}
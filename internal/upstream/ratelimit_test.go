@@ -0,0 +1,132 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package upstream
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiterAllowsBurstWithoutSleeping(t *testing.T) {
+	limiter := NewRateLimiter(1, 3)
+	var slept time.Duration
+	limiter.sleep = func(d time.Duration) { slept += d }
+
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, limiter.Wait(context.Background()))
+	}
+	assert.Zero(t, slept)
+	assert.EqualValues(t, 3, limiter.Metrics().Permits)
+	assert.Zero(t, limiter.Metrics().Waits)
+}
+
+func TestRateLimiterSleepsPastBurst(t *testing.T) {
+	limiter := NewRateLimiter(1, 1)
+	var slept time.Duration
+	limiter.sleep = func(d time.Duration) {
+		slept += d
+		limiter.mu.Lock()
+		limiter.tokens = limiter.burst
+		limiter.mu.Unlock()
+	}
+
+	assert.NoError(t, limiter.Wait(context.Background()))
+	assert.NoError(t, limiter.Wait(context.Background()))
+	assert.Greater(t, slept, time.Duration(0))
+	assert.EqualValues(t, 1, limiter.Metrics().Waits)
+}
+
+func TestRateLimiterDisabledWhenRateIsZero(t *testing.T) {
+	limiter := NewRateLimiter(0, 1)
+	var slept time.Duration
+	limiter.sleep = func(d time.Duration) { slept += d }
+
+	for i := 0; i < 10; i++ {
+		assert.NoError(t, limiter.Wait(context.Background()))
+	}
+	assert.Zero(t, slept)
+}
+
+// TestRateLimiterPacesNRequests asserts a fake clock sees every request beyond the initial burst
+// paced out by roughly 1/rate, rather than let through immediately.
+func TestRateLimiterPacesNRequests(t *testing.T) {
+	limiter := NewRateLimiter(2, 1)
+	var slept []time.Duration
+	limiter.sleep = func(d time.Duration) {
+		slept = append(slept, d)
+		limiter.mu.Lock()
+		limiter.tokens = limiter.burst
+		limiter.mu.Unlock()
+	}
+
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, limiter.Wait(context.Background()))
+	}
+
+	assert.Equal(t, 4, len(slept))
+	for _, d := range slept {
+		assert.InDelta(t, 500*time.Millisecond, d, float64(50*time.Millisecond))
+	}
+}
+
+func TestRateLimiterWaitReturnsOnContextCancellation(t *testing.T) {
+	limiter := NewRateLimiter(1, 1)
+	limiter.sleep = func(time.Duration) {}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.NoError(t, limiter.Wait(context.Background()))
+	assert.ErrorIs(t, limiter.Wait(ctx), context.Canceled)
+}
+
+func TestRateLimiterReportTooManyRequestsSlowsDownThenRecovers(t *testing.T) {
+	limiter := NewRateLimiter(10, 1)
+	assert.Equal(t, 10.0, limiter.currentRate)
+
+	limiter.ReportTooManyRequests()
+	assert.Equal(t, 5.0, limiter.currentRate)
+	assert.EqualValues(t, 1, limiter.Metrics().TooManyRequests)
+
+	limiter.mu.Lock()
+	limiter.lastRefill = limiter.lastRefill.Add(-time.Second)
+	limiter.mu.Unlock()
+
+	assert.NoError(t, limiter.Wait(context.Background()))
+	assert.Greater(t, limiter.currentRate, 5.0)
+	assert.LessOrEqual(t, limiter.currentRate, 10.0)
+}
+
+func TestRateLimiterReportTooManyRequestsHasAFloor(t *testing.T) {
+	limiter := NewRateLimiter(10, 1)
+	for i := 0; i < 10; i++ {
+		limiter.ReportTooManyRequests()
+	}
+	assert.Equal(t, 1.0, limiter.currentRate)
+}
+
+func TestRateLimiterReportTooManyRequestsDisabledWhenRateIsZero(t *testing.T) {
+	limiter := NewRateLimiter(0, 1)
+	limiter.ReportTooManyRequests()
+	assert.Zero(t, limiter.Metrics().TooManyRequests)
+}
@@ -0,0 +1,170 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package upstream
+
+import (
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// RetryConfig controls how retryingClient retries a request that Webflow rejected with a
+// rate-limit (429) or transient server (5xx) status.
+type RetryConfig struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// DefaultRetryConfig is a conservative default: five attempts, capped at 30s between tries.
+var DefaultRetryConfig = RetryConfig{
+	MaxAttempts: 5,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    30 * time.Second,
+}
+
+// RetryMetrics counts what retryingClient has done, so callers can alert on sustained giveups.
+type RetryMetrics struct {
+	Attempts int64
+	Retries  int64
+	Giveups  int64
+}
+
+func (m *RetryMetrics) snapshot() RetryMetrics {
+	return RetryMetrics{
+		Attempts: atomic.LoadInt64(&m.Attempts),
+		Retries:  atomic.LoadInt64(&m.Retries),
+		Giveups:  atomic.LoadInt64(&m.Giveups),
+	}
+}
+
+// retryingClient wraps an *http.Client with exponential backoff (honoring Retry-After when the
+// upstream sends one), a shared RateLimiter, and attempt/retry/giveup metrics. Request bodies
+// are rewound between attempts via http.Request.GetBody, which http.NewRequest populates
+// automatically for bytes.Reader/bytes.Buffer/strings.Reader bodies.
+type retryingClient struct {
+	client  *http.Client
+	limiter *RateLimiter
+	config  RetryConfig
+	logger  *zap.Logger
+	metrics RetryMetrics
+}
+
+func newRetryingClient(client *http.Client, limiter *RateLimiter, config RetryConfig, logger *zap.Logger) *retryingClient {
+	return &retryingClient{client: client, limiter: limiter, config: config, logger: logger}
+}
+
+// Metrics returns a point-in-time snapshot of attempts/retries/giveups.
+func (c *retryingClient) Metrics() RetryMetrics {
+	return c.metrics.snapshot()
+}
+
+// LimiterMetrics returns a point-in-time snapshot of the shared RateLimiter's permits/waits/429s,
+// or a zero value if this client has none configured.
+func (c *retryingClient) LimiterMetrics() RateLimiterMetrics {
+	return c.limiter.Metrics()
+}
+
+func (c *retryingClient) Do(req *http.Request) (res *http.Response, err error) {
+	for attempt := 1; attempt <= c.config.MaxAttempts; attempt++ {
+		if c.limiter != nil {
+			if err = c.limiter.Wait(req.Context()); err != nil {
+				return
+			}
+		}
+
+		atomic.AddInt64(&c.metrics.Attempts, 1)
+		res, err = c.client.Do(req)
+
+		if !shouldRetry(res, err) {
+			return
+		}
+
+		if res != nil && res.StatusCode == http.StatusTooManyRequests && c.limiter != nil {
+			c.limiter.ReportTooManyRequests()
+		}
+
+		if attempt == c.config.MaxAttempts {
+			atomic.AddInt64(&c.metrics.Giveups, 1)
+			return
+		}
+
+		delay := c.backoff(attempt, res)
+		c.logger.Debug("retrying upstream request",
+			zap.Int("attempt", attempt), zap.Duration("delay", delay), zap.Error(err))
+		atomic.AddInt64(&c.metrics.Retries, 1)
+
+		if res != nil {
+			_ = res.Body.Close()
+		}
+		if req.GetBody != nil {
+			if req.Body, err = req.GetBody(); err != nil {
+				return
+			}
+		}
+
+		time.Sleep(delay)
+	}
+	return
+}
+
+func shouldRetry(res *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= 500
+}
+
+// backoff honors a Retry-After header (seconds or HTTP-date) when present, otherwise falls
+// back to exponential backoff with jitter capped at config.MaxDelay.
+func (c *retryingClient) backoff(attempt int, res *http.Response) time.Duration {
+	if res != nil {
+		if retryAfter, ok := parseRetryAfter(res.Header.Get("Retry-After")); ok {
+			return retryAfter
+		}
+	}
+
+	delay := c.config.BaseDelay << (attempt - 1)
+	if delay > c.config.MaxDelay || delay <= 0 {
+		delay = c.config.MaxDelay
+	}
+	jitter := time.Duration(rand.Int63n(int64(delay) + 1))
+	return delay/2 + jitter/2
+}
+
+func parseRetryAfter(value string) (delay time.Duration, ok bool) {
+	if value == "" {
+		return
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if when, err := http.ParseTime(value); err == nil {
+		if d := time.Until(when); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return
+}
@@ -0,0 +1,83 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+// Package upstream publishes campaigns, participants and scores to whatever
+// leaderboard a deployment is configured to use, hiding the differences
+// between CMS-backed (Webflow), generic HTTP and in-memory sinks behind a
+// single Backend interface.
+package upstream
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+	"go.uber.org/zap"
+)
+
+// Backend is implemented by anything capable of mirroring campaign,
+// participant and score state to an upstream leaderboard. Every method takes a context.Context
+// so a caller (e.g. an HTTP handler or the poll loop) can cancel an in-flight request.
+type Backend interface {
+	// CreateCampaign publishes a brand new campaign and returns its upstream id.
+	CreateCampaign(ctx context.Context, campaign *types.CampaignStruct, isActive bool) (upstreamId string, err error)
+
+	// ActivateCampaign updates the active flag of a campaign already known upstream.
+	ActivateCampaign(ctx context.Context, campaign *types.CampaignStruct, isActive bool) (upstreamId string, err error)
+
+	// UpsertParticipant publishes a new participant under campaignUpstreamId and returns its upstream id.
+	UpsertParticipant(ctx context.Context, participant *types.ParticipantStruct, campaignUpstreamId string) (upstreamId string, err error)
+
+	// UpdateScore pushes a new score for the participant known upstream as participantUpstreamId.
+	// campaignName scopes any per-campaign rate-limit quota a Backend applies (see
+	// CampaignRateLimiters) so one campaign's scoring volume can't starve another's.
+	UpdateScore(ctx context.Context, campaignName, participantUpstreamId string, score int) (err error)
+
+	// DeleteParticipant removes a participant from the upstream leaderboard.
+	DeleteParticipant(ctx context.Context, participantUpstreamId string) (err error)
+}
+
+// envBackendKind selects which Backend implementation setupUpstream() wires up.
+const envBackendKind = "UPSTREAM_BACKEND"
+
+const (
+	KindWebflow = "webflow"
+	KindHTTP    = "http"
+	KindMemory  = "memory"
+	KindNone    = "none"
+)
+
+// NewBackend reads envBackendKind and builds the matching Backend, defaulting to Webflow
+// to preserve existing deployments that don't set the variable at all.
+func NewBackend(logger *zap.Logger) (backend Backend, err error) {
+	switch kind := os.Getenv(envBackendKind); kind {
+	case "", KindWebflow:
+		backend = NewWebflowBackend(logger)
+	case KindHTTP:
+		backend = NewHTTPSinkBackend(logger)
+	case KindMemory:
+		backend = NewMemoryBackend()
+	case KindNone:
+		backend = NewNoopBackend()
+	default:
+		err = fmt.Errorf("unknown upstream backend kind: %q", kind)
+	}
+	return
+}
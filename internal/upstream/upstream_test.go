@@ -0,0 +1,71 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package upstream
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestNewBackendDefaultsToWebflow(t *testing.T) {
+	_ = os.Unsetenv(envBackendKind)
+	backend, err := NewBackend(zap.NewNop())
+	assert.NoError(t, err)
+	assert.IsType(t, &WebflowBackend{}, backend)
+}
+
+func TestNewBackendMemory(t *testing.T) {
+	assert.NoError(t, os.Setenv(envBackendKind, KindMemory))
+	defer func() { _ = os.Unsetenv(envBackendKind) }()
+
+	backend, err := NewBackend(zap.NewNop())
+	assert.NoError(t, err)
+	assert.IsType(t, &MemoryBackend{}, backend)
+}
+
+func TestNewBackendHTTP(t *testing.T) {
+	assert.NoError(t, os.Setenv(envBackendKind, KindHTTP))
+	defer func() { _ = os.Unsetenv(envBackendKind) }()
+
+	backend, err := NewBackend(zap.NewNop())
+	assert.NoError(t, err)
+	assert.IsType(t, &HTTPSinkBackend{}, backend)
+}
+
+func TestNewBackendNone(t *testing.T) {
+	assert.NoError(t, os.Setenv(envBackendKind, KindNone))
+	defer func() { _ = os.Unsetenv(envBackendKind) }()
+
+	backend, err := NewBackend(zap.NewNop())
+	assert.NoError(t, err)
+	assert.IsType(t, &NoopBackend{}, backend)
+}
+
+func TestNewBackendUnknownKind(t *testing.T) {
+	assert.NoError(t, os.Setenv(envBackendKind, "bogus"))
+	defer func() { _ = os.Unsetenv(envBackendKind) }()
+
+	backend, err := NewBackend(zap.NewNop())
+	assert.Nil(t, backend)
+	assert.EqualError(t, err, `unknown upstream backend kind: "bogus"`)
+}
@@ -0,0 +1,352 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package upstream
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+	"go.uber.org/zap"
+)
+
+const (
+	envWebflowToken                 = "WEBFLOW_TOKEN"
+	envWebflowCampaignCollectionId  = "WEBFLOW_CAMPAIGN_COLLECTION_ID"
+	envWebflowParticipantCollection = "WEBFLOW_COLLECTION_ID"
+)
+
+// WebflowApiBase is the default base url for the Webflow CMS API.
+const WebflowApiBase string = "https://api.webflow.com"
+
+type webflowConfig struct {
+	// baseAPI allows tests to point at a local httptest.Server
+	baseAPI               string
+	token                 string
+	campaignCollection    string // campaign CMS collection id
+	participantCollection string // participant CMS collection id
+}
+
+type leaderboardItem struct {
+	UserName           string `json:"name"`
+	Slug               string `json:"slug"`
+	Score              int    `json:"score"`
+	CampaignUpstreamId string `json:"campaign-reference"`
+	Archived           bool   `json:"_archived"`
+	Draft              bool   `json:"_draft"`
+}
+
+type leaderboardPayload struct {
+	Fields leaderboardItem `json:"fields"`
+}
+
+type leaderboardResponse struct {
+	Id string `json:"_id"`
+}
+
+type leaderboardCampaign struct {
+	CampaignName string `json:"name"`
+	Slug         string `json:"slug"`
+	CreateOrder  int    `json:"create-order"`
+	Active       bool   `json:"active"`
+	Note         string `json:"note"`
+	Archived     bool   `json:"_archived"`
+	Draft        bool   `json:"_draft"`
+}
+
+type leaderboardCampaignPayload struct {
+	Fields leaderboardCampaign `json:"fields"`
+}
+
+type leaderboardCampaignResponse struct {
+	Id string `json:"_id"`
+}
+
+const msgPatternCreateErrorCampaign = "could not create upstream campaign. response status: %s"
+const msgPatternActivateErrorCampaign = "could not activate upstream campaign. response status: %s"
+const msgPatternCreateErrorParticipant = "could not create upstream participant. response status: %s"
+const msgPatternDeleteErrorParticipant = "could not delete upstream participant. response status: %s"
+
+// CreateError is returned when Webflow rejects a create/activate/delete request.
+type CreateError struct {
+	MsgPattern string
+	Status     string
+}
+
+func (e *CreateError) Error() string {
+	return fmt.Sprintf(e.MsgPattern, e.Status)
+}
+
+// ParticipantUpdateError is returned when Webflow rejects a score update request.
+type ParticipantUpdateError struct {
+	Status string
+}
+
+func (e *ParticipantUpdateError) Error() string {
+	return fmt.Sprintf("could not update score. response status: %s", e.Status)
+}
+
+// envWebflowRateLimitPerSecond caps the sustained rate of requests sent to Webflow; Webflow
+// enforces its own per-minute quota and returns 429s once exceeded.
+const envWebflowRateLimitPerSecond = "WEBFLOW_RATE_LIMIT_PER_SECOND"
+
+const defaultWebflowRateLimitPerSecond = 1.0
+const defaultWebflowRateLimitBurst = 5
+
+// envWebflowCampaignRateLimitPerSecond caps the sustained rate of score-update requests a single
+// campaign may spend out of the shared Webflow quota above; unset disables per-campaign limiting.
+const envWebflowCampaignRateLimitPerSecond = "WEBFLOW_CAMPAIGN_RATE_LIMIT_PER_SECOND"
+
+const defaultWebflowCampaignRateLimitBurst = 5
+
+// WebflowBackend is the original Backend implementation, publishing campaigns and
+// participants to Webflow CMS collections via their REST API. Requests are retried with
+// exponential backoff (honoring Retry-After) and rate-limited with a shared token bucket, since
+// Webflow enforces per-minute request quotas. campaignLimiters layers a per-campaign quota on top
+// of that shared one, so one campaign scoring heavily can't exhaust the budget every other
+// campaign's score updates also draw from. Participant operations (create/score/delete) are
+// delegated to items, an UpstreamClient - see client.go - so they can be exercised through
+// fakeUpstreamClient without an httptest.Server.
+type WebflowBackend struct {
+	config           webflowConfig
+	logger           *zap.Logger
+	http             *retryingClient
+	items            UpstreamClient
+	campaignLimiters *CampaignRateLimiters
+}
+
+var _ Backend = (*WebflowBackend)(nil)
+
+// NewWebflowBackend builds a WebflowBackend configured from the WEBFLOW_* environment variables.
+func NewWebflowBackend(logger *zap.Logger) *WebflowBackend {
+	rateLimit := defaultWebflowRateLimitPerSecond
+	if configured, err := strconv.ParseFloat(os.Getenv(envWebflowRateLimitPerSecond), 64); err == nil && configured > 0 {
+		rateLimit = configured
+	}
+
+	campaignRateLimit := 0.0
+	if configured, err := strconv.ParseFloat(os.Getenv(envWebflowCampaignRateLimitPerSecond), 64); err == nil && configured > 0 {
+		campaignRateLimit = configured
+	}
+
+	config := webflowConfig{
+		baseAPI:               WebflowApiBase,
+		token:                 os.Getenv(envWebflowToken),
+		campaignCollection:    os.Getenv(envWebflowCampaignCollectionId),
+		participantCollection: os.Getenv(envWebflowParticipantCollection),
+	}
+	httpClient := newRetryingClient(
+		&http.Client{Timeout: time.Second * 10},
+		NewRateLimiter(rateLimit, defaultWebflowRateLimitBurst),
+		DefaultRetryConfig,
+		logger,
+	)
+
+	return &WebflowBackend{
+		logger:           logger,
+		config:           config,
+		http:             httpClient,
+		items:            newWebflowItemsClient(config.baseAPI, config.token, config.participantCollection, httpClient, logger),
+		campaignLimiters: NewCampaignRateLimiters(campaignRateLimit, defaultWebflowCampaignRateLimitBurst),
+	}
+}
+
+// Metrics returns the attempt/retry/giveup counts accumulated by this backend's http client.
+func (w *WebflowBackend) Metrics() RetryMetrics {
+	return w.http.Metrics()
+}
+
+// LimiterMetrics returns the permits/waits/429s accumulated by this backend's shared RateLimiter.
+func (w *WebflowBackend) LimiterMetrics() RateLimiterMetrics {
+	return w.http.LimiterMetrics()
+}
+
+func (w *WebflowBackend) requestHeaderSetup(req *http.Request) {
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", w.config.token))
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("accept-version", "1.0.0")
+}
+
+// headerIdempotencyKey is sent with every create/score-update request, since POST to
+// /collections/{c}/items (and the score PATCH) is not naturally idempotent: if Webflow commits
+// the write but the response is lost, retryingClient would otherwise resend the same create as a
+// second, duplicate item. retryingClient.Do retries the same *http.Request object on every
+// attempt, so setting this header once on req before the first attempt keeps it stable across
+// retries without any changes to the retry loop itself.
+const headerIdempotencyKey = "Idempotency-Key"
+
+// generateIdempotencyKey returns a random hex key, hand-rolled the same way as
+// auth.GenerateToken/generateRequestID rather than pulling in a UUID library for it.
+func generateIdempotencyKey() (key string, err error) {
+	raw := make([]byte, 16)
+	if _, err = rand.Read(raw); err != nil {
+		return
+	}
+	key = hex.EncodeToString(raw)
+	return
+}
+
+// setIdempotencyKey generates a fresh key for this logical operation and attaches it to req.
+func setIdempotencyKey(req *http.Request) (err error) {
+	key, err := generateIdempotencyKey()
+	if err != nil {
+		return
+	}
+	req.Header.Set(headerIdempotencyKey, key)
+	return
+}
+
+func (w *WebflowBackend) doRequest(ctx context.Context, req *http.Request, errMsgPattern string) (res *http.Response, err error) {
+	req = req.WithContext(ctx)
+	w.requestHeaderSetup(req)
+
+	res, err = w.http.Do(req)
+	if err != nil {
+		return
+	}
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		defer res.Body.Close()
+		w.logger.Debug("unexpected webflow response", zap.Any("response", res))
+		var responseBody []byte
+		_, _ = res.Body.Read(responseBody)
+		w.logger.Debug("webflow response body", zap.ByteString("body", responseBody))
+		err = &CreateError{MsgPattern: errMsgPattern, Status: res.Status}
+		return
+	}
+	return
+}
+
+func (w *WebflowBackend) CreateCampaign(ctx context.Context, campaign *types.CampaignStruct, isActive bool) (upstreamId string, err error) {
+	item := leaderboardCampaign{
+		CampaignName: campaign.Name,
+		Slug:         campaign.Name,
+		CreateOrder:  campaign.CreatedOrder,
+		Active:       isActive,
+		Note:         "",
+		Archived:     false,
+		Draft:        false,
+	}
+
+	payload := leaderboardCampaignPayload{Fields: item}
+
+	var body []byte
+	body, err = json.Marshal(payload)
+	if err != nil {
+		return
+	}
+
+	var req *http.Request
+	req, err = http.NewRequest("POST", fmt.Sprintf("%s/collections/%s/items?live=true", w.config.baseAPI, w.config.campaignCollection), bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	if err = setIdempotencyKey(req); err != nil {
+		return
+	}
+
+	var res *http.Response
+	res, err = w.doRequest(ctx, req, msgPatternCreateErrorCampaign)
+	if err != nil {
+		return
+	}
+
+	var response leaderboardCampaignResponse
+	err = json.NewDecoder(res.Body).Decode(&response)
+	if err != nil {
+		return
+	}
+	upstreamId = response.Id
+
+	w.logger.Debug("created new upstream campaign", zap.Any("leaderboardCampaign", item))
+	return
+}
+
+func (w *WebflowBackend) ActivateCampaign(ctx context.Context, campaign *types.CampaignStruct, isActive bool) (upstreamId string, err error) {
+	item := leaderboardCampaign{
+		CampaignName: campaign.Name,
+		Slug:         campaign.Name,
+		CreateOrder:  campaign.CreatedOrder,
+		Active:       isActive,
+	}
+
+	payload := leaderboardCampaignPayload{Fields: item}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return
+	}
+
+	req, err := http.NewRequest("PUT", fmt.Sprintf("%s/collections/%s/items/%s?live=true",
+		w.config.baseAPI, w.config.campaignCollection, campaign.UpstreamId), bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+
+	var res *http.Response
+	res, err = w.doRequest(ctx, req, msgPatternActivateErrorCampaign)
+	if err != nil {
+		return
+	}
+
+	var response leaderboardCampaignResponse
+	err = json.NewDecoder(res.Body).Decode(&response)
+	if err != nil {
+		return
+	}
+	upstreamId = response.Id
+
+	w.logger.Debug("updated upstream campaign", zap.Any("leaderboardCampaign", item))
+	return
+}
+
+func (w *WebflowBackend) UpsertParticipant(ctx context.Context, participant *types.ParticipantStruct, campaignUpstreamId string) (upstreamId string, err error) {
+	return w.items.UpsertParticipant(ctx, ParticipantDoc{
+		LoginName:          participant.LoginName,
+		Score:              participant.Score,
+		CampaignUpstreamId: campaignUpstreamId,
+	})
+}
+
+func (w *WebflowBackend) UpdateScore(ctx context.Context, campaignName, participantUpstreamId string, score int) (err error) {
+	if err = w.campaignLimiters.For(campaignName).Wait(ctx); err != nil {
+		return
+	}
+
+	return w.items.PatchScore(ctx, participantUpstreamId, score)
+}
+
+// delete from upstream - warning: slugs are cached until webflow republishes site. create, delete, create will complain
+func (w *WebflowBackend) DeleteParticipant(ctx context.Context, participantUpstreamId string) (err error) {
+	if err = w.items.DeleteParticipant(ctx, participantUpstreamId); err != nil {
+		return
+	}
+
+	w.logger.Debug("deleted upstream participant", zap.String("participantUpstreamId", participantUpstreamId))
+	return
+}
@@ -0,0 +1,235 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.16
+// +build go1.16
+
+package upstream
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func testWebflowBackend(baseURL string) *WebflowBackend {
+	logger := zap.NewNop()
+	config := webflowConfig{
+		baseAPI:               baseURL,
+		token:                 "testWfToken",
+		campaignCollection:    "testCampaignCollection",
+		participantCollection: "testParticipantCollection",
+	}
+	httpClient := newRetryingClient(&http.Client{}, nil, RetryConfig{MaxAttempts: 1}, logger)
+	return &WebflowBackend{
+		logger:           logger,
+		config:           config,
+		http:             httpClient,
+		items:            newWebflowItemsClient(config.baseAPI, config.token, config.participantCollection, httpClient, logger),
+		campaignLimiters: NewCampaignRateLimiters(0, defaultWebflowCampaignRateLimitBurst),
+	}
+}
+
+// testWebflowBackendWithFakeItems builds a WebflowBackend whose campaign operations still talk to
+// an httptest.Server at baseURL, but whose participant operations (create/score/delete) are
+// delegated to fake instead of a real HTTP round trip.
+func testWebflowBackendWithFakeItems(baseURL string, fake *fakeUpstreamClient) *WebflowBackend {
+	backend := testWebflowBackend(baseURL)
+	backend.items = fake
+	return backend
+}
+
+func TestWebflowBackendCreateCampaignValidId(t *testing.T) {
+	testId := "testNewWebflowCampaignId"
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/collections/testCampaignCollection/items", r.URL.EscapedPath())
+		assert.Equal(t, "Bearer testWfToken", r.Header.Get("Authorization"))
+
+		w.WriteHeader(http.StatusOK)
+		body, err := json.Marshal(leaderboardCampaignResponse{Id: testId})
+		assert.NoError(t, err)
+		_, err = w.Write(body)
+		assert.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	backend := testWebflowBackend(ts.URL)
+	upstreamId, err := backend.CreateCampaign(context.Background(), &types.CampaignStruct{Name: "myCampaign"}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, testId, upstreamId)
+}
+
+func TestWebflowBackendCreateCampaignErrorNotFound(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	backend := testWebflowBackend(ts.URL)
+	upstreamId, err := backend.CreateCampaign(context.Background(), &types.CampaignStruct{}, true)
+	assert.Equal(t, "", upstreamId)
+	expectedErr := &CreateError{msgPatternCreateErrorCampaign, "404 Not Found"}
+	assert.EqualError(t, err, expectedErr.Error())
+}
+
+func TestWebflowBackendActivateCampaign(t *testing.T) {
+	testId := "testActivatedCampaignId"
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, fmt.Sprintf("/collections/testCampaignCollection/items/%s", "theCampaignUpstreamId"), r.URL.EscapedPath())
+
+		w.WriteHeader(http.StatusOK)
+		body, err := json.Marshal(leaderboardCampaignResponse{Id: testId})
+		assert.NoError(t, err)
+		_, err = w.Write(body)
+		assert.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	backend := testWebflowBackend(ts.URL)
+	upstreamId, err := backend.ActivateCampaign(context.Background(), &types.CampaignStruct{UpstreamId: "theCampaignUpstreamId"}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, testId, upstreamId)
+}
+
+func TestWebflowBackendUpsertParticipant(t *testing.T) {
+	fake := newFakeUpstreamClient()
+	backend := testWebflowBackendWithFakeItems("", fake)
+
+	upstreamId, err := backend.UpsertParticipant(context.Background(), &types.ParticipantStruct{LoginName: "theLoginName", Score: 7}, "theCampaignUpstreamId")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, upstreamId)
+	assert.Equal(t, ParticipantDoc{LoginName: "theLoginName", Score: 7, CampaignUpstreamId: "theCampaignUpstreamId"}, fake.Participants[upstreamId])
+}
+
+func TestWebflowBackendUpsertParticipantUpstreamError(t *testing.T) {
+	fake := newFakeUpstreamClient()
+	fake.UpsertErr = &CreateError{MsgPattern: msgPatternCreateErrorParticipant, Status: "404 Not Found"}
+	backend := testWebflowBackendWithFakeItems("", fake)
+
+	_, err := backend.UpsertParticipant(context.Background(), &types.ParticipantStruct{LoginName: "theLoginName"}, "theCampaignUpstreamId")
+	assert.EqualError(t, err, fake.UpsertErr.Error())
+}
+
+func TestWebflowBackendUpdateScore(t *testing.T) {
+	fake := newFakeUpstreamClient()
+	upstreamId, err := fake.UpsertParticipant(context.Background(), ParticipantDoc{LoginName: "theLoginName"})
+	assert.NoError(t, err)
+
+	backend := testWebflowBackendWithFakeItems("", fake)
+	assert.NoError(t, backend.UpdateScore(context.Background(), "someCampaign", upstreamId, 42))
+	assert.Equal(t, 42, fake.Scores[upstreamId])
+}
+
+func TestWebflowBackendUpdateScoreError(t *testing.T) {
+	fake := newFakeUpstreamClient()
+	backend := testWebflowBackendWithFakeItems("", fake)
+
+	err := backend.UpdateScore(context.Background(), "someCampaign", "noSuchUpstreamId", 42)
+	expectedErr := &ParticipantUpdateError{"404 Not Found"}
+	assert.EqualError(t, err, expectedErr.Error())
+}
+
+func TestWebflowBackendCreateCampaignIdempotencyKeyStableAcrossRetries(t *testing.T) {
+	var seenKeys []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seenKeys = append(seenKeys, r.Header.Get(headerIdempotencyKey))
+		if len(seenKeys) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		body, err := json.Marshal(leaderboardCampaignResponse{Id: "testId"})
+		assert.NoError(t, err)
+		_, err = w.Write(body)
+		assert.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	backend := testWebflowBackend(ts.URL)
+	backend.http = newRetryingClient(&http.Client{}, nil, RetryConfig{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}, backend.logger)
+
+	_, err := backend.CreateCampaign(context.Background(), &types.CampaignStruct{Name: "myCampaign"}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(seenKeys))
+	assert.NotEmpty(t, seenKeys[0])
+	assert.Equal(t, seenKeys[0], seenKeys[1])
+	assert.Equal(t, seenKeys[0], seenKeys[2])
+}
+
+func TestWebflowBackendCreateCampaignIdempotencyKeyDiffersAcrossCalls(t *testing.T) {
+	var seenKeys []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seenKeys = append(seenKeys, r.Header.Get(headerIdempotencyKey))
+		w.WriteHeader(http.StatusOK)
+		body, err := json.Marshal(leaderboardCampaignResponse{Id: "testId"})
+		assert.NoError(t, err)
+		_, err = w.Write(body)
+		assert.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	backend := testWebflowBackend(ts.URL)
+	_, err := backend.CreateCampaign(context.Background(), &types.CampaignStruct{Name: "myCampaign"}, true)
+	assert.NoError(t, err)
+	_, err = backend.CreateCampaign(context.Background(), &types.CampaignStruct{Name: "myCampaign"}, true)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 2, len(seenKeys))
+	assert.NotEqual(t, seenKeys[0], seenKeys[1])
+}
+
+func TestWebflowBackendUpdateScoreSetsIdempotencyKey(t *testing.T) {
+	var key string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key = r.Header.Get(headerIdempotencyKey)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	backend := testWebflowBackend(ts.URL)
+	assert.NoError(t, backend.UpdateScore(context.Background(), "someCampaign", "theParticipantUpstreamId", 42))
+	assert.NotEmpty(t, key)
+}
+
+func TestWebflowBackendDeleteParticipant(t *testing.T) {
+	fake := newFakeUpstreamClient()
+	upstreamId, err := fake.UpsertParticipant(context.Background(), ParticipantDoc{LoginName: "theLoginName"})
+	assert.NoError(t, err)
+
+	backend := testWebflowBackendWithFakeItems("", fake)
+	assert.NoError(t, backend.DeleteParticipant(context.Background(), upstreamId))
+	_, stillPresent := fake.Participants[upstreamId]
+	assert.False(t, stillPresent)
+}
+
+func TestWebflowBackendDeleteParticipantWithUpstreamDeleteError(t *testing.T) {
+	fake := newFakeUpstreamClient()
+	fake.DeleteErr = &CreateError{MsgPattern: msgPatternDeleteErrorParticipant, Status: "500 Internal Server Error"}
+	backend := testWebflowBackendWithFakeItems("", fake)
+
+	err := backend.DeleteParticipant(context.Background(), "theParticipantUpstreamId")
+	assert.EqualError(t, err, fake.DeleteErr.Error())
+}
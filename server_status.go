@@ -0,0 +1,116 @@
+//
+// Copyright 2021-present Sonatype Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/sonatype-nexus-community/bbash/internal/status"
+	"github.com/sonatype-nexus-community/bbash/internal/types"
+)
+
+// envDashboardBaseURL is the base URL of the campaign leaderboard dashboard a reported commit
+// status's TargetURL points into; empty means no dashboard is configured, so TargetURL is omitted.
+const envDashboardBaseURL = "DASHBOARD_BASE_URL"
+
+// statusQueues holds one status.Queue per registered source_control_provider.Kind, built lazily
+// the first time that kind is reported through, since each status.StatusReporter is stateless and
+// safe to share across every campaign/participant using that kind.
+var statusQueues = map[string]*status.Queue{}
+
+// statusQueueForKind returns the status.Queue for kind, building and caching one the first time
+// it's asked for.
+func statusQueueForKind(kind string) (queue *status.Queue, ok bool) {
+	if queue, ok = statusQueues[kind]; ok {
+		return
+	}
+	reporter, ok := status.ReporterForKind(kind)
+	if !ok {
+		return nil, false
+	}
+	queue = status.NewQueue(reporter, logger)
+	statusQueues[kind] = queue
+	return queue, true
+}
+
+// reportCampaignStatus posts a commit status describing newPoints and the participant's updated
+// rank back to the source control provider msg came from, if campaign.ReportStatus is enabled and
+// msg carries a commit to attach the status to. Failures are logged, not returned: a forge being
+// unreachable shouldn't fail the score update that triggered the report.
+func reportCampaignStatus(campaign *types.CampaignStruct, participant *types.ParticipantStruct, msg *types.ScoringMessage, newPoints float64) {
+	if !campaign.ReportStatus || msg.MergeSHA == "" {
+		return
+	}
+
+	scp, err := postgresDB.GetSourceControlProvider(participant.ScpName)
+	if err != nil || scp == nil || scp.ID == "" {
+		logger.Error("reportCampaignStatus: no source control provider", zap.String("scpName", participant.ScpName), zap.Error(err))
+		return
+	}
+
+	queue, ok := statusQueueForKind(scp.Kind)
+	if !ok {
+		// no reporter for this kind (or it has none registered): nothing to post to
+		return
+	}
+
+	rank, rankErr := participantRank(campaign.Name, participant.LoginName)
+	description := fmt.Sprintf("+%.0f points", newPoints)
+	if rankErr == nil {
+		description = fmt.Sprintf("%s, now rank #%d in %s", description, rank, campaign.Name)
+	}
+
+	var targetURL string
+	if base := os.Getenv(envDashboardBaseURL); base != "" {
+		targetURL = fmt.Sprintf("%s/campaign/%s", strings.TrimSuffix(base, "/"), campaign.Name)
+	}
+
+	commitStatus := types.CommitStatusStruct{
+		RepoOwner:   msg.RepoOwner,
+		RepoName:    msg.RepoName,
+		SHA:         msg.MergeSHA,
+		State:       "success",
+		TargetURL:   targetURL,
+		Description: description,
+		Context:     fmt.Sprintf("bbash/%s", campaign.Name),
+	}
+
+	queue.Enqueue(context.Background(), scp, commitStatus)
+}
+
+// participantRank returns loginName's 1-based rank by score within campaignName's leaderboard.
+func participantRank(campaignName, loginName string) (rank int, err error) {
+	participants, err := postgresDB.SelectParticipantsInCampaign(campaignName)
+	if err != nil {
+		return
+	}
+	sort.SliceStable(participants, func(i, j int) bool {
+		return participants[i].Score > participants[j].Score
+	})
+	for i, p := range participants {
+		if p.LoginName == loginName {
+			return i + 1, nil
+		}
+	}
+	return 0, fmt.Errorf("participant not found in campaign leaderboard: %s", loginName)
+}
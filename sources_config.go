@@ -0,0 +1,105 @@
+//
+// Copyright (c) 2021-present Sonatype, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/sonatype-nexus-community/bbash/internal/poll"
+	"gopkg.in/yaml.v3"
+)
+
+// envSourcesConfigPath points at an optional YAML file describing additional poll.ScoringSources
+// to acquire scoring events from, beyond the built-in "datadog" and "ingest" sources newPollSource
+// always configures. See sourceConfig for the supported shape.
+const envSourcesConfigPath = "SOURCES_CONFIG_PATH"
+
+// envIngestSigningSecret, when set, requires the "ingest" webhook source's requests to carry a
+// valid IngestSignatureHeader HMAC, rather than accepting any request unsigned.
+const envIngestSigningSecret = "SCORING_INGEST_SECRET"
+
+const sourceNameDatadog = "datadog"
+const sourceNameIngest = "ingest"
+
+// sourcesConfig is the root of the YAML file at envSourcesConfigPath.
+type sourcesConfig struct {
+	Sources []sourceConfig `yaml:"sources"`
+}
+
+// sourceConfig describes one additional poll.ScoringSource. Which fields apply depends on Type:
+//   - "webhook":      Secret (optional)
+//   - "tail":         Path
+//   - "redis-stream": Addr, StreamKey
+//   - "kafka":        Brokers, Topic (Fetch always errors; see poll.KafkaSource)
+//   - "journald":     Unit (optional; empty reads the whole journal)
+//   - "docker":       Container
+//   - "loki":         none (configured via LOKI_BASE_URL/LOKI_QUERY; see poll.LokiSource)
+//   - "cloudwatch":   LogGroup (Fetch always errors; see poll.CloudWatchSource)
+type sourceConfig struct {
+	Name      string   `yaml:"name"`
+	Type      string   `yaml:"type"`
+	Secret    string   `yaml:"secret,omitempty"`
+	Path      string   `yaml:"path,omitempty"`
+	Addr      string   `yaml:"addr,omitempty"`
+	StreamKey string   `yaml:"streamKey,omitempty"`
+	Brokers   []string `yaml:"brokers,omitempty"`
+	Topic     string   `yaml:"topic,omitempty"`
+	Unit      string   `yaml:"unit,omitempty"`
+	Container string   `yaml:"container,omitempty"`
+	LogGroup  string   `yaml:"logGroup,omitempty"`
+}
+
+// build constructs the poll.ScoringSource sc describes.
+func (sc sourceConfig) build() (source poll.ScoringSource, err error) {
+	switch sc.Type {
+	case "webhook":
+		if sc.Secret != "" {
+			source = poll.NewSignedWebhookSource(sc.Secret)
+		} else {
+			source = poll.NewWebhookSource()
+		}
+	case "tail":
+		source = poll.NewTailSource(sc.Path)
+	case "redis-stream":
+		source = poll.NewRedisStreamSource(sc.Addr, sc.StreamKey)
+	case "kafka":
+		source = poll.NewKafkaSource(sc.Brokers, sc.Topic)
+	case "journald":
+		source = poll.NewJournaldSource(sc.Unit)
+	case "docker":
+		source = poll.NewDockerSource(sc.Container)
+	case "loki":
+		source = poll.NewLokiSource()
+	case "cloudwatch":
+		source = poll.NewCloudWatchSource(sc.LogGroup)
+	default:
+		err = fmt.Errorf("sources config: unsupported source type %q for source %q", sc.Type, sc.Name)
+	}
+	return
+}
+
+// loadSourcesConfig reads and parses the YAML file at path.
+func loadSourcesConfig(path string) (cfg sourcesConfig, err error) {
+	var raw []byte
+	raw, err = os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	err = yaml.Unmarshal(raw, &cfg)
+	return
+}